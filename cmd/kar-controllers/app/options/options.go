@@ -25,21 +25,43 @@ import (
 
 // ServerOption is the main context object for the controller manager.
 type ServerOption struct {
-	Master          string
-	Kubeconfig      string
-	Dispatcher      bool
-	AgentConfigs    string
-	SecurePort      int
-	DynamicPriority bool // If DynamicPriority=true then no preemption is allowed by program logic
-	Preemption      bool // Preemption is not allowed under DynamicPriority
-	BackoffTime     int  // Number of seconds a job will go away for, if it can not be scheduled.  Default is 20.
+	Master         string
+	Kubeconfig     string
+	Dispatcher     bool
+	AgentConfigs   string
+	AgentSelection string // Policy used to rank agent clusters in dispatcher mode
+	// Consecutive failures after which an agent cluster is unhealthy, and seconds it can stay unhealthy
+	// before its AppWrappers are requeued.
+	AgentFailureThreshold int
+	AgentLostGracePeriod  int
+	CapacityReportPeriod  int // Seconds between the capacity reports published in agent mode, 0 disables them
+	SecurePort            int
+	DynamicPriority       bool // If DynamicPriority=true then no preemption is allowed by program logic
+	Preemption            bool // Preemption is not allowed under DynamicPriority
+	BackoffTime           int  // Number of seconds a job will go away for, if it can not be scheduled.  Default is 20.
 	// Head of line job will not be bumped away for at least HeadOfLineHoldingTime seconds by higher priority jobs.
 	// Default setting to 0 disables this mechanism.
 	HeadOfLineHoldingTime              int
 	QuotaEnabled                       bool // Controller is to evaluate quota per request
 	QuotaRestURL                       string
 	HealthProbeListenAddr              string
+	DebugAPIEnabled                    bool // Serve the read-only debug API on the health probe listener
+	MetricsListenAddr                  string
 	DispatchResourceReservationTimeout int64
+	// Concurrency limits on dispatched AppWrappers. A value of 0 disables the limit.
+	MaxAppWrappersPerNamespace int
+	MaxPodsPerNamespace        int
+	MaxAppWrappersPerUser      int
+	MaxPodsPerUser             int
+	UserAnnotation             string // AppWrapper annotation recording the submitting user
+	// OpenTelemetry tracing of the dispatch path, disabled when TracingExporter is empty
+	TracingExporter    string  // otlp or file
+	TracingEndpoint    string  // Address of the OTLP gRPC collector
+	TracingInsecure    bool    // Connect to the OTLP collector without TLS
+	TracingFile        string  // Path of the JSON lines file written by the file exporter
+	TracingSampleRatio float64 // Fraction of the dispatch attempts traced
+	// Path of the file configuring the JSONPath resource extractors of in-house generic item kinds
+	ResourceExtractorsConfig string
 }
 
 // NewServerOption creates a new CMServer with a default config.
@@ -56,7 +78,11 @@ func (s *ServerOption) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&s.Master, "master", s.Master, "The address of the Kubernetes API server (overrides any value in kubeconfig)")
 	fs.StringVar(&s.Kubeconfig, "kubeconfig", s.Kubeconfig, "Path to kubeconfig file with authorization and master location information.")
 	fs.BoolVar(&s.Dispatcher, "dispatcher", s.Dispatcher, "set dispatcher mode(true) or agent mode(false)")
-	fs.StringVar(&s.AgentConfigs, "agentconfigs", s.AgentConfigs, "Comma-separated paths to agent config file:deploymentName")
+	fs.StringVar(&s.AgentConfigs, "agentconfigs", s.AgentConfigs, "Comma-separated paths to agent config file:deploymentName[:labels], where labels are semicolon-separated key=value pairs")
+	fs.StringVar(&s.AgentSelection, "agentSelectionPolicy", s.AgentSelection, "Policy used to rank agent clusters in dispatcher mode: BestFit, Spread or LeastLoaded.  Default is LeastLoaded.")
+	fs.IntVar(&s.AgentFailureThreshold, "agentFailureThreshold", s.AgentFailureThreshold, "Number of consecutive metric or watch failures after which an agent cluster is marked unhealthy.  Default is 3.")
+	fs.IntVar(&s.AgentLostGracePeriod, "agentLostGracePeriod", s.AgentLostGracePeriod, "Number of seconds an agent cluster can stay unhealthy before the AppWrappers dispatched to it are requeued.  Default is 120.")
+	fs.IntVar(&s.CapacityReportPeriod, "capacityReportPeriod", s.CapacityReportPeriod, "Number of seconds between the capacity reports published for the dispatcher in agent mode, 0 disables them.  Default is 30.")
 	fs.BoolVar(&s.DynamicPriority, "dynamicpriority", s.DynamicPriority, "If true, set controller to use dynamic priority. If false, set controller to use static priority.  Default is false.")
 	fs.BoolVar(&s.Preemption, "preemption", s.Preemption, "Set controller to allow preemption if set to true. Note: when set to true, the Kubernetes Scheduler must be configured to enable preemption.  Default is false.")
 	fs.IntVar(&s.BackoffTime, "backofftime", s.BackoffTime, "Number of seconds a job will go away for, if it can not be scheduled.  Default is 20.")
@@ -65,12 +91,29 @@ func (s *ServerOption) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&s.QuotaRestURL, "quotaURL", s.QuotaRestURL, "URL for ReST quota management.  Default is none.")
 	fs.IntVar(&s.SecurePort, "secure-port", 6443, "The port on which to serve secured, authenticated access for metrics.")
 	fs.StringVar(&s.HealthProbeListenAddr, "healthProbeListenAddr", ":8081", "Listen address for health probes. Defaults to ':8081'")
+	fs.BoolVar(&s.DebugAPIEnabled, "debugAPIEnabled", s.DebugAPIEnabled, "Serve the read-only JSON debug API under /debug/ on the health probe listener.  Default is false.")
+	fs.StringVar(&s.MetricsListenAddr, "metricsListenAddr", ":8082", "Listen address for Prometheus metrics, empty to disable them. Defaults to ':8082'")
+	fs.IntVar(&s.MaxAppWrappersPerNamespace, "maxAppWrappersPerNamespace", s.MaxAppWrappersPerNamespace, "Maximum number of AppWrappers dispatched at the same time per namespace.  Default is 0 (no limit).")
+	fs.IntVar(&s.MaxPodsPerNamespace, "maxPodsPerNamespace", s.MaxPodsPerNamespace, "Maximum number of pods of AppWrappers dispatched at the same time per namespace.  Default is 0 (no limit).")
+	fs.IntVar(&s.MaxAppWrappersPerUser, "maxAppWrappersPerUser", s.MaxAppWrappersPerUser, "Maximum number of AppWrappers dispatched at the same time per submitting user.  Default is 0 (no limit).")
+	fs.IntVar(&s.MaxPodsPerUser, "maxPodsPerUser", s.MaxPodsPerUser, "Maximum number of pods of AppWrappers dispatched at the same time per submitting user.  Default is 0 (no limit).")
+	fs.StringVar(&s.UserAnnotation, "userAnnotation", s.UserAnnotation, "AppWrapper annotation recording the submitting user, used for per-user concurrency limits.  Default is 'workload.codeflare.dev/user'.")
+	fs.StringVar(&s.TracingExporter, "tracingExporter", s.TracingExporter, "Exporter of the traces of the dispatch path: otlp or file.  Default is none (tracing disabled).")
+	fs.StringVar(&s.TracingEndpoint, "tracingEndpoint", s.TracingEndpoint, "Address of the OTLP gRPC collector receiving the traces.  Default is the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or 'localhost:4317'.")
+	fs.BoolVar(&s.TracingInsecure, "tracingInsecure", s.TracingInsecure, "Connect to the OTLP collector without TLS.  Default is false.")
+	fs.StringVar(&s.TracingFile, "tracingFile", s.TracingFile, "Path of the JSON lines file written by the file trace exporter.  Default is 'mcad-traces.jsonl'.")
+	fs.Float64Var(&s.TracingSampleRatio, "tracingSampleRatio", s.TracingSampleRatio, "Fraction of the AppWrapper dispatch attempts traced, between 0 and 1.  Default is 1.")
+	fs.StringVar(&s.ResourceExtractorsConfig, "resourceExtractorsConfig", s.ResourceExtractorsConfig, "Path of the YAML file configuring the JSONPath resource extractors of in-house generic item kinds.  Default is none.")
 	fs.Int64Var(&s.DispatchResourceReservationTimeout, "dispatchResourceReservationTimeout", s.DispatchResourceReservationTimeout, "Resource reservation timeout for pods to be created once AppWrapper is dispatched, in millisecond.  Defaults to '300000', 5 minutes")
 }
 
 func (s *ServerOption) loadDefaultsFromEnvVars() {
 	// Set defaults via environment variables
 	s.AgentConfigs = os.Getenv("DISPATCHER_AGENT_CONFIGS")
+	s.AgentSelection = os.Getenv("DISPATCHER_AGENT_SELECTION_POLICY")
+	s.AgentFailureThreshold = intFromEnvVar("DISPATCHER_AGENT_FAILURE_THRESHOLD", 3)
+	s.AgentLostGracePeriod = intFromEnvVar("DISPATCHER_AGENT_LOST_GRACE_PERIOD", 120)
+	s.CapacityReportPeriod = intFromEnvVar("AGENT_CAPACITY_REPORT_PERIOD", 30)
 	dispatcherMode, envVarExists := os.LookupEnv("DISPATCHER_MODE")
 
 	s.Dispatcher = false
@@ -128,4 +171,45 @@ func (s *ServerOption) loadDefaultsFromEnvVars() {
 			s.DispatchResourceReservationTimeout = to
 		}
 	}
+
+	s.MaxAppWrappersPerNamespace = intFromEnvVar("MAX_APPWRAPPERS_PER_NAMESPACE", 0)
+	s.MaxPodsPerNamespace = intFromEnvVar("MAX_PODS_PER_NAMESPACE", 0)
+	s.MaxAppWrappersPerUser = intFromEnvVar("MAX_APPWRAPPERS_PER_USER", 0)
+	s.MaxPodsPerUser = intFromEnvVar("MAX_PODS_PER_USER", 0)
+
+	userAnnotation, envVarExists := os.LookupEnv("USER_ANNOTATION")
+	s.UserAnnotation = "workload.codeflare.dev/user"
+	if envVarExists {
+		s.UserAnnotation = userAnnotation
+	}
+
+	debugAPIEnabled, envVarExists := os.LookupEnv("DEBUG_API_ENABLED")
+	s.DebugAPIEnabled = envVarExists && strings.EqualFold(debugAPIEnabled, "true")
+
+	s.TracingExporter = os.Getenv("TRACING_EXPORTER")
+	s.TracingEndpoint = os.Getenv("TRACING_ENDPOINT")
+	tracingInsecure, envVarExists := os.LookupEnv("TRACING_INSECURE")
+	s.TracingInsecure = envVarExists && strings.EqualFold(tracingInsecure, "true")
+	s.TracingFile = os.Getenv("TRACING_FILE")
+	if s.TracingFile == "" {
+		s.TracingFile = "mcad-traces.jsonl"
+	}
+	tracingSampleRatio, envVarExists := os.LookupEnv("TRACING_SAMPLE_RATIO")
+	s.TracingSampleRatio = 1
+	if envVarExists {
+		if ratio, err := strconv.ParseFloat(tracingSampleRatio, 64); err == nil {
+			s.TracingSampleRatio = ratio
+		}
+	}
+
+	s.ResourceExtractorsConfig = os.Getenv("RESOURCE_EXTRACTORS_CONFIG")
+}
+
+func intFromEnvVar(name string, defaultValue int) int {
+	if value, envVarExists := os.LookupEnv(name); envVarExists {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
 }
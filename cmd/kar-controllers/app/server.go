@@ -17,6 +17,7 @@ limitations under the License.
 package app
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -24,11 +25,15 @@ import (
 
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/cmd/kar-controllers/app/options"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/metrics"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejob"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobresources/genericresource"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/tracing"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/health"
 )
 
@@ -47,6 +52,24 @@ func Run(opt *options.ServerOption) error {
 
 	neverStop := make(chan struct{})
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    opt.TracingExporter,
+		Endpoint:    opt.TracingEndpoint,
+		Insecure:    opt.TracingInsecure,
+		File:        opt.TracingFile,
+		SampleRatio: opt.TracingSampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	if opt.ResourceExtractorsConfig != "" {
+		if err := genericresource.LoadResourceExtractors(opt.ResourceExtractorsConfig); err != nil {
+			return err
+		}
+	}
+
 	restConfig.QPS = 100.0
 	restConfig.Burst = 200.0
 
@@ -56,10 +79,21 @@ func Run(opt *options.ServerOption) error {
 		BackoffTime:           pointer.Int32(int32(opt.BackoffTime)),
 		HeadOfLineHoldingTime: pointer.Int32(int32(opt.HeadOfLineHoldingTime)),
 		QuotaEnabled:          &opt.QuotaEnabled,
+		ConcurrencyLimits: &config.ConcurrencyLimits{
+			MaxAppWrappersPerNamespace: pointer.Int32(int32(opt.MaxAppWrappersPerNamespace)),
+			MaxPodsPerNamespace:        pointer.Int32(int32(opt.MaxPodsPerNamespace)),
+			MaxAppWrappersPerUser:      pointer.Int32(int32(opt.MaxAppWrappersPerUser)),
+			MaxPodsPerUser:             pointer.Int32(int32(opt.MaxPodsPerUser)),
+			UserAnnotation:             opt.UserAnnotation,
+		},
 	}
 	extConfig := &config.MCADConfigurationExtended{
-		Dispatcher:   pointer.Bool(opt.Dispatcher),
-		AgentConfigs: strings.Split(opt.AgentConfigs, ","),
+		Dispatcher:            pointer.Bool(opt.Dispatcher),
+		AgentConfigs:          strings.Split(opt.AgentConfigs, ","),
+		AgentSelectionPolicy:  pointer.String(opt.AgentSelection),
+		AgentFailureThreshold: pointer.Int32(int32(opt.AgentFailureThreshold)),
+		AgentLostGracePeriod:  pointer.Int32(int32(opt.AgentLostGracePeriod)),
+		CapacityReportPeriod:  pointer.Int32(int32(opt.CapacityReportPeriod)),
 	}
 
 	jobctrl := queuejob.NewJobController(restConfig, mcadConfig, extConfig)
@@ -68,8 +102,12 @@ func Run(opt *options.ServerOption) error {
 	}
 	jobctrl.Run(neverStop)
 
+	if opt.MetricsListenAddr != "" {
+		go listenMetrics(opt)
+	}
+
 	// This call is blocking (unless an error occurs) which equates to <-neverStop
-	err = listenHealthProbe(opt)
+	err = listenHealthProbe(opt, jobctrl)
 	if err != nil {
 		return err
 	}
@@ -77,10 +115,22 @@ func Run(opt *options.ServerOption) error {
 	return nil
 }
 
-// Starts the health probe listener
-func listenHealthProbe(opt *options.ServerOption) error {
+// Starts the metrics listener
+func listenMetrics(opt *options.ServerOption) {
+	handler := http.NewServeMux()
+	handler.Handle("/metrics", metrics.Handler())
+	if err := http.ListenAndServe(opt.MetricsListenAddr, handler); err != nil {
+		klog.Errorf("[listenMetrics] Failed to serve metrics on %s, err=%v", opt.MetricsListenAddr, err)
+	}
+}
+
+// Starts the health probe listener, serving the debug API when enabled
+func listenHealthProbe(opt *options.ServerOption, jobctrl *queuejob.XController) error {
 	handler := http.NewServeMux()
 	handler.Handle("/healthz", &health.Handler{})
+	if opt.DebugAPIEnabled {
+		handler.Handle("/debug/", jobctrl.DebugHandler())
+	}
 	err := http.ListenAndServe(opt.HealthProbeListenAddr, handler)
 	if err != nil {
 		return err
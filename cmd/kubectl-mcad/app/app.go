@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package app implements kubectl-mcad, a kubectl plugin to inspect the queue, the quota trees and the AppWrappers
+// of the multi-cluster app dispatcher.
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/clientset/versioned"
+)
+
+// Options are the global options of the plugin
+type Options struct {
+	Kubeconfig    string
+	Context       string
+	Namespace     string
+	AllNamespaces bool
+}
+
+// NewOptions creates the default options
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// AddFlags adds the global flags to the specified FlagSet
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file.  Default is the kubectl configuration.")
+	fs.StringVar(&o.Context, "context", o.Context, "Name of the kubeconfig context to use.")
+	fs.StringVar(&o.Namespace, "n", o.Namespace, "Namespace of the AppWrappers.  Default is the namespace of the kubeconfig context.")
+	fs.StringVar(&o.Namespace, "namespace", o.Namespace, "Namespace of the AppWrappers.  Default is the namespace of the kubeconfig context.")
+	fs.BoolVar(&o.AllNamespaces, "A", o.AllNamespaces, "Show the AppWrappers of all the namespaces.")
+	fs.BoolVar(&o.AllNamespaces, "all-namespaces", o.AllNamespaces, "Show the AppWrappers of all the namespaces.")
+}
+
+// Usage prints the usage of the plugin
+func Usage(out io.Writer) {
+	fmt.Fprint(out, `Inspect the multi-cluster app dispatcher.
+
+Usage:
+  kubectl mcad [flags] queue                     List the pending AppWrappers in scheduling order
+  kubectl mcad [flags] quota tree                Render the quota trees with their quota, usage and borrowing
+  kubectl mcad [flags] why <appwrapper>          Explain why an AppWrapper is not running
+  kubectl mcad [flags] simulate -f <file>        Predict whether an AppWrapper fits in the quota trees
+
+Flags:
+  --kubeconfig string      Path to the kubeconfig file
+  --context string         Name of the kubeconfig context to use
+  -n, --namespace string   Namespace of the AppWrappers
+  -A, --all-namespaces     Show the AppWrappers of all the namespaces
+`)
+}
+
+// Run runs a subcommand of the plugin, given with its arguments
+func Run(o *Options, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		Usage(out)
+		return fmt.Errorf("missing command")
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case "queue":
+		return runQueue(o, args, out)
+	case "quota":
+		return runQuota(o, args, out)
+	case "why":
+		return runWhy(o, args, out)
+	case "simulate":
+		return runSimulate(o, args, out)
+	case "help":
+		Usage(out)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, see 'kubectl mcad help'", command)
+	}
+}
+
+// parseFlags parses the arguments of a subcommand and returns its positional arguments. The global flags are accepted
+// after the subcommand, and flags may follow positional arguments as with kubectl.
+func (o *Options) parseFlags(command string, args []string, out io.Writer, addFlags func(fs *flag.FlagSet)) ([]string, error) {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(out)
+	o.AddFlags(fs)
+	if addFlags != nil {
+		addFlags(fs)
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// clientConfig returns the REST configuration and the namespace selected by the options and the kubeconfig
+func (o *Options) clientConfig() (*rest.Config, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+	overrides.Context.Namespace = o.Namespace
+	config := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	restConfig, err := config.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	namespace, _, err := config.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+	return restConfig, namespace, nil
+}
+
+// clientset returns the clientset of the AppWrappers and the QuotaSubtrees and the selected namespace
+func (o *Options) clientset() (versioned.Interface, *rest.Config, string, error) {
+	restConfig, namespace, err := o.clientConfig()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	client, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return client, restConfig, namespace, nil
+}
+
+// age formats the time elapsed since t, "<unknown>" when t is zero
+func age(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(t))
+}
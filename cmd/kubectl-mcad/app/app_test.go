@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParseFlags(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	o := NewOptions()
+	var filename string
+	positional, err := o.parseFlags("why", []string{"my-aw", "-n", "team-a", "-A", "-f", "aw.yaml"}, &bytes.Buffer{}, nil)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(positional).To(gomega.BeNil())
+
+	positional, err = o.parseFlags("why", []string{"my-aw", "-n", "team-a", "-A"}, &bytes.Buffer{}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(positional).To(gomega.Equal([]string{"my-aw"}))
+	g.Expect(o.Namespace).To(gomega.Equal("team-a"))
+	g.Expect(o.AllNamespaces).To(gomega.BeTrue())
+
+	positional, err = NewOptions().parseFlags("simulate", []string{"--filename", "aw.yaml"}, &bytes.Buffer{},
+		func(fs *flag.FlagSet) { fs.StringVar(&filename, "filename", "", "") })
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(positional).To(gomega.BeEmpty())
+	g.Expect(filename).To(gomega.Equal("aw.yaml"))
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	out := &bytes.Buffer{}
+	g.Expect(Run(NewOptions(), nil, out)).To(gomega.HaveOccurred())
+	g.Expect(out.String()).To(gomega.ContainSubstring("Usage:"))
+	g.Expect(Run(NewOptions(), []string{"dispatch"}, out)).To(gomega.MatchError(gomega.ContainSubstring("unknown command")))
+	g.Expect(Run(NewOptions(), []string{"queue", "extra"}, out)).To(gomega.MatchError(gomega.ContainSubstring("unexpected arguments")))
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejob"
+)
+
+// isPending returns whether an AppWrapper is waiting for dispatch
+func isPending(aw *arbv1.AppWrapper) bool {
+	return aw.DeletionTimestamp == nil && !aw.Status.CanRun &&
+		(aw.Status.State == arbv1.AppWrapperStateEnqueued || aw.Status.State == "")
+}
+
+// pendingAppWrappers returns the pending AppWrappers in the scheduling order of the controller
+func pendingAppWrappers(aws []arbv1.AppWrapper) []*arbv1.AppWrapper {
+	var pending []*arbv1.AppWrapper
+	for i := range aws {
+		if isPending(&aws[i]) {
+			pending = append(pending, &aws[i])
+		}
+	}
+	queuejob.SortBySchedulingOrder(pending)
+	return pending
+}
+
+// pendingReason returns the reason an AppWrapper is pending: the reason of its latest failed attempt to dispatch,
+// else the reason of its last backoff, else its queueing state
+func pendingReason(aw *arbv1.AppWrapper) string {
+	if aw.Status.SchedulingDiagnostics != nil && aw.Status.SchedulingDiagnostics.Reason != "" {
+		return aw.Status.SchedulingDiagnostics.Reason
+	}
+	if backoff := lastBackoff(aw); backoff != nil {
+		return backoff.Reason
+	}
+	if aw.Status.QueueJobState != "" {
+		return string(aw.Status.QueueJobState)
+	}
+	return "<none>"
+}
+
+// lastBackoff returns the last backoff condition of an AppWrapper, nil if it was never backed off
+func lastBackoff(aw *arbv1.AppWrapper) *arbv1.AppWrapperCondition {
+	for i := len(aw.Status.Conditions) - 1; i >= 0; i-- {
+		if aw.Status.Conditions[i].Type == arbv1.AppWrapperCondBackoff {
+			return &aw.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// arrivalTime returns the time an AppWrapper was first seen by the controller, falling back to its creation time
+func arrivalTime(aw *arbv1.AppWrapper) time.Time {
+	if !aw.Status.ControllerFirstTimestamp.IsZero() {
+		return aw.Status.ControllerFirstTimestamp.Time
+	}
+	return aw.CreationTimestamp.Time
+}
+
+// estimatedDispatch formats the estimated dispatch time of an AppWrapper published by the controller
+func estimatedDispatch(aw *arbv1.AppWrapper, now time.Time) string {
+	if aw.Status.QueuePosition == nil || aw.Status.QueuePosition.EstimatedDispatchTime == nil {
+		return "<unknown>"
+	}
+	eta := aw.Status.QueuePosition.EstimatedDispatchTime.Time
+	if !eta.After(now) {
+		return "now"
+	}
+	return "in " + age(now, eta)
+}
+
+// printQueue prints the pending AppWrappers, given in scheduling order, of the namespace, or of all the namespaces
+// when the namespace is empty
+func printQueue(out io.Writer, pending []*arbv1.AppWrapper, namespace string, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tNAMESPACE\tNAME\tPRIORITY\tAGE\tDISPATCH\tREASON")
+	for i, aw := range pending {
+		if namespace != "" && aw.Namespace != namespace {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%g\t%s\t%s\t%s\n", i+1, aw.Namespace, aw.Name, aw.Status.SystemPriority,
+			age(arrivalTime(aw), now), estimatedDispatch(aw, now), pendingReason(aw))
+	}
+	return w.Flush()
+}
+
+func runQueue(o *Options, args []string, out io.Writer) error {
+	positional, err := o.parseFlags("queue", args, out, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return fmt.Errorf("unexpected arguments %v", positional)
+	}
+	client, _, namespace, err := o.clientset()
+	if err != nil {
+		return err
+	}
+	// The positions are global, all the AppWrappers are listed before filtering the namespace
+	aws, err := client.WorkloadV1beta1().AppWrappers("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if o.AllNamespaces {
+		namespace = ""
+	}
+	return printQueue(out, pendingAppWrappers(aws.Items), namespace, time.Now())
+}
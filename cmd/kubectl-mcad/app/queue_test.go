@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func appWrapper(namespace string, name string, priority float64, arrival time.Time) arbv1.AppWrapper {
+	return arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: arbv1.AppWrapperStatus{
+			State:                    arbv1.AppWrapperStateEnqueued,
+			SystemPriority:           priority,
+			ControllerFirstTimestamp: metav1.NewMicroTime(arrival),
+		},
+	}
+}
+
+func TestPendingAppWrappers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	running := appWrapper("team-a", "running", 10, now)
+	running.Status.CanRun = true
+	running.Status.State = arbv1.AppWrapperStateActive
+	aws := []arbv1.AppWrapper{
+		appWrapper("team-a", "late", 1, now.Add(-time.Minute)),
+		running,
+		appWrapper("team-b", "urgent", 5, now),
+		appWrapper("team-b", "early", 1, now.Add(-time.Hour)),
+	}
+
+	var names []string
+	for _, aw := range pendingAppWrappers(aws) {
+		names = append(names, aw.Name)
+	}
+	g.Expect(names).To(gomega.Equal([]string{"urgent", "early", "late"}))
+}
+
+func TestPendingReason(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	aw := appWrapper("team-a", "aw", 1, time.Now())
+	g.Expect(pendingReason(&aw)).To(gomega.Equal("<none>"))
+
+	aw.Status.QueueJobState = arbv1.AppWrapperCondQueueing
+	g.Expect(pendingReason(&aw)).To(gomega.Equal(string(arbv1.AppWrapperCondQueueing)))
+
+	aw.Status.Conditions = []arbv1.AppWrapperCondition{
+		{Type: arbv1.AppWrapperCondBackoff, Reason: "AppWrapperNotRunnable"},
+		{Type: arbv1.AppWrapperCondQueueing, Reason: "AwaitingHeadOfLine"},
+	}
+	g.Expect(pendingReason(&aw)).To(gomega.Equal("AppWrapperNotRunnable"))
+
+	aw.Status.SchedulingDiagnostics = &arbv1.SchedulingDiagnostics{Reason: "InsufficientQuota"}
+	g.Expect(pendingReason(&aw)).To(gomega.Equal("InsufficientQuota"))
+}
+
+func TestPrintQueue(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	aws := []arbv1.AppWrapper{
+		appWrapper("team-a", "first", 5, now.Add(-2*time.Hour)),
+		appWrapper("team-b", "second", 1, now.Add(-time.Minute)),
+	}
+	eta := metav1.NewTime(now.Add(30 * time.Minute))
+	aws[1].Status.QueuePosition = &arbv1.QueuePosition{Global: 2, EstimatedDispatchTime: &eta}
+
+	out := &bytes.Buffer{}
+	g.Expect(printQueue(out, pendingAppWrappers(aws), "team-b", now)).To(gomega.Succeed())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	g.Expect(lines).To(gomega.HaveLen(2))
+	g.Expect(strings.Fields(lines[0])).To(gomega.Equal([]string{"POSITION", "NAMESPACE", "NAME", "PRIORITY", "AGE", "DISPATCH", "REASON"}))
+	g.Expect(strings.Fields(lines[1])).To(gomega.Equal([]string{"2", "team-b", "second", "1", "60s", "in", "30m", "<none>"}))
+}
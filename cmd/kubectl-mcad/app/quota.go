@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	listersv1beta1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobresources/genericresource"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota/quotaforestmanager"
+	qmutils "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/quotaplugins/util"
+)
+
+// aggregatedResources returns the resources requested by the items of an AppWrapper, as computed by the controller
+func aggregatedResources(aw *arbv1.AppWrapper) *clusterstateapi.Resource {
+	allocated := clusterstateapi.EmptyResource()
+	for _, genericItem := range aw.Spec.AggrResources.GenericItems {
+		resources, err := genericresource.GetResources(&genericItem, aw.Namespace)
+		if err != nil {
+			klog.Errorf("[aggregatedResources] Failure aggregating resources for %s/%s, err=%v", aw.Namespace, aw.Name, err)
+		}
+		allocated = allocated.Add(resources)
+	}
+	return allocated
+}
+
+// loadLimitRanges makes the demands computed by aggregatedResources apply the defaults of the live LimitRanges,
+// as the controller does
+func loadLimitRanges(restConfig *rest.Config) error {
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	limitRanges, err := client.CoreV1().LimitRanges("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for i := range limitRanges.Items {
+		if err := indexer.Add(&limitRanges.Items[i]); err != nil {
+			return err
+		}
+	}
+	genericresource.SetLimitRangeLister(corelisters.NewLimitRangeLister(indexer))
+	return nil
+}
+
+// loadQuotaForest builds the quota forest of the controller offline, from the live QuotaSubtrees and the AppWrappers
+// dispatched by the controller
+func loadQuotaForest(restConfig *rest.Config, aws []arbv1.AppWrapper, preemption bool) (*quotaforestmanager.QuotaManager, error) {
+	if err := loadLimitRanges(restConfig); err != nil {
+		return nil, err
+	}
+	demands := map[string]*clusterstateapi.Resource{}
+	dispatched := map[string]*arbv1.AppWrapper{}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for i := range aws {
+		aw := &aws[i]
+		if err := indexer.Add(aw); err != nil {
+			return nil, err
+		}
+		if aw.Status.CanRun {
+			id := qmutils.CreateId(aw.Namespace, aw.Name)
+			demands[id] = aggregatedResources(aw)
+			dispatched[id] = aw
+		}
+	}
+	mcadConfig := &config.MCADConfiguration{
+		QuotaEnabled: pointer.Bool(true),
+		Preemption:   pointer.Bool(preemption),
+	}
+	return quotaforestmanager.NewQuotaManager(demands, dispatched, listersv1beta1.NewAppWrapperLister(indexer), restConfig, mcadConfig)
+}
+
+// formatAmounts formats amounts by resource name, sorted by name, "-" when there are none
+func formatAmounts(amounts map[string]int64) string {
+	names := make([]string, 0, len(amounts))
+	for name := range amounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		formatted = append(formatted, fmt.Sprintf("%s=%d", name, amounts[name]))
+	}
+	if len(formatted) == 0 {
+		return "-"
+	}
+	return strings.Join(formatted, ",")
+}
+
+// borrowed returns the amount of each resource allocated beyond the quota of a node
+func borrowed(node quota.QuotaNodeUsage) map[string]int64 {
+	amounts := map[string]int64{}
+	for name, allocated := range node.Allocated {
+		if allocated > node.Quota[name] {
+			amounts[name] = allocated - node.Quota[name]
+		}
+	}
+	return amounts
+}
+
+// printQuotaTrees renders the nodes of the quota trees, sorted by tree and node, as trees
+func printQuotaTrees(out io.Writer, usage []quota.QuotaNodeUsage) error {
+	trees := map[string]map[string]quota.QuotaNodeUsage{}
+	var treeNames []string
+	for _, node := range usage {
+		if _, ok := trees[node.Tree]; !ok {
+			trees[node.Tree] = map[string]quota.QuotaNodeUsage{}
+			treeNames = append(treeNames, node.Tree)
+		}
+		trees[node.Tree][node.Node] = node
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	for i, treeName := range treeNames {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "TREE %s\n", treeName)
+		fmt.Fprintln(w, "NODE\tHARD\tQUOTA\tALLOCATED\tBORROWED\tCONSUMERS")
+		nodes := trees[treeName]
+		children := map[string][]string{}
+		var roots []string
+		for _, node := range usage {
+			if node.Tree != treeName {
+				continue
+			}
+			if _, ok := nodes[node.Parent]; node.Parent == "" || !ok {
+				roots = append(roots, node.Node)
+			} else {
+				children[node.Parent] = append(children[node.Parent], node.Node)
+			}
+		}
+		var printNode func(name string, prefix string, childPrefix string)
+		printNode = func(name string, prefix string, childPrefix string) {
+			node := nodes[name]
+			fmt.Fprintf(w, "%s%s\t%t\t%s\t%s\t%s\t%d\n", prefix, name, node.Hard, formatAmounts(node.Quota),
+				formatAmounts(node.Allocated), formatAmounts(borrowed(node)), len(node.Consumers))
+			for j, child := range children[name] {
+				if j == len(children[name])-1 {
+					printNode(child, childPrefix+"└── ", childPrefix+"    ")
+				} else {
+					printNode(child, childPrefix+"├── ", childPrefix+"│   ")
+				}
+			}
+		}
+		for _, root := range roots {
+			printNode(root, "", "")
+		}
+	}
+	return w.Flush()
+}
+
+func runQuota(o *Options, args []string, out io.Writer) error {
+	positional, err := o.parseFlags("quota", args, out, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || positional[0] != "tree" {
+		return fmt.Errorf("usage: kubectl mcad quota tree")
+	}
+	client, restConfig, _, err := o.clientset()
+	if err != nil {
+		return err
+	}
+	aws, err := client.WorkloadV1beta1().AppWrappers("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	forest, err := loadQuotaForest(restConfig, aws.Items, false)
+	if err != nil {
+		return err
+	}
+	return printQuotaTrees(out, forest.GetQuotaNodeUsage())
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+)
+
+func TestFormatAmounts(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(formatAmounts(map[string]int64{"memory": 512, "cpu": 2000})).To(gomega.Equal("cpu=2000,memory=512"))
+	g.Expect(formatAmounts(nil)).To(gomega.Equal("-"))
+}
+
+func TestPrintQuotaTrees(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	usage := []quota.QuotaNodeUsage{
+		{Tree: "context", Node: "default", Parent: "root", Quota: map[string]int64{"cpu": 0}, Allocated: map[string]int64{"cpu": 0}},
+		{Tree: "context", Node: "root", Hard: true, Quota: map[string]int64{"cpu": 10}, Allocated: map[string]int64{"cpu": 6}},
+		{Tree: "context", Node: "team-a", Parent: "root", Quota: map[string]int64{"cpu": 4}, Allocated: map[string]int64{"cpu": 6},
+			Consumers: []string{"team-a/aw-1", "team-a/aw-2"}},
+		{Tree: "context", Node: "team-a-dev", Parent: "team-a", Quota: map[string]int64{"cpu": 1}, Allocated: map[string]int64{"cpu": 0}},
+	}
+
+	out := &bytes.Buffer{}
+	g.Expect(printQuotaTrees(out, usage)).To(gomega.Succeed())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	g.Expect(lines).To(gomega.HaveLen(6))
+	g.Expect(lines[0]).To(gomega.Equal("TREE context"))
+	g.Expect(strings.Fields(lines[2])).To(gomega.Equal([]string{"root", "true", "cpu=10", "cpu=6", "-", "0"}))
+	g.Expect(strings.Fields(lines[3])).To(gomega.Equal([]string{"├──", "default", "false", "cpu=0", "cpu=0", "-", "0"}))
+	g.Expect(strings.Fields(lines[4])).To(gomega.Equal([]string{"└──", "team-a", "false", "cpu=4", "cpu=6", "cpu=2", "2"}))
+	g.Expect(strings.Fields(lines[5])).To(gomega.Equal([]string{"└──", "team-a-dev", "false", "cpu=1", "cpu=0", "-", "0"}))
+	g.Expect(lines[5]).To(gomega.HavePrefix("    └── team-a-dev"))
+}
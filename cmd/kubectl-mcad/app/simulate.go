@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// defaultQuotaNode is the quota tree node charged by the controller for AppWrappers without a label for the tree
+const defaultQuotaNode = "default"
+
+// readAppWrapper reads an AppWrapper from a YAML or JSON document
+func readAppWrapper(r io.Reader) (*arbv1.AppWrapper, error) {
+	aw := &arbv1.AppWrapper{}
+	if err := yaml.NewYAMLOrJSONDecoder(r, 4096).Decode(aw); err != nil {
+		return nil, err
+	}
+	if aw.Name == "" {
+		return nil, fmt.Errorf("the AppWrapper has no name")
+	}
+	return aw, nil
+}
+
+// setDefaultQuotaLabels charges an AppWrapper to the default node of the quota trees it has no label for, as the
+// controller does before the quota evaluation
+func setDefaultQuotaLabels(aw *arbv1.AppWrapper, trees []string) {
+	for _, tree := range trees {
+		if _, ok := aw.Labels[tree]; !ok {
+			if aw.Labels == nil {
+				aw.Labels = map[string]string{}
+			}
+			aw.Labels[tree] = defaultQuotaNode
+		}
+	}
+}
+
+func runSimulate(o *Options, args []string, out io.Writer) error {
+	var filename string
+	var preemption bool
+	positional, err := o.parseFlags("simulate", args, out, func(fs *flag.FlagSet) {
+		fs.StringVar(&filename, "f", "", "File containing the AppWrapper, - for the standard input.")
+		fs.StringVar(&filename, "filename", "", "File containing the AppWrapper, - for the standard input.")
+		fs.BoolVar(&preemption, "preemption", false, "Evaluate the quota as the controller does with preemption enabled.")
+	})
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 || filename == "" {
+		return fmt.Errorf("usage: kubectl mcad simulate -f <file>")
+	}
+	var r io.Reader = os.Stdin
+	if filename != "-" {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	aw, err := readAppWrapper(r)
+	if err != nil {
+		return err
+	}
+
+	client, restConfig, namespace, err := o.clientset()
+	if err != nil {
+		return err
+	}
+	if aw.Namespace == "" {
+		aw.Namespace = namespace
+	}
+	aws, err := client.WorkloadV1beta1().AppWrappers("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	forest, err := loadQuotaForest(restConfig, aws.Items, preemption)
+	if err != nil {
+		return err
+	}
+	// An AppWrapper already dispatched is evaluated as if it was dispatched again
+	for i := range aws.Items {
+		if aws.Items[i].Namespace == aw.Namespace && aws.Items[i].Name == aw.Name && aws.Items[i].Status.CanRun {
+			forest.Release(&aws.Items[i])
+		}
+	}
+	setDefaultQuotaLabels(aw, forest.GetValidQuotaLabels())
+
+	demands := aggregatedResources(aw)
+	fmt.Fprintf(out, "AppWrapper %s/%s requests %s\n", aw.Namespace, aw.Name, demands)
+	fits, preemptions, message := forest.Fits(aw, demands, nil, nil)
+	if !fits {
+		fmt.Fprintf(out, "Does not fit in the quota: %s\n", message)
+		for _, rejection := range forest.GetQuotaRejections(aw, demands) {
+			fmt.Fprintf(out, "  Rejected by %s/%s, missing %s\n", rejection.Tree, rejection.Node, formatAmounts(rejection.Shortfall))
+		}
+		return nil
+	}
+	fmt.Fprintln(out, "Fits in the quota")
+	if len(preemptions) == 0 {
+		fmt.Fprintln(out, "No preemption")
+	}
+	for _, preempted := range preemptions {
+		fmt.Fprintf(out, "  Preempts %s/%s\n", preempted.Namespace, preempted.Name)
+	}
+	fmt.Fprintln(out, "The resources of the cluster are not evaluated")
+	return nil
+}
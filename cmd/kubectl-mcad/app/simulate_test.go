@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestReadAppWrapper(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	aw, err := readAppWrapper(strings.NewReader(`
+apiVersion: workload.codeflare.dev/v1beta1
+kind: AppWrapper
+metadata:
+  name: my-aw
+  labels:
+    context: team-a
+spec:
+  resources:
+    GenericItems: []
+`))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(aw.Name).To(gomega.Equal("my-aw"))
+
+	setDefaultQuotaLabels(aw, []string{"context", "service"})
+	g.Expect(aw.Labels).To(gomega.Equal(map[string]string{"context": "team-a", "service": defaultQuotaNode}))
+
+	_, err = readAppWrapper(strings.NewReader(`{"kind": "AppWrapper", "metadata": {}}`))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
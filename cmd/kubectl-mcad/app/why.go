@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// formatResourceList formats a list of resources, sorted by name, "-" when empty
+func formatResourceList(resources v1.ResourceList) string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[v1.ResourceName(name)]
+		formatted = append(formatted, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	if len(formatted) == 0 {
+		return "-"
+	}
+	return strings.Join(formatted, ",")
+}
+
+// printWhy explains the state of an AppWrapper from its status
+func printWhy(out io.Writer, aw *arbv1.AppWrapper, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s/%s\n", aw.Namespace, aw.Name)
+	state := string(aw.Status.State)
+	if state == "" {
+		state = "<none>"
+	}
+	fmt.Fprintf(w, "State:\t%s\n", state)
+	if aw.Status.QueueJobState != "" {
+		fmt.Fprintf(w, "Queue job state:\t%s\n", aw.Status.QueueJobState)
+	}
+	fmt.Fprintf(w, "Can run:\t%t\n", aw.Status.CanRun)
+	fmt.Fprintf(w, "Priority:\t%g\n", aw.Status.SystemPriority)
+	fmt.Fprintf(w, "Age:\t%s\n", age(arrivalTime(aw), now))
+
+	if position := aw.Status.QueuePosition; position != nil && isPending(aw) {
+		leaves := make([]string, 0, len(position.QuotaLeaves))
+		for _, leaf := range position.QuotaLeaves {
+			leaves = append(leaves, fmt.Sprintf("%s/%s: %d", leaf.Tree, leaf.Node, leaf.Position))
+		}
+		line := fmt.Sprintf("%d", position.Global)
+		if len(leaves) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(leaves, ", "))
+		}
+		fmt.Fprintf(w, "Queue position:\t%s\n", line)
+		fmt.Fprintf(w, "Estimated dispatch:\t%s\n", estimatedDispatch(aw, now))
+	}
+
+	if backoff := lastBackoff(aw); backoff != nil {
+		fmt.Fprintf(w, "Last backoff:\t%s: %s (%s ago)\n", backoff.Reason, backoff.Message,
+			age(backoff.LastUpdateMicroTime.Time, now))
+	}
+
+	if diagnostics := aw.Status.SchedulingDiagnostics; diagnostics != nil && isPending(aw) {
+		fmt.Fprintln(w, "Scheduling diagnostics:")
+		fmt.Fprintf(w, "  Reason:\t%s\n", diagnostics.Reason)
+		fmt.Fprintf(w, "  Requested:\t%s\n", formatResourceList(diagnostics.Requested))
+		fmt.Fprintf(w, "  Available:\t%s\n", formatResourceList(diagnostics.Available))
+		fmt.Fprintf(w, "  Shortfall:\t%s\n", formatResourceList(diagnostics.Shortfall))
+		for _, rejection := range diagnostics.QuotaRejections {
+			fmt.Fprintf(w, "  Quota rejected by:\t%s/%s, missing %s\n", rejection.Tree, rejection.Node,
+				formatAmounts(rejection.Shortfall))
+		}
+		if len(diagnostics.ProposedPreemptions) > 0 {
+			fmt.Fprintf(w, "  Proposed preemptions:\t%s\n", strings.Join(diagnostics.ProposedPreemptions, ", "))
+		}
+		fmt.Fprintf(w, "  Queued ahead:\t%d\n", diagnostics.QueuedAhead)
+		fmt.Fprintf(w, "  Updated:\t%s ago\n", age(diagnostics.LastUpdateTime.Time, now))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Conditions:")
+	w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, condition := range aw.Status.Conditions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason,
+			age(condition.LastUpdateMicroTime.Time, now), condition.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(aw.Status.PendingPodConditions) > 0 {
+		fmt.Fprintln(out, "Pending pods:")
+		w = tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "  POD\tTYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, pod := range aw.Status.PendingPodConditions {
+			for _, condition := range pod.Conditions {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", pod.PodName, condition.Type, condition.Status,
+					condition.Reason, condition.Message)
+			}
+		}
+		return w.Flush()
+	}
+	return nil
+}
+
+func runWhy(o *Options, args []string, out io.Writer) error {
+	positional, err := o.parseFlags("why", args, out, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: kubectl mcad why <appwrapper>")
+	}
+	client, _, namespace, err := o.clientset()
+	if err != nil {
+		return err
+	}
+	aw, err := client.WorkloadV1beta1().AppWrappers(namespace).Get(context.Background(), positional[0], metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return printWhy(out, aw, time.Now())
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func TestPrintWhy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	aw := appWrapper("team-a", "my-aw", 1, now.Add(-time.Hour))
+	aw.Status.QueueJobState = arbv1.AppWrapperCondBackoff
+	aw.Status.QueuePosition = &arbv1.QueuePosition{
+		Global:      3,
+		QuotaLeaves: []arbv1.QuotaLeafPosition{{Tree: "context", Node: "team-a", Position: 1}},
+	}
+	aw.Status.Conditions = []arbv1.AppWrapperCondition{{
+		Type:                arbv1.AppWrapperCondBackoff,
+		Status:              v1.ConditionTrue,
+		Reason:              "AppWrapperNotRunnable",
+		Message:             "Insufficient quota to dispatch AppWrapper.",
+		LastUpdateMicroTime: metav1.NewMicroTime(now.Add(-time.Minute)),
+	}}
+	aw.Status.SchedulingDiagnostics = &arbv1.SchedulingDiagnostics{
+		Reason:          "InsufficientQuota",
+		Requested:       v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		QuotaRejections: []arbv1.QuotaRejection{{Tree: "context", Node: "team-a", Shortfall: map[string]int64{"cpu": 1000}}},
+	}
+	aw.Status.PendingPodConditions = []arbv1.PendingPodSpec{{
+		PodName:    "my-aw-0",
+		Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable"}},
+	}}
+
+	out := &bytes.Buffer{}
+	g.Expect(printWhy(out, &aw, now)).To(gomega.Succeed())
+	g.Expect(out.String()).To(gomega.And(
+		gomega.MatchRegexp(`Name:\s+team-a/my-aw`),
+		gomega.MatchRegexp(`Queue position:\s+3 \(context/team-a: 1\)`),
+		gomega.MatchRegexp(`Last backoff:\s+AppWrapperNotRunnable: Insufficient quota to dispatch AppWrapper. \(60s ago\)`),
+		gomega.MatchRegexp(`Requested:\s+cpu=2`),
+		gomega.MatchRegexp(`Quota rejected by:\s+context/team-a, missing cpu=1000`),
+		gomega.MatchRegexp(`my-aw-0\s+PodScheduled\s+False\s+Unschedulable`),
+	))
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/cmd/kubectl-mcad/app"
+)
+
+func main() {
+	flagSet := flag.CommandLine
+	klog.InitFlags(flagSet)
+	// The libraries shared with the controller log at the info level, only errors are shown by default
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	o := app.NewOptions()
+	o.AddFlags(flagSet)
+	flagSet.Usage = func() { app.Usage(flagSet.Output()) }
+	flag.Parse()
+
+	if err := app.Run(o, flagSet.Args(), os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
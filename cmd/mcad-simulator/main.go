@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/klog/v2"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/simulator"
+)
+
+func main() {
+	flagSet := flag.CommandLine
+	klog.InitFlags(flagSet)
+	// The controller code logs quota rejections as errors, which are expected in a simulation.  Logs are only
+	// shown when requested with -logtostderr.
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+	_ = flagSet.Set("stderrthreshold", "FATAL")
+
+	scenarioFile := flagSet.String("scenario", "", "Path to the scenario, in YAML or JSON: the policy, the nodes and the quota subtrees.")
+	traceFile := flagSet.String("trace", "", "Path to the arrival trace of jobs, one JSON record per line.")
+	output := flagSet.String("output", "text", "Output format of the report: text or json.")
+	flagSet.Usage = func() {
+		fmt.Fprintf(flagSet.Output(), "Replay an arrival trace of AppWrappers against a synthetic cluster.\n\nUsage:\n  mcad-simulator -scenario <file> -trace <file> [flags]\n\nFlags:\n")
+		flagSet.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*scenarioFile, *traceFile, *output, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(scenarioFile, traceFile, output string, out io.Writer) error {
+	if len(scenarioFile) == 0 || len(traceFile) == 0 {
+		return fmt.Errorf("both -scenario and -trace are required")
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	f, err := os.Open(scenarioFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scenario, err := simulator.ReadScenario(f)
+	if err != nil {
+		return err
+	}
+
+	t, err := os.Open(traceFile)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	jobs, err := simulator.ReadTrace(t)
+	if err != nil {
+		return fmt.Errorf("failed to read the trace: %v", err)
+	}
+
+	sim, err := simulator.New(scenario)
+	if err != nil {
+		return err
+	}
+	report, err := sim.Run(jobs)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return report.WriteText(out)
+}
@@ -31,6 +31,10 @@ const AppWrapperAnnotationKey = "appwrapper.mcad.ibm.com/appwrapper-name"
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="QueueJobState",type="string",JSONPath=".status.queuejobstate"
+// +kubebuilder:printcolumn:name="Blocking Item",type="string",JSONPath=".status.blockingItem"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // Definition of AppWrapper class
@@ -70,6 +74,23 @@ type AppWrapperSpec struct {
 	// SchedSpec specifies the parameters used for scheduling generic items wrapped inside AppWrappers.
 	// It defines the policy for requeuing jobs based on the number of running pods.
 	SchedSpec SchedulingSpecTemplate `json:"schedulingSpec,omitempty" protobuf:"bytes,2,opt,name=schedulingSpec"`
+
+	// RestartPolicy specifies if and how often a failed AppWrapper is returned to the queue.
+	// It applies to AppWrappers that failed dispatching, exceeded their dispatch deadline or
+	// have an item that reported a failure condition.
+	// +optional
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// RestartPolicy describes how a failed AppWrapper is restarted.
+type RestartPolicy struct {
+	// Maximum number of times a failed AppWrapper is cleaned up and returned to the queue.
+	// When not specified, restarting is disabled and a failed AppWrapper remains in the Failed state.
+	// +kubebuilder:default=0
+	MaxRestarts int `json:"maxRestarts,omitempty"`
+	// Time in seconds a restarted AppWrapper waits before it is considered for dispatching again.
+	// +kubebuilder:default=0
+	BackoffInSeconds int `json:"backoffInSeconds,omitempty"`
 }
 
 // a collection of AppWrapperResource
@@ -121,12 +142,49 @@ type AppWrapperGenericResource struct {
 	// The completionstatus field contains a list of conditions that make the associate item considered
 	// completed, for instance:
 	// - completion conditions could be "Complete" or "Failed".
-	// The associated item's level .status.conditions[].type field is monitored for any one of these conditions.
+	// The associated item's level .status.conditions[].type field is monitored for any one of these conditions
+	// with a .status.conditions[].status of True.
 	// Once all items with this option is set and the conditionstatus is met the entire AppWrapper state will be changed to one of the valid AppWrapper completion state.
 	// Note:
 	// - this is an AND operation for all items where this option is set.
 	// See the list of AppWrapper states for a list of valid complete states.
 	CompletionStatus string `json:"completionstatus,omitempty"`
+
+	// Optional field that drives failure status of this AppWrapper.
+	// The failurestatus field contains a comma-separated list of conditions that make the associated item
+	// considered failed, for instance "Failed".
+	// The associated item's level .status.conditions[].type field is monitored for any one of these conditions
+	// with a .status.conditions[].status of True.
+	// Note:
+	// - this is an OR operation for all items where this option is set, the AppWrapper is moved
+	//   to the Failed state as soon as any item reports failure.
+	FailureStatus string `json:"failurestatus,omitempty"`
+
+	// Optional field that orders the creation of the items of this AppWrapper.
+	// Items are created stage by stage in increasing order; the items of a stage are only created once
+	// all items of the earlier stages are ready according to their readiness predicate.
+	// All items are created at once when no item sets a stage.
+	// +kubebuilder:default=0
+	Stage int `json:"stage,omitempty"`
+
+	// Optional field that specifies when this item is considered ready, gating the creation of the items
+	// in later stages. An item without a readiness predicate is ready as soon as it is created.
+	Readiness *ItemReadiness `json:"readiness,omitempty"`
+}
+
+// ItemReadiness is the readiness predicate of a generic item.
+// When both fields are set the item is ready once both predicates are satisfied.
+type ItemReadiness struct {
+	// Minimum value of the .status.readyReplicas field of the item.
+	ReadyReplicas *int32 `json:"readyReplicas,omitempty"`
+
+	// Comma-separated list of condition types, the item is ready once its .status.conditions[] contains
+	// one of these types with a .status.conditions[].status of True.
+	ConditionTypes string `json:"conditionTypes,omitempty"`
+
+	// Time in seconds the item has to become ready after it is created.
+	// The AppWrapper is failed when the timeout expires. When not specified, there is no timeout.
+	TimeoutInSeconds int `json:"timeoutInSeconds,omitempty"`
 }
 
 type CustomPodResourceTemplate struct {
@@ -173,6 +231,10 @@ type AppWrapperStatus struct {
 	// Is Dispatched?
 	IsDispatched bool `json:"isdispatched,omitempty" protobuf:"bytes,1,opt,name=isdispatched"`
 
+	// ID of the agent cluster the AppWrapper is dispatched to, in dispatcher mode
+	// +optional
+	DispatchedAgent string `json:"dispatchedAgent,omitempty"`
+
 	// State - Pending, Running, Failed, Deleted
 	State AppWrapperState `json:"state,omitempty"`
 
@@ -223,8 +285,131 @@ type AppWrapperStatus struct {
 
 	// Field to keep track of how many times a requeuing event has been triggered
 	NumberOfRequeueings int `json:"numberOfRequeueings,omitempty"`
+
+	// Field to keep track of how many times the AppWrapper has been restarted after a failure
+	NumberOfRestarts int `json:"numberOfRestarts,omitempty"`
+
+	// The latest stage of generic items that has been created
+	CurrentStage int `json:"currentStage,omitempty"`
+
+	// References to the objects created for the generic items of the AppWrapper and their state
+	Items []AppWrapperItemStatus `json:"items,omitempty"`
+
+	// Kind and name of the item holding the AppWrapper back: the first failed item, or else the first item
+	// that is neither ready nor completed
+	// +optional
+	BlockingItem string `json:"blockingItem,omitempty"`
+
+	// Explanation of the latest failed attempt to dispatch the AppWrapper
+	// +optional
+	SchedulingDiagnostics *SchedulingDiagnostics `json:"schedulingDiagnostics,omitempty"`
+
+	// Position of the AppWrapper in the queue and estimated dispatch time, while it is queued
+	// +optional
+	QueuePosition *QueuePosition `json:"queuePosition,omitempty"`
+}
+
+// QueuePosition is the position of a queued AppWrapper and its estimated dispatch time
+type QueuePosition struct {
+	// Position among all the queued AppWrappers, starting at 1
+	Global int32 `json:"global"`
+
+	// Positions among the queued AppWrappers charged to the same quota tree leaf nodes
+	QuotaLeaves []QuotaLeafPosition `json:"quotaLeaves,omitempty"`
+
+	// Best-effort estimation of the dispatch time, assuming the running AppWrappers finish after their expected
+	// dispatch duration
+	// +optional
+	EstimatedDispatchTime *metav1.Time `json:"estimatedDispatchTime,omitempty"`
+
+	// Time the position last changed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// QuotaLeafPosition is the position of a queued AppWrapper among the AppWrappers charged to a quota tree leaf node
+type QuotaLeafPosition struct {
+	// Name of the quota tree
+	Tree string `json:"tree"`
+
+	// Name of the leaf node
+	Node string `json:"node"`
+
+	// Position among the queued AppWrappers charged to the leaf node, starting at 1
+	Position int32 `json:"position"`
+}
+
+// SchedulingDiagnostics explains why an AppWrapper could not be dispatched
+type SchedulingDiagnostics struct {
+	// Reason of the dispatch failure
+	Reason string `json:"reason,omitempty"`
+
+	// Resources requested by the AppWrapper
+	Requested v1.ResourceList `json:"requested,omitempty"`
+
+	// Resources available to the AppWrapper, including the resources of the proposed preemption victims
+	Available v1.ResourceList `json:"available,omitempty"`
+
+	// Amount of each requested resource missing from the available resources
+	Shortfall v1.ResourceList `json:"shortfall,omitempty"`
+
+	// Quota tree nodes that rejected the AppWrapper
+	QuotaRejections []QuotaRejection `json:"quotaRejections,omitempty"`
+
+	// AppWrappers proposed for preemption that did not free enough resources, as namespace/name
+	ProposedPreemptions []string `json:"proposedPreemptions,omitempty"`
+
+	// Number of queued AppWrappers ahead of the AppWrapper
+	QueuedAhead int32 `json:"queuedAhead,omitempty"`
+
+	// Time the diagnostics last changed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// QuotaRejection describes a quota tree node without enough quota left for an AppWrapper
+type QuotaRejection struct {
+	// Name of the quota tree
+	Tree string `json:"tree"`
+
+	// Name of the quota node
+	Node string `json:"node"`
+
+	// Amount of each resource missing from the quota left in the node, in the units of the quota tree
+	Shortfall map[string]int64 `json:"shortfall,omitempty"`
+}
+
+// AppWrapperItemStatus describes the object created for a generic item of the AppWrapper
+type AppWrapperItemStatus struct {
+	// Index of the generic item in the list of generic items of the AppWrapper
+	Index int `json:"index"`
+
+	// Group, version and kind of the created object
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Resource is the plural resource name of the created object, used to delete it without discovery
+	Resource string `json:"resource"`
+
+	// Name and namespace of the created object
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// Time the object was created
+	CreationTime metav1.Time `json:"creationTime,omitempty"`
+
+	// State of the item - Created, Ready, Completed, Failed
+	State AppWrapperItemState `json:"state,omitempty"`
 }
 
+type AppWrapperItemState string
+
+const (
+	AppWrapperItemStateCreated   AppWrapperItemState = "Created"
+	AppWrapperItemStateReady     AppWrapperItemState = "Ready"
+	AppWrapperItemStateCompleted AppWrapperItemState = "Completed"
+	AppWrapperItemStateFailed    AppWrapperItemState = "Failed"
+)
+
 type AppWrapperState string
 
 // enqueued, active, deleting, succeeded, failed
@@ -252,6 +437,7 @@ const (
 	AppWrapperCondFailed                AppWrapperConditionType = "Failed"
 	AppWrapperCondCompleted             AppWrapperConditionType = "Completed"
 	AppWrapperCondRunningHoldCompletion AppWrapperConditionType = "RunningHoldCompletion"
+	AppWrapperCondRestarting            AppWrapperConditionType = "Restarting"
 )
 
 // AppWrapperCondition describes the state of an AppWrapper at a certain point.
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAgentPlural is the plural of ClusterAgent
+const ClusterAgentPlural string = "clusteragents"
+
+// DefaultKubeconfigSecretKey is the key of the kubeconfig in the Secret referenced by a ClusterAgent
+// when none is specified.
+const DefaultKubeconfigSecretKey = "kubeconfig"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterAgent registers an agent cluster AppWrappers can be dispatched to in dispatcher mode.
+type ClusterAgent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ClusterAgentSpec   `json:"spec"`
+	Status            ClusterAgentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterAgentList is a collection of ClusterAgents.
+type ClusterAgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ClusterAgent `json:"items"`
+}
+
+// ClusterAgentSpec describes how to connect to an agent cluster.
+type ClusterAgentSpec struct {
+	// Reference to the Secret holding the kubeconfig of the agent cluster.
+	KubeconfigSecretRef SecretKeyReference `json:"kubeconfigSecretRef"`
+
+	// Name of the MCAD deployment in the agent cluster.
+	// +optional
+	DeploymentName string `json:"deploymentName,omitempty"`
+
+	// Labels of the agent cluster, matched against the cluster selectors of AppWrappers.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Relative preference for the agent cluster when scoring clusters an AppWrapper fits in.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// SecretKeyReference references a key of a Secret.
+type SecretKeyReference struct {
+	// Namespace of the Secret.
+	Namespace string `json:"namespace"`
+
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Key of the Secret data, defaults to 'kubeconfig'.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// ClusterAgentStatus reports the connection health of an agent cluster.
+type ClusterAgentStatus struct {
+	// Whether the dispatcher is connected to the agent cluster.
+	// +optional
+	Healthy bool `json:"healthy,omitempty"`
+
+	// Reason the dispatcher cannot connect to the agent cluster.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Last time the connection health changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
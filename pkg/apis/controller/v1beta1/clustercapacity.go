@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterCapacityPlural is the plural of ClusterCapacity
+const ClusterCapacityPlural string = "clustercapacities"
+
+// ClusterCapacityName is the name of the ClusterCapacity an agent cluster reports its capacity in.
+const ClusterCapacityName = "mcad-capacity"
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterCapacity is the capacity report an MCAD controller in agent mode publishes for the dispatcher.
+type ClusterCapacity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Status            ClusterCapacityStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterCapacityList is a collection of ClusterCapacities.
+type ClusterCapacityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ClusterCapacity `json:"items"`
+}
+
+// ClusterCapacityStatus is the capacity of an agent cluster available to AppWrappers.
+type ClusterCapacityStatus struct {
+	// Capacity of the schedulable nodes not requested by pods outside of AppWrappers, for all resources.
+	// +optional
+	Allocatable v1.ResourceList `json:"allocatable,omitempty"`
+
+	// Largest capacities free on a single node, in decreasing order of CPU.
+	// +optional
+	LargestFreeNodeSlots []v1.ResourceList `json:"largestFreeNodeSlots,omitempty"`
+
+	// Number of AppWrappers waiting to be dispatched in the agent cluster.
+	// +optional
+	QueueLength int32 `json:"queueLength,omitempty"`
+
+	// Quota left in each quota tree of the agent cluster.
+	// +optional
+	QuotaHeadroom []QuotaTreeHeadroom `json:"quotaHeadroom,omitempty"`
+
+	// Last time the report was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// QuotaTreeHeadroom is the quota left at the root of a quota tree.
+type QuotaTreeHeadroom struct {
+	// Name of the quota tree.
+	Tree string `json:"tree"`
+
+	// Quota left by resource name, in the units of the quota tree.
+	// +optional
+	Resources map[string]int64 `json:"resources,omitempty"`
+}
@@ -45,6 +45,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&SchedulingSpecList{},
 		&AppWrapper{},
 		&AppWrapperList{},
+		&ClusterAgent{},
+		&ClusterAgentList{},
+		&ClusterCapacity{},
+		&ClusterCapacityList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -43,6 +43,10 @@ type SchedulingSpecTemplate struct {
 	Requeuing RequeuingTemplate `json:"requeuing,omitempty" protobuf:"bytes,1,rep,name=requeuing"`
 	// Wall clock duration time of appwrapper in seconds.
 	DispatchDuration DispatchDurationSpec `json:"dispatchDuration,omitempty"`
+	// Constraints on the agent clusters the AppWrapper can be dispatched to.
+	// Only used in dispatcher mode.
+	// +optional
+	ClusterScheduling *ClusterSchedulingSpec `json:"clusterScheduling,omitempty"`
 }
 
 type RequeuingTemplate struct {
@@ -85,11 +89,15 @@ type SchedulingSpecList struct {
 type ResourceName string
 
 type ClusterReference struct {
+	// Name of the agent cluster.
 	Name string `json:"name"`
 }
 
 type ClusterSchedulingSpec struct {
-	Clusters        []ClusterReference    `json:"clusters,omitempty"`
+	// Explicit list of agent clusters the AppWrapper can be dispatched to.
+	Clusters []ClusterReference `json:"clusters,omitempty"`
+	// Label selector on the labels of the agent clusters the AppWrapper can be dispatched to.
+	// Both constraints must be satisfied when both are specified.
 	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
 }
 
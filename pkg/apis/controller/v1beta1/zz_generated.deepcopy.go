@@ -87,6 +87,11 @@ func (in *AppWrapperGenericResource) DeepCopyInto(out *AppWrapperGenericResource
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ItemReadiness)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppWrapperGenericResource.
@@ -99,6 +104,22 @@ func (in *AppWrapperGenericResource) DeepCopy() *AppWrapperGenericResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppWrapperItemStatus) DeepCopyInto(out *AppWrapperItemStatus) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppWrapperItemStatus.
+func (in *AppWrapperItemStatus) DeepCopy() *AppWrapperItemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AppWrapperItemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppWrapperList) DeepCopyInto(out *AppWrapperList) {
 	*out = *in
@@ -180,6 +201,7 @@ func (in *AppWrapperSpec) DeepCopyInto(out *AppWrapperSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.SchedSpec.DeepCopyInto(&out.SchedSpec)
+	out.RestartPolicy = in.RestartPolicy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppWrapperSpec.
@@ -211,6 +233,23 @@ func (in *AppWrapperStatus) DeepCopyInto(out *AppWrapperStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AppWrapperItemStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchedulingDiagnostics != nil {
+		in, out := &in.SchedulingDiagnostics, &out.SchedulingDiagnostics
+		*out = new(SchedulingDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueuePosition != nil {
+		in, out := &in.QueuePosition, &out.QueuePosition
+		*out = new(QueuePosition)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppWrapperStatus.
@@ -223,6 +262,205 @@ func (in *AppWrapperStatus) DeepCopy() *AppWrapperStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAgent) DeepCopyInto(out *ClusterAgent) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgent.
+func (in *ClusterAgent) DeepCopy() *ClusterAgent {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAgent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAgentList) DeepCopyInto(out *ClusterAgentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterAgent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentList.
+func (in *ClusterAgentList) DeepCopy() *ClusterAgentList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAgentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAgentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAgentSpec) DeepCopyInto(out *ClusterAgentSpec) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentSpec.
+func (in *ClusterAgentSpec) DeepCopy() *ClusterAgentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAgentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAgentStatus) DeepCopyInto(out *ClusterAgentStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAgentStatus.
+func (in *ClusterAgentStatus) DeepCopy() *ClusterAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacity) DeepCopyInto(out *ClusterCapacity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacity.
+func (in *ClusterCapacity) DeepCopy() *ClusterCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterCapacity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacityList) DeepCopyInto(out *ClusterCapacityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterCapacity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacityList.
+func (in *ClusterCapacityList) DeepCopy() *ClusterCapacityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterCapacityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacityStatus) DeepCopyInto(out *ClusterCapacityStatus) {
+	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LargestFreeNodeSlots != nil {
+		in, out := &in.LargestFreeNodeSlots, &out.LargestFreeNodeSlots
+		*out = make([]corev1.ResourceList, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(corev1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+		}
+	}
+	if in.QuotaHeadroom != nil {
+		in, out := &in.QuotaHeadroom, &out.QuotaHeadroom
+		*out = make([]QuotaTreeHeadroom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacityStatus.
+func (in *ClusterCapacityStatus) DeepCopy() *ClusterCapacityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterReference) DeepCopyInto(out *ClusterReference) {
 	*out = *in
@@ -324,6 +562,26 @@ func (in *DispatchingWindowSpec) DeepCopy() *DispatchingWindowSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemReadiness) DeepCopyInto(out *ItemReadiness) {
+	*out = *in
+	if in.ReadyReplicas != nil {
+		in, out := &in.ReadyReplicas, &out.ReadyReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemReadiness.
+func (in *ItemReadiness) DeepCopy() *ItemReadiness {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemReadiness)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PendingPodSpec) DeepCopyInto(out *PendingPodSpec) {
 	*out = *in
@@ -346,6 +604,90 @@ func (in *PendingPodSpec) DeepCopy() *PendingPodSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuePosition) DeepCopyInto(out *QueuePosition) {
+	*out = *in
+	if in.QuotaLeaves != nil {
+		in, out := &in.QuotaLeaves, &out.QuotaLeaves
+		*out = make([]QuotaLeafPosition, len(*in))
+		copy(*out, *in)
+	}
+	if in.EstimatedDispatchTime != nil {
+		in, out := &in.EstimatedDispatchTime, &out.EstimatedDispatchTime
+		*out = (*in).DeepCopy()
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuePosition.
+func (in *QueuePosition) DeepCopy() *QueuePosition {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuePosition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaLeafPosition) DeepCopyInto(out *QuotaLeafPosition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaLeafPosition.
+func (in *QuotaLeafPosition) DeepCopy() *QuotaLeafPosition {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaLeafPosition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRejection) DeepCopyInto(out *QuotaRejection) {
+	*out = *in
+	if in.Shortfall != nil {
+		in, out := &in.Shortfall, &out.Shortfall
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRejection.
+func (in *QuotaRejection) DeepCopy() *QuotaRejection {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRejection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaTreeHeadroom) DeepCopyInto(out *QuotaTreeHeadroom) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaTreeHeadroom.
+func (in *QuotaTreeHeadroom) DeepCopy() *QuotaTreeHeadroom {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaTreeHeadroom)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RequeuingTemplate) DeepCopyInto(out *RequeuingTemplate) {
 	*out = *in
@@ -361,6 +703,21 @@ func (in *RequeuingTemplate) DeepCopy() *RequeuingTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartPolicy) DeepCopyInto(out *RestartPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartPolicy.
+func (in *RestartPolicy) DeepCopy() *RestartPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RestartPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduleTimeSpec) DeepCopyInto(out *ScheduleTimeSpec) {
 	*out = *in
@@ -379,6 +736,55 @@ func (in *ScheduleTimeSpec) DeepCopy() *ScheduleTimeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingDiagnostics) DeepCopyInto(out *SchedulingDiagnostics) {
+	*out = *in
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Shortfall != nil {
+		in, out := &in.Shortfall, &out.Shortfall
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.QuotaRejections != nil {
+		in, out := &in.QuotaRejections, &out.QuotaRejections
+		*out = make([]QuotaRejection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProposedPreemptions != nil {
+		in, out := &in.ProposedPreemptions, &out.ProposedPreemptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingDiagnostics.
+func (in *SchedulingDiagnostics) DeepCopy() *SchedulingDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulingSpec) DeepCopyInto(out *SchedulingSpec) {
 	*out = *in
@@ -449,6 +855,11 @@ func (in *SchedulingSpecTemplate) DeepCopyInto(out *SchedulingSpecTemplate) {
 	}
 	out.Requeuing = in.Requeuing
 	out.DispatchDuration = in.DispatchDuration
+	if in.ClusterScheduling != nil {
+		in, out := &in.ClusterScheduling, &out.ClusterScheduling
+		*out = new(ClusterSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSpecTemplate.
@@ -460,3 +871,18 @@ func (in *SchedulingSpecTemplate) DeepCopy() *SchedulingSpecTemplate {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -66,6 +66,8 @@ type Quota struct {
 	Disabled  bool         `json:"disabled,omitempty" protobuf:"bytes,1,opt,name=disabled"`
 	Requests  ResourceList `json:"requests,omitempty" protobuf:"bytes,2,rep,name=requests,casttype=ResourceList,castkey=ResourceName"`
 	HardLimit bool         `json:"hardLimit,omitempty" protobuf:"bytes,4,opt,name=hardLimit"`
+	// Maximum resources the quota node can use in each agent cluster in dispatcher mode, by ClusterAgent name
+	ClusterLimits map[string]ResourceList `json:"clusterLimits,omitempty" protobuf:"bytes,5,rep,name=clusterLimits"`
 }
 
 // QuotaSubtreeStatus is the status for a QuotaSubtree resource
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -51,6 +52,23 @@ func (in *Quota) DeepCopyInto(out *Quota) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.ClusterLimits != nil {
+		in, out := &in.ClusterLimits, &out.ClusterLimits
+		*out = make(map[string]ResourceList, len(*in))
+		for key, val := range *in {
+			var outVal map[ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
@@ -33,6 +33,9 @@ type AppWrapperGenericResourceApplyConfiguration struct {
 	GenericTemplate    *runtime.RawExtension                         `json:"generictemplate,omitempty"`
 	CustomPodResources []CustomPodResourceTemplateApplyConfiguration `json:"custompodresources,omitempty"`
 	CompletionStatus   *string                                       `json:"completionstatus,omitempty"`
+	FailureStatus      *string                                       `json:"failurestatus,omitempty"`
+	Stage              *int                                          `json:"stage,omitempty"`
+	Readiness          *ItemReadinessApplyConfiguration              `json:"readiness,omitempty"`
 }
 
 // AppWrapperGenericResourceApplyConfiguration constructs an declarative configuration of the AppWrapperGenericResource type for use with
@@ -109,3 +112,27 @@ func (b *AppWrapperGenericResourceApplyConfiguration) WithCompletionStatus(value
 	b.CompletionStatus = &value
 	return b
 }
+
+// WithFailureStatus sets the FailureStatus field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FailureStatus field is set to the value of the last call.
+func (b *AppWrapperGenericResourceApplyConfiguration) WithFailureStatus(value string) *AppWrapperGenericResourceApplyConfiguration {
+	b.FailureStatus = &value
+	return b
+}
+
+// WithStage sets the Stage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Stage field is set to the value of the last call.
+func (b *AppWrapperGenericResourceApplyConfiguration) WithStage(value int) *AppWrapperGenericResourceApplyConfiguration {
+	b.Stage = &value
+	return b
+}
+
+// WithReadiness sets the Readiness field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Readiness field is set to the value of the last call.
+func (b *AppWrapperGenericResourceApplyConfiguration) WithReadiness(value *ItemReadinessApplyConfiguration) *AppWrapperGenericResourceApplyConfiguration {
+	b.Readiness = value
+	return b
+}
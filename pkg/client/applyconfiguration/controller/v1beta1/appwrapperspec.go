@@ -31,6 +31,7 @@ type AppWrapperSpecApplyConfiguration struct {
 	AggrResources *AppWrapperResourceListApplyConfiguration `json:"resources,omitempty"`
 	Selector      *v1.LabelSelector                         `json:"selector,omitempty"`
 	SchedSpec     *SchedulingSpecTemplateApplyConfiguration `json:"schedulingSpec,omitempty"`
+	RestartPolicy *RestartPolicyApplyConfiguration          `json:"restartPolicy,omitempty"`
 }
 
 // AppWrapperSpecApplyConfiguration constructs an declarative configuration of the AppWrapperSpec type for use with
@@ -86,3 +87,11 @@ func (b *AppWrapperSpecApplyConfiguration) WithSchedSpec(value *SchedulingSpecTe
 	b.SchedSpec = value
 	return b
 }
+
+// WithRestartPolicy sets the RestartPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RestartPolicy field is set to the value of the last call.
+func (b *AppWrapperSpecApplyConfiguration) WithRestartPolicy(value *RestartPolicyApplyConfiguration) *AppWrapperSpecApplyConfiguration {
+	b.RestartPolicy = value
+	return b
+}
@@ -26,29 +26,36 @@ import (
 // AppWrapperStatusApplyConfiguration represents an declarative configuration of the AppWrapperStatus type for use
 // with apply.
 type AppWrapperStatusApplyConfiguration struct {
-	Pending                          *int32                                  `json:"pending,omitempty"`
-	Running                          *int32                                  `json:"running,omitempty"`
-	Succeeded                        *int32                                  `json:"Succeeded,omitempty"`
-	Failed                           *int32                                  `json:"failed,omitempty"`
-	MinAvailable                     *int32                                  `json:"template,omitempty"`
-	CanRun                           *bool                                   `json:"canrun,omitempty"`
-	IsDispatched                     *bool                                   `json:"isdispatched,omitempty"`
-	State                            *v1beta1.AppWrapperState                `json:"state,omitempty"`
-	Message                          *string                                 `json:"message,omitempty"`
-	SystemPriority                   *float64                                `json:"systempriority,omitempty"`
-	QueueJobState                    *v1beta1.AppWrapperConditionType        `json:"queuejobstate,omitempty"`
-	ControllerFirstTimestamp         *v1.MicroTime                           `json:"controllerfirsttimestamp,omitempty"`
-	ControllerFirstDispatchTimestamp *v1.MicroTime                           `json:"controllerfirstdispatchtimestamp,omitempty"`
-	FilterIgnore                     *bool                                   `json:"filterignore,omitempty"`
-	Sender                           *string                                 `json:"sender,omitempty"`
-	Local                            *bool                                   `json:"local,omitempty"`
-	Conditions                       []AppWrapperConditionApplyConfiguration `json:"conditions,omitempty"`
-	PendingPodConditions             []PendingPodSpecApplyConfiguration      `json:"pendingpodconditions,omitempty"`
-	TotalCPU                         *int32                                  `json:"totalcpu,omitempty"`
-	TotalMemory                      *int32                                  `json:"totalmemory,omitempty"`
-	TotalGPU                         *int32                                  `json:"totalgpu,omitempty"`
-	RequeueingTimeInSeconds          *int                                    `json:"requeueingTimeInSeconds,omitempty"`
-	NumberOfRequeueings              *int                                    `json:"numberOfRequeueings,omitempty"`
+	Pending                          *int32                                   `json:"pending,omitempty"`
+	Running                          *int32                                   `json:"running,omitempty"`
+	Succeeded                        *int32                                   `json:"Succeeded,omitempty"`
+	Failed                           *int32                                   `json:"failed,omitempty"`
+	MinAvailable                     *int32                                   `json:"template,omitempty"`
+	CanRun                           *bool                                    `json:"canrun,omitempty"`
+	IsDispatched                     *bool                                    `json:"isdispatched,omitempty"`
+	DispatchedAgent                  *string                                  `json:"dispatchedAgent,omitempty"`
+	State                            *v1beta1.AppWrapperState                 `json:"state,omitempty"`
+	Message                          *string                                  `json:"message,omitempty"`
+	SystemPriority                   *float64                                 `json:"systempriority,omitempty"`
+	QueueJobState                    *v1beta1.AppWrapperConditionType         `json:"queuejobstate,omitempty"`
+	ControllerFirstTimestamp         *v1.MicroTime                            `json:"controllerfirsttimestamp,omitempty"`
+	ControllerFirstDispatchTimestamp *v1.MicroTime                            `json:"controllerfirstdispatchtimestamp,omitempty"`
+	FilterIgnore                     *bool                                    `json:"filterignore,omitempty"`
+	Sender                           *string                                  `json:"sender,omitempty"`
+	Local                            *bool                                    `json:"local,omitempty"`
+	Conditions                       []AppWrapperConditionApplyConfiguration  `json:"conditions,omitempty"`
+	PendingPodConditions             []PendingPodSpecApplyConfiguration       `json:"pendingpodconditions,omitempty"`
+	TotalCPU                         *int32                                   `json:"totalcpu,omitempty"`
+	TotalMemory                      *int32                                   `json:"totalmemory,omitempty"`
+	TotalGPU                         *int32                                   `json:"totalgpu,omitempty"`
+	RequeueingTimeInSeconds          *int                                     `json:"requeueingTimeInSeconds,omitempty"`
+	NumberOfRequeueings              *int                                     `json:"numberOfRequeueings,omitempty"`
+	NumberOfRestarts                 *int                                     `json:"numberOfRestarts,omitempty"`
+	CurrentStage                     *int                                     `json:"currentStage,omitempty"`
+	Items                            []AppWrapperItemStatusApplyConfiguration `json:"items,omitempty"`
+	BlockingItem                     *string                                  `json:"blockingItem,omitempty"`
+	SchedulingDiagnostics            *SchedulingDiagnosticsApplyConfiguration `json:"schedulingDiagnostics,omitempty"`
+	QueuePosition                    *QueuePositionApplyConfiguration         `json:"queuePosition,omitempty"`
 }
 
 // AppWrapperStatusApplyConfiguration constructs an declarative configuration of the AppWrapperStatus type for use with
@@ -113,6 +120,14 @@ func (b *AppWrapperStatusApplyConfiguration) WithIsDispatched(value bool) *AppWr
 	return b
 }
 
+// WithDispatchedAgent sets the DispatchedAgent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DispatchedAgent field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithDispatchedAgent(value string) *AppWrapperStatusApplyConfiguration {
+	b.DispatchedAgent = &value
+	return b
+}
+
 // WithState sets the State field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the State field is set to the value of the last call.
@@ -250,3 +265,56 @@ func (b *AppWrapperStatusApplyConfiguration) WithNumberOfRequeueings(value int)
 	b.NumberOfRequeueings = &value
 	return b
 }
+
+// WithNumberOfRestarts sets the NumberOfRestarts field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NumberOfRestarts field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithNumberOfRestarts(value int) *AppWrapperStatusApplyConfiguration {
+	b.NumberOfRestarts = &value
+	return b
+}
+
+// WithCurrentStage sets the CurrentStage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CurrentStage field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithCurrentStage(value int) *AppWrapperStatusApplyConfiguration {
+	b.CurrentStage = &value
+	return b
+}
+
+// WithItems adds the given value to the Items field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Items field.
+func (b *AppWrapperStatusApplyConfiguration) WithItems(values ...*AppWrapperItemStatusApplyConfiguration) *AppWrapperStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithItems")
+		}
+		b.Items = append(b.Items, *values[i])
+	}
+	return b
+}
+
+// WithBlockingItem sets the BlockingItem field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BlockingItem field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithBlockingItem(value string) *AppWrapperStatusApplyConfiguration {
+	b.BlockingItem = &value
+	return b
+}
+
+// WithSchedulingDiagnostics sets the SchedulingDiagnostics field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SchedulingDiagnostics field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithSchedulingDiagnostics(value *SchedulingDiagnosticsApplyConfiguration) *AppWrapperStatusApplyConfiguration {
+	b.SchedulingDiagnostics = value
+	return b
+}
+
+// WithQueuePosition sets the QueuePosition field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the QueuePosition field is set to the value of the last call.
+func (b *AppWrapperStatusApplyConfiguration) WithQueuePosition(value *QueuePositionApplyConfiguration) *AppWrapperStatusApplyConfiguration {
+	b.QueuePosition = value
+	return b
+}
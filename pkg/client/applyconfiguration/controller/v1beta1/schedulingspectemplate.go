@@ -21,10 +21,11 @@ package v1beta1
 // SchedulingSpecTemplateApplyConfiguration represents an declarative configuration of the SchedulingSpecTemplate type for use
 // with apply.
 type SchedulingSpecTemplateApplyConfiguration struct {
-	NodeSelector     map[string]string                       `json:"nodeSelector,omitempty"`
-	MinAvailable     *int                                    `json:"minAvailable,omitempty"`
-	Requeuing        *RequeuingTemplateApplyConfiguration    `json:"requeuing,omitempty"`
-	DispatchDuration *DispatchDurationSpecApplyConfiguration `json:"dispatchDuration,omitempty"`
+	NodeSelector      map[string]string                        `json:"nodeSelector,omitempty"`
+	MinAvailable      *int                                     `json:"minAvailable,omitempty"`
+	Requeuing         *RequeuingTemplateApplyConfiguration     `json:"requeuing,omitempty"`
+	DispatchDuration  *DispatchDurationSpecApplyConfiguration  `json:"dispatchDuration,omitempty"`
+	ClusterScheduling *ClusterSchedulingSpecApplyConfiguration `json:"clusterScheduling,omitempty"`
 }
 
 // SchedulingSpecTemplateApplyConfiguration constructs an declarative configuration of the SchedulingSpecTemplate type for use with
@@ -70,3 +71,11 @@ func (b *SchedulingSpecTemplateApplyConfiguration) WithDispatchDuration(value *D
 	b.DispatchDuration = value
 	return b
 }
+
+// WithClusterScheduling sets the ClusterScheduling field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClusterScheduling field is set to the value of the last call.
+func (b *SchedulingSpecTemplateApplyConfiguration) WithClusterScheduling(value *ClusterSchedulingSpecApplyConfiguration) *SchedulingSpecTemplateApplyConfiguration {
+	b.ClusterScheduling = value
+	return b
+}
@@ -35,6 +35,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &controllerv1beta1.AppWrapperConditionApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("AppWrapperGenericResource"):
 		return &controllerv1beta1.AppWrapperGenericResourceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("AppWrapperItemStatus"):
+		return &controllerv1beta1.AppWrapperItemStatusApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("AppWrapperResourceList"):
 		return &controllerv1beta1.AppWrapperResourceListApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("AppWrapperService"):
@@ -43,16 +45,46 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &controllerv1beta1.AppWrapperSpecApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("AppWrapperStatus"):
 		return &controllerv1beta1.AppWrapperStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterAgent"):
+		return &controllerv1beta1.ClusterAgentApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterAgentSpec"):
+		return &controllerv1beta1.ClusterAgentSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterAgentStatus"):
+		return &controllerv1beta1.ClusterAgentStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterCapacity"):
+		return &controllerv1beta1.ClusterCapacityApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterCapacityStatus"):
+		return &controllerv1beta1.ClusterCapacityStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterReference"):
+		return &controllerv1beta1.ClusterReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ClusterSchedulingSpec"):
+		return &controllerv1beta1.ClusterSchedulingSpecApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("CustomPodResourceTemplate"):
 		return &controllerv1beta1.CustomPodResourceTemplateApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("DispatchDurationSpec"):
 		return &controllerv1beta1.DispatchDurationSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ItemReadiness"):
+		return &controllerv1beta1.ItemReadinessApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("PendingPodSpec"):
 		return &controllerv1beta1.PendingPodSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("QueuePosition"):
+		return &controllerv1beta1.QueuePositionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("QuotaLeafPosition"):
+		return &controllerv1beta1.QuotaLeafPositionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("QuotaRejection"):
+		return &controllerv1beta1.QuotaRejectionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("QuotaTreeHeadroom"):
+		return &controllerv1beta1.QuotaTreeHeadroomApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("RequeuingTemplate"):
 		return &controllerv1beta1.RequeuingTemplateApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RestartPolicy"):
+		return &controllerv1beta1.RestartPolicyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("SchedulingDiagnostics"):
+		return &controllerv1beta1.SchedulingDiagnosticsApplyConfiguration{}
 	case v1beta1.SchemeGroupVersion.WithKind("SchedulingSpecTemplate"):
 		return &controllerv1beta1.SchedulingSpecTemplateApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("SecretKeyReference"):
+		return &controllerv1beta1.SecretKeyReferenceApplyConfiguration{}
 
 	}
 	return nil
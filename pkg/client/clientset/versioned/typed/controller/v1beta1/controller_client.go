@@ -29,6 +29,8 @@ import (
 type WorkloadV1beta1Interface interface {
 	RESTClient() rest.Interface
 	AppWrappersGetter
+	ClusterAgentsGetter
+	ClusterCapacitiesGetter
 }
 
 // WorkloadV1beta1Client is used to interact with features provided by the workload.codeflare.dev group.
@@ -40,6 +42,14 @@ func (c *WorkloadV1beta1Client) AppWrappers(namespace string) AppWrapperInterfac
 	return newAppWrappers(c, namespace)
 }
 
+func (c *WorkloadV1beta1Client) ClusterAgents() ClusterAgentInterface {
+	return newClusterAgents(c)
+}
+
+func (c *WorkloadV1beta1Client) ClusterCapacities() ClusterCapacityInterface {
+	return newClusterCapacities(c)
+}
+
 // NewForConfig creates a new WorkloadV1beta1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
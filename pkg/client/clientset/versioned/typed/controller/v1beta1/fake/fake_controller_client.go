@@ -32,6 +32,14 @@ func (c *FakeWorkloadV1beta1) AppWrappers(namespace string) v1beta1.AppWrapperIn
 	return &FakeAppWrappers{c, namespace}
 }
 
+func (c *FakeWorkloadV1beta1) ClusterAgents() v1beta1.ClusterAgentInterface {
+	return &FakeClusterAgents{c}
+}
+
+func (c *FakeWorkloadV1beta1) ClusterCapacities() v1beta1.ClusterCapacityInterface {
+	return &FakeClusterCapacities{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeWorkloadV1beta1) RESTClient() rest.Interface {
@@ -19,3 +19,7 @@ limitations under the License.
 package v1beta1
 
 type AppWrapperExpansion interface{}
+
+type ClusterAgentExpansion interface{}
+
+type ClusterCapacityExpansion interface{}
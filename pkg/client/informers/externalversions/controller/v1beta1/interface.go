@@ -26,6 +26,10 @@ import (
 type Interface interface {
 	// AppWrappers returns a AppWrapperInformer.
 	AppWrappers() AppWrapperInformer
+	// ClusterAgents returns a ClusterAgentInformer.
+	ClusterAgents() ClusterAgentInformer
+	// ClusterCapacities returns a ClusterCapacityInformer.
+	ClusterCapacities() ClusterCapacityInformer
 }
 
 type version struct {
@@ -43,3 +47,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) AppWrappers() AppWrapperInformer {
 	return &appWrapperInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ClusterAgents returns a ClusterAgentInformer.
+func (v *version) ClusterAgents() ClusterAgentInformer {
+	return &clusterAgentInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterCapacities returns a ClusterCapacityInformer.
+func (v *version) ClusterCapacities() ClusterCapacityInformer {
+	return &clusterCapacityInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
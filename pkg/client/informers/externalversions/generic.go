@@ -60,6 +60,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=workload.codeflare.dev, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("appwrappers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1beta1().AppWrappers().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clusteragents"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1beta1().ClusterAgents().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("clustercapacities"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Workload().V1beta1().ClusterCapacities().Informer()}, nil
 
 	}
 
@@ -25,3 +25,11 @@ type AppWrapperListerExpansion interface{}
 // AppWrapperNamespaceListerExpansion allows custom methods to be added to
 // AppWrapperNamespaceLister.
 type AppWrapperNamespaceListerExpansion interface{}
+
+// ClusterAgentListerExpansion allows custom methods to be added to
+// ClusterAgentLister.
+type ClusterAgentListerExpansion interface{}
+
+// ClusterCapacityListerExpansion allows custom methods to be added to
+// ClusterCapacityLister.
+type ClusterCapacityListerExpansion interface{}
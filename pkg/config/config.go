@@ -45,6 +45,41 @@ type MCADConfiguration struct {
 	// It defaults to false.
 	// +optional
 	QuotaEnabled *bool `json:"quotaEnabled,omitempty"`
+
+	// concurrencyLimits caps the number of AppWrappers and pods that can be
+	// dispatched at the same time per namespace and per submitting user.
+	// +optional
+	ConcurrencyLimits *ConcurrencyLimits `json:"concurrencyLimits,omitempty"`
+}
+
+// ConcurrencyLimits defines the caps on concurrently dispatched AppWrappers.
+// A nil or zero limit means no limit.
+type ConcurrencyLimits struct {
+	// maxAppWrappersPerNamespace is the maximum number of dispatched AppWrappers
+	// per namespace.
+	// +optional
+	MaxAppWrappersPerNamespace *int32 `json:"maxAppWrappersPerNamespace,omitempty"`
+
+	// maxPodsPerNamespace is the maximum number of pods of dispatched AppWrappers
+	// per namespace.
+	// +optional
+	MaxPodsPerNamespace *int32 `json:"maxPodsPerNamespace,omitempty"`
+
+	// maxAppWrappersPerUser is the maximum number of dispatched AppWrappers
+	// per submitting user.
+	// +optional
+	MaxAppWrappersPerUser *int32 `json:"maxAppWrappersPerUser,omitempty"`
+
+	// maxPodsPerUser is the maximum number of pods of dispatched AppWrappers
+	// per submitting user.
+	// +optional
+	MaxPodsPerUser *int32 `json:"maxPodsPerUser,omitempty"`
+
+	// userAnnotation is the AppWrapper annotation, set by an admission webhook,
+	// that records the submitting user. AppWrappers without it are not subject
+	// to the per-user limits.
+	// +optional
+	UserAnnotation string `json:"userAnnotation,omitempty"`
 }
 
 // MCADConfigurationExtended defines the extended MCAD configuration, e.g.,
@@ -57,4 +92,38 @@ type MCADConfigurationExtended struct {
 
 	// agentConfigs contains paths to agent config file
 	AgentConfigs []string `json:"agentConfigs,omitempty"`
+
+	// agentSelectionPolicy sets how the dispatcher ranks the agent clusters
+	// an AppWrapper fits in: BestFit, Spread or LeastLoaded.
+	// It defaults to LeastLoaded.
+	// +optional
+	AgentSelectionPolicy *string `json:"agentSelectionPolicy,omitempty"`
+
+	// agentFailureThreshold is the number of consecutive failures of metric
+	// queries or informer watches after which an agent cluster is marked
+	// unhealthy and no longer selected for dispatch.
+	// It defaults to 3.
+	// +optional
+	AgentFailureThreshold *int32 `json:"agentFailureThreshold,omitempty"`
+
+	// agentLostGracePeriod defines the duration in seconds an agent cluster
+	// can stay unhealthy before the AppWrappers dispatched to it are requeued.
+	// It defaults to 120.
+	// +optional
+	AgentLostGracePeriod *int32 `json:"agentLostGracePeriod,omitempty"`
+
+	// capacityReportPeriod defines the period in seconds at which a controller
+	// in agent mode publishes the capacity of its cluster for the dispatcher.
+	// A value of 0 disables the reports. It defaults to 30.
+	// +optional
+	CapacityReportPeriod *int32 `json:"capacityReportPeriod,omitempty"`
 }
+
+const (
+	// AgentSelectionBestFit prefers the cluster with the least resources left after dispatch.
+	AgentSelectionBestFit = "BestFit"
+	// AgentSelectionSpread prefers the cluster with the fewest dispatched AppWrappers.
+	AgentSelectionSpread = "Spread"
+	// AgentSelectionLeastLoaded prefers the cluster with the most resources left after dispatch.
+	AgentSelectionLeastLoaded = "LeastLoaded"
+)
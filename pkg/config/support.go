@@ -35,10 +35,48 @@ func (c *MCADConfiguration) BackoffTimeOrDefault(val int32) int32 {
 	return *c.BackoffTime
 }
 
+func (c *MCADConfiguration) HasConcurrencyLimits() bool {
+	l := c.ConcurrencyLimits
+	return l != nil && (isPositive(l.MaxAppWrappersPerNamespace) || isPositive(l.MaxPodsPerNamespace) ||
+		isPositive(l.MaxAppWrappersPerUser) || isPositive(l.MaxPodsPerUser))
+}
+
 func (e *MCADConfigurationExtended) IsDispatcher() bool {
 	return isTrue(e.Dispatcher)
 }
 
+func (e *MCADConfigurationExtended) AgentSelectionPolicyOrDefault() string {
+	if e.AgentSelectionPolicy == nil || len(*e.AgentSelectionPolicy) == 0 {
+		return AgentSelectionLeastLoaded
+	}
+	return *e.AgentSelectionPolicy
+}
+
+func (e *MCADConfigurationExtended) AgentFailureThresholdOrDefault(val int32) int32 {
+	if e.AgentFailureThreshold == nil || *e.AgentFailureThreshold <= 0 {
+		return val
+	}
+	return *e.AgentFailureThreshold
+}
+
+func (e *MCADConfigurationExtended) AgentLostGracePeriodOrDefault(val int32) int32 {
+	if e.AgentLostGracePeriod == nil || *e.AgentLostGracePeriod < 0 {
+		return val
+	}
+	return *e.AgentLostGracePeriod
+}
+
+func (e *MCADConfigurationExtended) CapacityReportPeriodOrDefault(val int32) int32 {
+	if e.CapacityReportPeriod == nil || *e.CapacityReportPeriod < 0 {
+		return val
+	}
+	return *e.CapacityReportPeriod
+}
+
 func isTrue(v *bool) bool {
 	return v != nil && *v
 }
+
+func isPositive(v *int32) bool {
+	return v != nil && *v > 0
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+)
+
+const (
+	namespace = "mcad"
+
+	// QueueActive is the queue label of the AppWrappers waiting to be dispatched
+	QueueActive = "active"
+	// QueueUnschedulable is the queue label of the AppWrappers backing off after a failed dispatch
+	QueueUnschedulable = "unschedulable"
+
+	// OutcomeDispatched is the outcome label of a successful dispatch attempt
+	OutcomeDispatched = "dispatched"
+	// OutcomeFailed is the outcome label of a failed dispatch attempt
+	OutcomeFailed = "failed"
+)
+
+var (
+	// QueueDepth is the number of AppWrappers in the active and the unschedulable queues
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Number of AppWrappers in the scheduling queue.",
+	}, []string{"queue"})
+
+	// TimeInQueue is the time from the first time the controller saw an AppWrapper to its dispatch
+	TimeInQueue = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "time_in_queue_seconds",
+		Help:      "Time an AppWrapper waited in the queue before being dispatched.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 18),
+	})
+
+	// DispatchLatency is the time from the first time the controller saw an AppWrapper to the creation of its resources
+	DispatchLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dispatch_latency_seconds",
+		Help:      "Time from the first time an AppWrapper was seen by the controller to the creation of its resources.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 18),
+	})
+
+	// DispatchAttempts is the number of dispatch attempts by outcome and reason
+	DispatchAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dispatch_attempts_total",
+		Help:      "Number of attempts to dispatch an AppWrapper by outcome and reason.",
+	}, []string{"outcome", "reason"})
+
+	// Preemptions is the number of preempted AppWrappers by reason
+	Preemptions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "preemptions_total",
+		Help:      "Number of AppWrappers preempted by reason.",
+	}, []string{"reason"})
+
+	// Requeuings is the number of AppWrappers returned to the queue by reason
+	Requeuings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requeuings_total",
+		Help:      "Number of AppWrappers returned to the queue by reason.",
+	}, []string{"reason"})
+
+	// AllocatableCapacityDuration is the time taken to compute the resources available in the cluster
+	AllocatableCapacityDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "allocatable_capacity_duration_seconds",
+		Help:      "Time taken to compute the allocatable capacity of the cluster.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	})
+
+	// QuotaNodeQuota is the quota of each node of the quota trees by resource
+	QuotaNodeQuota = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "quota_node_quota",
+		Help:      "Quota of a quota tree node by resource.",
+	}, []string{"tree", "node", "resource"})
+
+	// QuotaNodeAllocated is the amount allocated in each node of the quota trees by resource
+	QuotaNodeAllocated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "quota_node_allocated",
+		Help:      "Amount allocated in a quota tree node by resource.",
+	}, []string{"tree", "node", "resource"})
+
+	// QuotaNodeBorrowed is the amount allocated in each node of the quota trees beyond its quota by resource
+	QuotaNodeBorrowed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "quota_node_borrowed",
+		Help:      "Amount allocated in a quota tree node beyond its quota by resource.",
+	}, []string{"tree", "node", "resource"})
+
+	// Registry holds the metrics of the controller
+	Registry = prometheus.NewRegistry()
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		QueueDepth,
+		TimeInQueue,
+		DispatchLatency,
+		DispatchAttempts,
+		Preemptions,
+		Requeuings,
+		AllocatableCapacityDuration,
+		QuotaNodeQuota,
+		QuotaNodeAllocated,
+		QuotaNodeBorrowed,
+	)
+}
+
+// Handler serves the metrics of the controller
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// SetQueueDepth records the number of AppWrappers in the active and the unschedulable queues
+func SetQueueDepth(active int, unschedulable int) {
+	QueueDepth.WithLabelValues(QueueActive).Set(float64(active))
+	QueueDepth.WithLabelValues(QueueUnschedulable).Set(float64(unschedulable))
+}
+
+// SetQuotaNodeUsage records the quota, allocated and borrowed amounts of the quota tree nodes,
+// the nodes no longer in the quota trees are dropped
+func SetQuotaNodeUsage(usage []quota.QuotaNodeUsage) {
+	QuotaNodeQuota.Reset()
+	QuotaNodeAllocated.Reset()
+	QuotaNodeBorrowed.Reset()
+	for _, node := range usage {
+		for resourceName, allocated := range node.Allocated {
+			nodeQuota := node.Quota[resourceName]
+			borrowed := allocated - nodeQuota
+			if borrowed < 0 {
+				borrowed = 0
+			}
+			QuotaNodeQuota.WithLabelValues(node.Tree, node.Node, resourceName).Set(float64(nodeQuota))
+			QuotaNodeAllocated.WithLabelValues(node.Tree, node.Node, resourceName).Set(float64(allocated))
+			QuotaNodeBorrowed.WithLabelValues(node.Tree, node.Node, resourceName).Set(float64(borrowed))
+		}
+	}
+}
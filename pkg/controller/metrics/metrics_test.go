@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+)
+
+func TestSetQuotaNodeUsage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetQuotaNodeUsage([]quota.QuotaNodeUsage{
+		{
+			Tree:      "tree",
+			Node:      "gold",
+			Quota:     map[string]int64{"cpu": 4000, "memory": 1024},
+			Allocated: map[string]int64{"cpu": 6000, "memory": 512},
+		},
+		{
+			Tree:      "tree",
+			Node:      "silver",
+			Quota:     map[string]int64{"cpu": 2000},
+			Allocated: map[string]int64{"cpu": 0},
+		},
+	})
+
+	g.Expect(testutil.ToFloat64(QuotaNodeQuota.WithLabelValues("tree", "gold", "cpu"))).To(gomega.Equal(4000.0))
+	g.Expect(testutil.ToFloat64(QuotaNodeAllocated.WithLabelValues("tree", "gold", "cpu"))).To(gomega.Equal(6000.0))
+	g.Expect(testutil.ToFloat64(QuotaNodeBorrowed.WithLabelValues("tree", "gold", "cpu"))).To(gomega.Equal(2000.0))
+	g.Expect(testutil.ToFloat64(QuotaNodeBorrowed.WithLabelValues("tree", "gold", "memory"))).To(gomega.Equal(0.0))
+	g.Expect(testutil.CollectAndCount(QuotaNodeQuota)).To(gomega.Equal(3))
+
+	// nodes removed from the quota trees are dropped
+	SetQuotaNodeUsage([]quota.QuotaNodeUsage{
+		{
+			Tree:      "tree",
+			Node:      "silver",
+			Quota:     map[string]int64{"cpu": 2000},
+			Allocated: map[string]int64{"cpu": 1000},
+		},
+	})
+
+	g.Expect(testutil.CollectAndCount(QuotaNodeAllocated)).To(gomega.Equal(1))
+	g.Expect(testutil.ToFloat64(QuotaNodeAllocated.WithLabelValues("tree", "silver", "cpu"))).To(gomega.Equal(1000.0))
+}
+
+func TestSetQueueDepth(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetQueueDepth(3, 2)
+
+	g.Expect(testutil.ToFloat64(QueueDepth.WithLabelValues(QueueActive))).To(gomega.Equal(3.0))
+	g.Expect(testutil.ToFloat64(QueueDepth.WithLabelValues(QueueUnschedulable))).To(gomega.Equal(2.0))
+}
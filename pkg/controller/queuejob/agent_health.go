@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/metrics"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultAgentFailureThreshold is the default number of consecutive failures after which an agent is unhealthy
+	defaultAgentFailureThreshold = 3
+	// defaultAgentLostGracePeriod is the default time in seconds an agent can stay unhealthy before its
+	// AppWrappers are requeued
+	defaultAgentLostGracePeriod = 120
+	// agentHealthPeriod is the period of the agent health checks
+	agentHealthPeriod = 5 * time.Second
+)
+
+// agentHealth is the state of an unhealthy agent cluster
+type agentHealth struct {
+	unhealthySince time.Time
+	// whether the AppWrappers dispatched to the agent cluster have been requeued
+	requeued bool
+}
+
+type agentHealthAction int
+
+const (
+	agentHealthNoAction agentHealthAction = iota
+	agentHealthMarkUnhealthy
+	agentHealthRequeue
+	agentHealthRecover
+)
+
+// nextAgentHealthAction decides the transition of an agent given its consecutive failures and its current
+// state, nil when the agent is healthy.
+func nextAgentHealthAction(failures int, threshold int, health *agentHealth, now time.Time, gracePeriod time.Duration) agentHealthAction {
+	if failures < threshold {
+		if health != nil {
+			return agentHealthRecover
+		}
+		return agentHealthNoAction
+	}
+	if health == nil {
+		return agentHealthMarkUnhealthy
+	}
+	if !health.requeued && now.Sub(health.unhealthySince) >= gracePeriod {
+		return agentHealthRequeue
+	}
+	return agentHealthNoAction
+}
+
+// isAgentHealthy returns whether an agent cluster can be selected for dispatch, agentMutex must be held
+func (qjm *XController) isAgentHealthy(agentId string) bool {
+	_, unhealthy := qjm.unhealthyAgents[agentId]
+	return !unhealthy
+}
+
+// checkAgentHealth marks agents unhealthy after consecutive failures, requeues the AppWrappers dispatched
+// to the agents unhealthy for longer than the grace period, and deletes these AppWrappers from the agents
+// that come back.
+func (qjm *XController) checkAgentHealth() {
+	ctx := context.Background()
+	now := time.Now()
+	var lostAgents []string
+	recoveredAgents := map[*queuejobdispatch.JobClusterAgent][]*arbv1.AppWrapper{}
+
+	qjm.agentMutex.Lock()
+	for agentId, agent := range qjm.agentMap {
+		health := qjm.unhealthyAgents[agentId]
+		failures := agent.ConsecutiveFailures()
+		switch nextAgentHealthAction(failures, qjm.agentFailureThreshold, health, now, qjm.agentLostGracePeriod) {
+		case agentHealthMarkUnhealthy:
+			klog.Warningf("[checkAgentHealth] Agent %s is unhealthy after %d consecutive failures.", agentId, failures)
+			qjm.unhealthyAgents[agentId] = &agentHealth{unhealthySince: now}
+		case agentHealthRequeue:
+			klog.Warningf("[checkAgentHealth] Agent %s has been unhealthy since %v, requeuing its AppWrappers.", agentId, health.unhealthySince)
+			health.requeued = true
+			lostAgents = append(lostAgents, agentId)
+		case agentHealthRecover:
+			klog.Infof("[checkAgentHealth] Agent %s is healthy again.", agentId)
+			delete(qjm.unhealthyAgents, agentId)
+			for _, aw := range qjm.lostAppWrappers[agentId] {
+				// skip the AppWrappers dispatched again to the same agent
+				if key, _ := GetQueueJobKey(aw); qjm.dispatchMap[key] != agentId {
+					recoveredAgents[agent] = append(recoveredAgents[agent], aw)
+				}
+			}
+			delete(qjm.lostAppWrappers, agentId)
+		}
+	}
+	// forget the state of the agents that have been removed
+	for agentId := range qjm.unhealthyAgents {
+		if _, ok := qjm.agentMap[agentId]; !ok {
+			delete(qjm.unhealthyAgents, agentId)
+			delete(qjm.lostAppWrappers, agentId)
+		}
+	}
+	qjm.agentMutex.Unlock()
+
+	for _, agentId := range lostAgents {
+		qjm.requeueAppWrappersOfLostAgent(ctx, agentId)
+	}
+	for agent, appwrappers := range recoveredAgents {
+		for _, aw := range appwrappers {
+			klog.V(2).Infof("[checkAgentHealth] Deleting AppWrapper %s/%s requeued from Agent %s.", aw.Namespace, aw.Name, agent.AgentId)
+			agent.DeleteJob(ctx, aw)
+		}
+	}
+}
+
+// requeueAppWrappersOfLostAgent resets the AppWrappers dispatched to an unhealthy agent, releases their quota
+// and returns them to the queue to be dispatched to another agent. They are deleted from the agent if it recovers.
+// The AppWrappers that could not be requeued are tried again by the next health check.
+func (qjm *XController) requeueAppWrappersOfLostAgent(ctx context.Context, agentId string) {
+	lost, err := qjm.requeueAppWrappersOfAgent(ctx, agentId, "AgentClusterLost",
+		fmt.Sprintf("Agent cluster %s has been unreachable for more than %v.", agentId, qjm.agentLostGracePeriod))
+
+	qjm.agentMutex.Lock()
+	qjm.lostAppWrappers[agentId] = append(qjm.lostAppWrappers[agentId], lost...)
+	if health, ok := qjm.unhealthyAgents[agentId]; ok && err != nil {
+		klog.Errorf("[requeueAppWrappersOfLostAgent] Failed to requeue AppWrappers of Agent %s, retrying with the next health check, err=%v", agentId, err)
+		health.requeued = false
+	}
+	qjm.agentMutex.Unlock()
+}
+
+// requeueAppWrappersOfAgent resets the AppWrappers dispatched to an agent, releases their quota and returns them
+// to the queue to be dispatched to another agent, with the reason and message of their Queueing condition.
+// It returns copies of the requeued AppWrappers as they were dispatched. The AppWrappers whose status cannot be
+// updated remain dispatched to the agent and an error is returned so that they are requeued again later.
+func (qjm *XController) requeueAppWrappersOfAgent(ctx context.Context, agentId string, reason string, message string) ([]*arbv1.AppWrapper, error) {
+	qjm.agentMutex.RLock()
+	var keys []string
+	for key, id := range qjm.dispatchMap {
+		if id == agentId {
+			keys = append(keys, key)
+		}
+	}
+	qjm.agentMutex.RUnlock()
+
+	var lost []*arbv1.AppWrapper
+	var errs *multierror.Error
+	for _, key := range keys {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			qjm.forgetDispatch(key, agentId)
+			continue
+		}
+		aw, err := qjm.getAppWrapper(namespace, name, "[requeueAppWrappersOfAgent] get fresh app wrapper")
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("[requeueAppWrappersOfAgent] Failed to get AppWrapper %s, err=%v", key, err)
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			qjm.forgetDispatch(key, agentId)
+			continue
+		}
+		if aw.Status.State == arbv1.AppWrapperStateCompleted || aw.Status.State == arbv1.AppWrapperStateFailed ||
+			aw.Status.State == arbv1.AppWrapperStateDeleted {
+			qjm.forgetDispatch(key, agentId)
+			continue
+		}
+		dispatched := aw.DeepCopy()
+
+		aw.Status.CanRun = false
+		aw.Status.IsDispatched = false
+		aw.Status.DispatchedAgent = ""
+		aw.Status.State = arbv1.AppWrapperStateEnqueued
+		aw.Status.QueueJobState = arbv1.AppWrapperCondQueueing
+		qjm.addOrUpdateCondition(aw, arbv1.AppWrapperCondQueueing, v1.ConditionTrue, reason, message)
+		if err := qjm.updateStatusInEtcdWithRetry(ctx, aw, "[requeueAppWrappersOfAgent] requeue"); err != nil {
+			// the AppWrapper is still dispatched to the agent, keep its quota and its entry in the dispatch map
+			klog.Errorf("[requeueAppWrappersOfAgent] Failed to update status of AppWrapper %s, err=%v", key, err)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		qjm.forgetDispatch(key, agentId)
+		if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+			qjm.quotaManager.Release(aw)
+		}
+		lost = append(lost, dispatched)
+		klog.Infof("[requeueAppWrappersOfAgent] Requeued AppWrapper %s dispatched to Agent %s: %s", key, agentId, message)
+		metrics.Requeuings.WithLabelValues(reason).Inc()
+		qjm.qjqueue.AddIfNotPresent(aw)
+	}
+	return lost, errs.ErrorOrNil()
+}
+
+// forgetDispatch deletes the entry of an AppWrapper from the dispatch map, unless it was dispatched to another agent since
+func (qjm *XController) forgetDispatch(key string, agentId string) {
+	qjm.agentMutex.Lock()
+	defer qjm.agentMutex.Unlock()
+	if qjm.dispatchMap[key] == agentId {
+		delete(qjm.dispatchMap, key)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clientset "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/clientset/versioned"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+)
+
+func TestNextAgentHealthAction(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	gracePeriod := 2 * time.Minute
+	tests := []struct {
+		name     string
+		failures int
+		health   *agentHealth
+		expected agentHealthAction
+	}{
+		{name: "healthy", failures: 0, health: nil, expected: agentHealthNoAction},
+		{name: "failures below threshold", failures: 2, health: nil, expected: agentHealthNoAction},
+		{name: "failures reach threshold", failures: 3, health: nil, expected: agentHealthMarkUnhealthy},
+		{name: "unhealthy within grace period", failures: 5, health: &agentHealth{unhealthySince: now.Add(-time.Minute)}, expected: agentHealthNoAction},
+		{name: "unhealthy past grace period", failures: 5, health: &agentHealth{unhealthySince: now.Add(-3 * time.Minute)}, expected: agentHealthRequeue},
+		{name: "already requeued", failures: 5, health: &agentHealth{unhealthySince: now.Add(-3 * time.Minute), requeued: true}, expected: agentHealthNoAction},
+		{name: "recovered", failures: 0, health: &agentHealth{unhealthySince: now.Add(-3 * time.Minute), requeued: true}, expected: agentHealthRecover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(nextAgentHealthAction(tt.failures, 3, tt.health, now, gracePeriod)).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+// releaseRecorder records the AppWrappers whose quota is released
+type releaseRecorder struct {
+	quota.QuotaManagerInterface
+	released []string
+}
+
+func (r *releaseRecorder) Release(aw *arbv1.AppWrapper) bool {
+	r.released = append(r.released, aw.Name)
+	return true
+}
+
+func TestRequeueAppWrappersOfLostAgent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the API server fails the status updates until it is told otherwise, then echoes the updated AppWrapper
+	var failUpdates atomic.Bool
+	failUpdates.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failUpdates.Load() {
+			http.Error(w, "etcd unavailable", http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+	arbclients, err := clientset.NewForConfig(&rest.Config{Host: server.URL})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	running := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "default"},
+		Status: arbv1.AppWrapperStatus{State: arbv1.AppWrapperStateActive, CanRun: true, IsDispatched: true, DispatchedAgent: "agent-a"}}
+	quotaManager := &releaseRecorder{}
+	health := &agentHealth{unhealthySince: time.Now().Add(-time.Hour), requeued: true}
+	qjm := &XController{
+		config:           config.MCADConfiguration{QuotaEnabled: pointer.Bool(true)},
+		arbclients:       arbclients,
+		appWrapperLister: newAppWrapperLister(g, running),
+		qjqueue:          NewSchedulingQueue(),
+		quotaManager:     quotaManager,
+		dispatchMap:      map[string]string{"default/running": "agent-a"},
+		unhealthyAgents:  map[string]*agentHealth{"agent-a": health},
+		lostAppWrappers:  map[string][]*arbv1.AppWrapper{},
+	}
+
+	// an AppWrapper whose status cannot be updated stays dispatched to the agent with its quota, and is retried
+	qjm.requeueAppWrappersOfLostAgent(context.Background(), "agent-a")
+	g.Expect(qjm.dispatchMap).To(gomega.Equal(map[string]string{"default/running": "agent-a"}))
+	g.Expect(quotaManager.released).To(gomega.BeEmpty())
+	g.Expect(qjm.lostAppWrappers["agent-a"]).To(gomega.BeEmpty())
+	g.Expect(qjm.qjqueue.IfExist(running)).To(gomega.BeFalse())
+	g.Expect(health.requeued).To(gomega.BeFalse())
+
+	failUpdates.Store(false)
+	health.requeued = true
+	qjm.requeueAppWrappersOfLostAgent(context.Background(), "agent-a")
+	g.Expect(qjm.dispatchMap).To(gomega.BeEmpty())
+	g.Expect(quotaManager.released).To(gomega.Equal([]string{"running"}))
+	g.Expect(qjm.lostAppWrappers["agent-a"]).To(gomega.HaveLen(1))
+	g.Expect(qjm.lostAppWrappers["agent-a"][0].Status.DispatchedAgent).To(gomega.Equal("agent-a"))
+	g.Expect(qjm.qjqueue.IfExist(running)).To(gomega.BeTrue())
+	g.Expect(health.requeued).To(gomega.BeTrue())
+}
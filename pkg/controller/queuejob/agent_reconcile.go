@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"time"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// agentReconcilePeriod is the period of the reconciliation of the AppWrappers dispatched to the agents
+const agentReconcilePeriod = time.Minute
+
+// dispatchedAgent returns the agent the AppWrapper is dispatched to, from the dispatch map or else from
+// its status, agentMutex must be held
+func (qjm *XController) dispatchedAgent(aw *arbv1.AppWrapper) (string, bool) {
+	queueJobKey, _ := GetQueueJobKey(aw)
+	if agentId, ok := qjm.dispatchMap[queueJobKey]; ok {
+		return agentId, true
+	}
+	return aw.Status.DispatchedAgent, len(aw.Status.DispatchedAgent) > 0
+}
+
+// rebuildDispatchMap restores the dispatch map from the agents recorded in the status of the AppWrappers
+func (qjm *XController) rebuildDispatchMap() {
+	appwrappers, err := qjm.appWrapperLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("[rebuildDispatchMap] Failed to list AppWrappers, err=%v", err)
+		return
+	}
+	qjm.agentMutex.Lock()
+	defer qjm.agentMutex.Unlock()
+	for _, aw := range appwrappers {
+		if len(aw.Status.DispatchedAgent) == 0 || isTerminalState(aw.Status.State) {
+			continue
+		}
+		queueJobKey, _ := GetQueueJobKey(aw)
+		qjm.dispatchMap[queueJobKey] = aw.Status.DispatchedAgent
+	}
+	klog.Infof("[rebuildDispatchMap] Restored %d dispatched AppWrappers.", len(qjm.dispatchMap))
+}
+
+// planAgentReconciliation compares the AppWrappers of the dispatcher with their copies in an agent cluster.
+// It returns the AppWrappers dispatched to the agent that are missing from it, and the copies in the agent
+// whose AppWrapper no longer exists or is no longer dispatched to the agent.
+func planAgentReconciliation(agentId string, dispatcherAWs []*arbv1.AppWrapper, agentAWs []*arbv1.AppWrapper) ([]*arbv1.AppWrapper, []*arbv1.AppWrapper) {
+	dispatched := map[string]*arbv1.AppWrapper{}
+	for _, aw := range dispatcherAWs {
+		queueJobKey, _ := GetQueueJobKey(aw)
+		dispatched[queueJobKey] = aw
+	}
+	present := map[string]bool{}
+	var toDelete []*arbv1.AppWrapper
+	for _, aw := range agentAWs {
+		queueJobKey, _ := GetQueueJobKey(aw)
+		present[queueJobKey] = true
+		if d, ok := dispatched[queueJobKey]; !ok || d.Status.DispatchedAgent != agentId {
+			toDelete = append(toDelete, aw)
+		}
+	}
+	var toCreate []*arbv1.AppWrapper
+	for _, aw := range dispatcherAWs {
+		queueJobKey, _ := GetQueueJobKey(aw)
+		if present[queueJobKey] || aw.Status.DispatchedAgent != agentId || !aw.Status.IsDispatched || aw.DeletionTimestamp != nil {
+			continue
+		}
+		if isTerminalState(aw.Status.State) || aw.Status.State == arbv1.AppWrapperStateDeleted {
+			continue
+		}
+		toCreate = append(toCreate, aw)
+	}
+	return toCreate, toDelete
+}
+
+// reconcileAgentJobs re-creates in each healthy agent the AppWrappers dispatched to it that are missing,
+// and deletes the ones that are not dispatched to it anymore
+func (qjm *XController) reconcileAgentJobs() {
+	ctx := context.Background()
+	dispatcherAWs, err := qjm.appWrapperLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("[reconcileAgentJobs] Failed to list AppWrappers, err=%v", err)
+		return
+	}
+
+	qjm.agentMutex.RLock()
+	agents := map[string]*queuejobdispatch.JobClusterAgent{}
+	for agentId, agent := range qjm.agentMap {
+		if qjm.isAgentHealthy(agentId) {
+			agents[agentId] = agent
+		}
+	}
+	qjm.agentMutex.RUnlock()
+
+	for agentId, agent := range agents {
+		agentAWs, synced := agent.ListJobs()
+		if !synced {
+			klog.V(4).Infof("[reconcileAgentJobs] AppWrappers of Agent %s are not synced yet.", agentId)
+			continue
+		}
+		toCreate, toDelete := planAgentReconciliation(agentId, dispatcherAWs, agentAWs)
+		for _, aw := range toCreate {
+			klog.Infof("[reconcileAgentJobs] Re-creating AppWrapper %s/%s missing from Agent %s.", aw.Namespace, aw.Name, agentId)
+			agent.CreateJob(ctx, aw)
+		}
+		for _, aw := range toDelete {
+			klog.Infof("[reconcileAgentJobs] Deleting AppWrapper %s/%s not dispatched to Agent %s.", aw.Namespace, aw.Name, agentId)
+			agent.DeleteJob(ctx, aw)
+		}
+	}
+}
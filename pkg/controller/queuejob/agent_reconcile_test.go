@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func TestPlanAgentReconciliation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	newAW := func(name string, agentId string, isDispatched bool, state arbv1.AppWrapperState) *arbv1.AppWrapper {
+		return &arbv1.AppWrapper{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     arbv1.AppWrapperStatus{DispatchedAgent: agentId, IsDispatched: isDispatched, State: state},
+		}
+	}
+	names := func(aws []*arbv1.AppWrapper) []string {
+		var result []string
+		for _, aw := range aws {
+			result = append(result, aw.Name)
+		}
+		return result
+	}
+
+	dispatcherAWs := []*arbv1.AppWrapper{
+		newAW("running", "agent1", true, arbv1.AppWrapperStateActive),
+		newAW("missing", "agent1", true, arbv1.AppWrapperStateActive),
+		newAW("completed", "agent1", true, arbv1.AppWrapperStateCompleted),
+		newAW("other-agent", "agent2", true, arbv1.AppWrapperStateActive),
+		newAW("requeued", "", false, arbv1.AppWrapperStateEnqueued),
+		newAW("not-yet-dispatched", "agent1", false, arbv1.AppWrapperStateEnqueued),
+	}
+	agentAWs := []*arbv1.AppWrapper{
+		newAW("running", "", true, arbv1.AppWrapperStateActive),
+		newAW("other-agent", "", true, arbv1.AppWrapperStateActive),
+		newAW("requeued", "", true, arbv1.AppWrapperStateActive),
+		newAW("deleted", "", true, arbv1.AppWrapperStateActive),
+		newAW("not-yet-dispatched", "", true, arbv1.AppWrapperStateEnqueued),
+	}
+
+	toCreate, toDelete := planAgentReconciliation("agent1", dispatcherAWs, agentAWs)
+	g.Expect(names(toCreate)).To(gomega.Equal([]string{"missing"}))
+	g.Expect(names(toDelete)).To(gomega.Equal([]string{"other-agent", "requeued", "deleted"}))
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/tracing"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// agentCandidate is an agent cluster the AppWrapper fits in
+type agentCandidate struct {
+	agentId string
+	// resources available to the AppWrapper in the cluster, including preemptable ones
+	available           *clusterstateapi.Resource
+	proposedPreemptions []*arbv1.AppWrapper
+	// number of AppWrappers dispatched to the cluster
+	dispatched int
+	// relative preference for the cluster
+	weight int32
+	// number of AppWrappers waiting in the queue of the cluster, from its capacity report
+	queueLength int32
+	// whether the quota left in the cluster is short of the request, from its capacity report
+	quotaShort bool
+	score      float64
+}
+
+// isClusterEligible checks the cluster scheduling constraints of an AppWrapper against the name and labels
+// of an agent cluster. A nil spec matches every cluster.
+func isClusterEligible(spec *arbv1.ClusterSchedulingSpec, clusterName string, clusterLabels map[string]string) (bool, error) {
+	if spec == nil {
+		return true, nil
+	}
+	if len(spec.Clusters) > 0 {
+		listed := false
+		for _, cluster := range spec.Clusters {
+			if cluster.Name == clusterName {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			return false, nil
+		}
+	}
+	if spec.ClusterSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.ClusterSelector)
+		if err != nil {
+			return false, err
+		}
+		return selector.Matches(labels.Set(clusterLabels)), nil
+	}
+	return true, nil
+}
+
+// freeFraction returns the average fraction of the available resources left after the request is
+// dispatched, over the resource dimensions available in the cluster.
+func freeFraction(request *clusterstateapi.Resource, available *clusterstateapi.Resource) float64 {
+	sum, n := 0.0, 0
+	if available.MilliCPU > 0 {
+		sum += (available.MilliCPU - request.MilliCPU) / available.MilliCPU
+		n++
+	}
+	if available.Memory > 0 {
+		sum += (available.Memory - request.Memory) / available.Memory
+		n++
+	}
+	if available.GPU > 0 {
+		sum += float64(available.GPU-request.GPU) / float64(available.GPU)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// waitPenalty lowers the score of the clusters the AppWrapper would wait in: up to a point for the AppWrappers
+// already queued in the cluster, and a point when the quota left in the cluster is short of the request.
+func waitPenalty(c *agentCandidate) float64 {
+	penalty := float64(c.queueLength) / float64(c.queueLength+1)
+	if c.quotaShort {
+		penalty++
+	}
+	return penalty
+}
+
+// scoreAgents scores the candidates according to the selection policy, scaled by their weight, less their wait
+// penalty, and sorts them by decreasing score.
+// Ties are broken by agent id so that the order is deterministic.
+func scoreAgents(policy string, request *clusterstateapi.Resource, candidates []*agentCandidate) {
+	for _, c := range candidates {
+		free := freeFraction(request, c.available)
+		weight := float64(c.weight)
+		switch policy {
+		case config.AgentSelectionBestFit:
+			c.score = (1 - free) * weight
+		case config.AgentSelectionSpread:
+			// fewest dispatched AppWrappers per unit of weight first, most free resources among equals
+			c.score = -float64(c.dispatched)/weight + free/2
+		default:
+			c.score = free * weight
+		}
+		c.score -= waitPenalty(c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].agentId < candidates[j].agentId
+	})
+}
+
+// podsFitFreeNodeSlots checks that each pod fits in the free capacity of a node. The slots are the largest free
+// node capacities of a capacity report, in decreasing order of CPU: a pod that fits in none of them may still fit
+// in a node not listed, unless it requests more CPU than the first slot or the slots cover all the nodes.
+func podsFitFreeNodeSlots(pods []*clusterstateapi.Resource, slots []v1.ResourceList, allNodes bool) bool {
+	resources := make([]*clusterstateapi.Resource, 0, len(slots))
+	for _, slot := range slots {
+		resources = append(resources, clusterstateapi.NewResource(slot))
+	}
+	for _, pod := range pods {
+		fits := false
+		for _, slot := range resources {
+			if pod.LessEqual(slot) {
+				fits = true
+				break
+			}
+		}
+		if !fits && (allNodes || len(resources) == 0 || pod.MilliCPU > resources[0].MilliCPU) {
+			return false
+		}
+	}
+	return true
+}
+
+// isQuotaHeadroomShort checks whether the quota left in one of the quota trees of a capacity report is short of
+// the request, an AppWrapper is charged to every quota tree of the cluster
+func isQuotaHeadroomShort(request *clusterstateapi.Resource, headroom []arbv1.QuotaTreeHeadroom) bool {
+	for _, tree := range headroom {
+		for resourceName, left := range tree.Resources {
+			var demand float64
+			switch name := strings.ToLower(resourceName); {
+			case strings.Contains(name, "cpu"):
+				demand = request.MilliCPU
+			case strings.Contains(name, "memory"):
+				demand = request.Memory
+			case strings.Contains(name, "gpu"):
+				demand = float64(request.GPU)
+			}
+			if demand > float64(left) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// agentSnapshot is the state of an agent cluster copied under agentMutex, so that the agent cluster is
+// evaluated without holding the lock
+type agentSnapshot struct {
+	agentId    string
+	agent      *queuejobdispatch.JobClusterAgent
+	healthy    bool
+	dispatched int
+}
+
+// snapshotAgents returns the state of the agent clusters in the order of agentList
+func (qjm *XController) snapshotAgents() []agentSnapshot {
+	qjm.agentMutex.RLock()
+	defer qjm.agentMutex.RUnlock()
+	dispatched := qjm.countDispatchedPerAgent()
+	agents := make([]agentSnapshot, 0, len(qjm.agentList))
+	for _, agentId := range qjm.agentList {
+		agent, ok := qjm.agentMap[agentId]
+		if !ok {
+			continue
+		}
+		agents = append(agents, agentSnapshot{
+			agentId:    agentId,
+			agent:      agent,
+			healthy:    qjm.isAgentHealthy(agentId),
+			dispatched: dispatched[agentId],
+		})
+	}
+	return agents
+}
+
+// countDispatchedPerAgent returns the number of AppWrappers dispatched to each agent cluster, agentMutex must be held
+func (qjm *XController) countDispatchedPerAgent() map[string]int {
+	counts := map[string]int{}
+	for _, agentId := range qjm.dispatchMap {
+		counts[agentId]++
+	}
+	return counts
+}
+
+// chooseAgent evaluates every agent cluster for the AppWrapper: clusters excluded by its cluster scheduling
+// constraints or without enough resources or quota are filtered out, the others are tried in score order.
+// The agent clusters are evaluated on a snapshot, agentMutex is not held while the quota is allocated and
+// the preempted AppWrappers are updated.
+// It returns the selected agent id, or an empty string if there is none, together with the reason and a
+// message describing the decision or the reasons each cluster was rejected for.
+func (qjm *XController) chooseAgent(ctx context.Context, qj *arbv1.AppWrapper) (string, string, string) {
+	ctx, span := tracing.Start(ctx, "chooseAgent")
+	defer span.End()
+	qjAggrResources := qjm.GetAggregatedResources(qj)
+	klog.V(2).Infof("[chooseAgent] Aggregated Resources of XQJ %s/%s: %v\n", qj.Namespace, qj.Name, qjAggrResources)
+
+	var candidates []*agentCandidate
+	var rejections []string
+	eligible := 0
+	for _, snapshot := range qjm.snapshotAgents() {
+		agentId, agent := snapshot.agentId, snapshot.agent
+		if ok, err := isClusterEligible(qj.Spec.SchedSpec.ClusterScheduling, agent.ClusterName, agent.ClusterLabels); !ok {
+			klog.V(2).Infof("[chooseAgent] Agent %s is not eligible for AppWrapper %s/%s, err=%v\n", agentId, qj.Namespace, qj.Name, err)
+			if err != nil {
+				return "", "InvalidClusterScheduling", fmt.Sprintf("Invalid cluster scheduling constraints: %v.", err)
+			}
+			continue
+		}
+		eligible++
+		if !snapshot.healthy {
+			klog.V(2).Infof("[chooseAgent] Agent %s is unhealthy\n", agentId)
+			rejections = append(rejections, fmt.Sprintf("%s: unhealthy", agentId))
+			continue
+		}
+		aggrResources := agent.AggrResources()
+		if aggrResources == nil {
+			aggrResources = clusterstateapi.EmptyResource()
+		}
+		resources, proposedPreemptions := qjm.getAggregatedAvailableResourcesPriority(aggrResources, qj.Status.SystemPriority, qj, agentId)
+		klog.V(4).Infof("[chooseAgent] Aggr Resources of Agent %s: %v\n", agentId, resources)
+		if !qjAggrResources.LessEqual(resources) {
+			klog.V(2).Infof("[chooseAgent] Agent %s does not have enough resources\n", agentId)
+			rejections = append(rejections, fmt.Sprintf("%s: insufficient resources", agentId))
+			continue
+		}
+		candidate := &agentCandidate{
+			agentId:             agentId,
+			available:           resources,
+			proposedPreemptions: proposedPreemptions,
+			dispatched:          snapshot.dispatched,
+			weight:              agent.Weight,
+		}
+		if report := agent.CapacityReport(); report != nil {
+			allNodes := len(report.LargestFreeNodeSlots) < maxFreeNodeSlots
+			if !podsFitFreeNodeSlots(qjm.GetAggregatedResourcesPerGenericItem(qj), report.LargestFreeNodeSlots, allNodes) {
+				klog.V(2).Infof("[chooseAgent] Agent %s has no node large enough for the pods of AppWrapper %s/%s\n", agentId, qj.Namespace, qj.Name)
+				rejections = append(rejections, fmt.Sprintf("%s: no node large enough", agentId))
+				continue
+			}
+			candidate.queueLength = report.QueueLength
+			candidate.quotaShort = isQuotaHeadroomShort(qjAggrResources, report.QuotaHeadroom)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if eligible == 0 {
+		return "", "NoEligibleCluster", "No agent cluster satisfies the cluster scheduling constraints of the AppWrapper."
+	}
+
+	scoreAgents(qjm.agentSelectionPolicy, qjAggrResources, candidates)
+	for _, c := range candidates {
+		klog.V(2).Infof("[chooseAgent] Agent %s has enough resources, score=%.3f\n", c.agentId, c.score)
+		if qjm.config.IsQuotaEnabled() {
+			if qjm.quotaManager == nil {
+				klog.Errorf("[chooseAgent] Quota evaluation is enable but not initialize. AppWrapper %s/%s does not have enough quota\n", qj.Namespace, qj.Name)
+				rejections = append(rejections, fmt.Sprintf("%s: quota evaluation not initialized", c.agentId))
+				continue
+			}
+			// the quota is allocated in the dispatcher quota forest and tied to the agent
+			fits, preemptAWs, msg := qjm.quotaManager.FitsInCluster(qj, qjAggrResources, c.agentId, c.proposedPreemptions)
+			if !fits {
+				klog.V(2).Infof("[chooseAgent] AppWrapper %s/%s does not have enough quota in agent %s: %s\n", qj.Namespace, qj.Name, c.agentId, msg)
+				rejections = append(rejections, fmt.Sprintf("%s: insufficient quota", c.agentId))
+				continue
+			}
+			klog.V(2).Infof("[chooseAgent] AppWrapper %s/%s has enough quota.\n", qj.Namespace, qj.Name)
+			qjm.preemptAWJobs(ctx, qj, preemptAWs)
+		}
+		return c.agentId, "ClusterSelected", fmt.Sprintf("Selected cluster %s with %s score %.3f.", c.agentId, qjm.agentSelectionPolicy, c.score)
+	}
+	return "", "AppWrapperNotRunnable.", fmt.Sprintf("Cannot find a cluster with enough resources to dispatch AppWrapper. Rejected clusters: %s.", strings.Join(rejections, "; "))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	arblisters "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+)
+
+func TestScoreAgents(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	request := &clusterstateapi.Resource{MilliCPU: 1000, Memory: 1000}
+	newCandidates := func() []*agentCandidate {
+		return []*agentCandidate{
+			{agentId: "small", available: &clusterstateapi.Resource{MilliCPU: 2000, Memory: 2000}, dispatched: 0, weight: 1},
+			{agentId: "large", available: &clusterstateapi.Resource{MilliCPU: 8000, Memory: 8000}, dispatched: 3, weight: 1},
+			{agentId: "medium", available: &clusterstateapi.Resource{MilliCPU: 4000, Memory: 4000}, dispatched: 1, weight: 1},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		weights    map[string]int32
+		queued     map[string]int32
+		quotaShort map[string]bool
+		expected   []string
+	}{
+		{name: "best fit", policy: config.AgentSelectionBestFit, expected: []string{"small", "medium", "large"}},
+		{name: "least loaded", policy: config.AgentSelectionLeastLoaded, expected: []string{"large", "medium", "small"}},
+		{name: "spread", policy: config.AgentSelectionSpread, expected: []string{"small", "medium", "large"}},
+		{name: "weighted best fit", policy: config.AgentSelectionBestFit, weights: map[string]int32{"large": 5}, expected: []string{"large", "small", "medium"}},
+		{name: "weighted least loaded", policy: config.AgentSelectionLeastLoaded, weights: map[string]int32{"small": 2}, expected: []string{"small", "large", "medium"}},
+		{name: "weighted spread", policy: config.AgentSelectionSpread, weights: map[string]int32{"large": 4}, expected: []string{"small", "large", "medium"}},
+		{name: "least loaded with queued AppWrappers", policy: config.AgentSelectionLeastLoaded, queued: map[string]int32{"large": 5}, expected: []string{"medium", "small", "large"}},
+		{name: "best fit with short quota", policy: config.AgentSelectionBestFit, quotaShort: map[string]bool{"small": true}, expected: []string{"medium", "large", "small"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := newCandidates()
+			for _, c := range candidates {
+				if w, ok := tt.weights[c.agentId]; ok {
+					c.weight = w
+				}
+				c.queueLength = tt.queued[c.agentId]
+				c.quotaShort = tt.quotaShort[c.agentId]
+			}
+			scoreAgents(tt.policy, request, candidates)
+			var order []string
+			for _, c := range candidates {
+				order = append(order, c.agentId)
+			}
+			g.Expect(order).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+func TestFreeFraction(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(freeFraction(&clusterstateapi.Resource{MilliCPU: 500, GPU: 1}, &clusterstateapi.Resource{MilliCPU: 1000, GPU: 4})).To(gomega.BeNumerically("~", 0.625))
+	g.Expect(freeFraction(&clusterstateapi.Resource{}, &clusterstateapi.Resource{})).To(gomega.BeZero())
+}
+
+func TestPodsFitFreeNodeSlots(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	slots := []v1.ResourceList{
+		{v1.ResourceCPU: resource.MustParse("8"), v1.ResourceMemory: resource.MustParse("16Gi")},
+		{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("64Gi")},
+	}
+	pod := func(milliCPU float64, memory string) *clusterstateapi.Resource {
+		quantity := resource.MustParse(memory)
+		return &clusterstateapi.Resource{MilliCPU: milliCPU, Memory: float64(quantity.Value())}
+	}
+
+	g.Expect(podsFitFreeNodeSlots([]*clusterstateapi.Resource{pod(8000, "16Gi"), pod(4000, "32Gi")}, slots, true)).To(gomega.BeTrue())
+	g.Expect(podsFitFreeNodeSlots([]*clusterstateapi.Resource{pod(6000, "32Gi")}, slots, true)).To(gomega.BeFalse())
+	// a node not listed may have the memory of the pod, but none has its CPU
+	g.Expect(podsFitFreeNodeSlots([]*clusterstateapi.Resource{pod(6000, "32Gi")}, slots, false)).To(gomega.BeTrue())
+	g.Expect(podsFitFreeNodeSlots([]*clusterstateapi.Resource{pod(9000, "1Gi")}, slots, false)).To(gomega.BeFalse())
+	g.Expect(podsFitFreeNodeSlots([]*clusterstateapi.Resource{pod(1000, "1Gi")}, nil, true)).To(gomega.BeFalse())
+	g.Expect(podsFitFreeNodeSlots(nil, nil, true)).To(gomega.BeTrue())
+}
+
+func TestIsQuotaHeadroomShort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	headroom := []arbv1.QuotaTreeHeadroom{{Tree: "tree", Resources: map[string]int64{"cpu": 4000, "memory": 1024, "nvidia.com/gpu": 1}}}
+
+	g.Expect(isQuotaHeadroomShort(&clusterstateapi.Resource{MilliCPU: 4000, Memory: 1024, GPU: 1}, headroom)).To(gomega.BeFalse())
+	g.Expect(isQuotaHeadroomShort(&clusterstateapi.Resource{MilliCPU: 1000, GPU: 2}, headroom)).To(gomega.BeTrue())
+	g.Expect(isQuotaHeadroomShort(&clusterstateapi.Resource{MilliCPU: 1000, GPU: 2}, nil)).To(gomega.BeFalse())
+}
+
+func TestKubeconfigSecretKey(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(kubeconfigSecretKey(arbv1.SecretKeyReference{Namespace: "ns", Name: "agent1"})).To(gomega.Equal(arbv1.DefaultKubeconfigSecretKey))
+	g.Expect(kubeconfigSecretKey(arbv1.SecretKeyReference{Namespace: "ns", Name: "agent1", Key: "config"})).To(gomega.Equal("config"))
+}
+
+func TestIsClusterEligible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	clusterLabels := map[string]string{"region": "eu", "gpu": "a100"}
+	tests := []struct {
+		name     string
+		spec     *arbv1.ClusterSchedulingSpec
+		expected bool
+		err      bool
+	}{
+		{name: "no constraints", spec: nil, expected: true},
+		{name: "listed cluster", spec: &arbv1.ClusterSchedulingSpec{Clusters: []arbv1.ClusterReference{{Name: "other"}, {Name: "cluster1"}}}, expected: true},
+		{name: "unlisted cluster", spec: &arbv1.ClusterSchedulingSpec{Clusters: []arbv1.ClusterReference{{Name: "other"}}}, expected: false},
+		{name: "matching selector", spec: &arbv1.ClusterSchedulingSpec{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}}, expected: true},
+		{name: "non matching selector", spec: &arbv1.ClusterSchedulingSpec{ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us"}}}, expected: false},
+		{name: "listed cluster with non matching selector", spec: &arbv1.ClusterSchedulingSpec{Clusters: []arbv1.ClusterReference{{Name: "cluster1"}},
+			ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "us"}}}, expected: false},
+		{name: "invalid selector", spec: &arbv1.ClusterSchedulingSpec{ClusterSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "region", Operator: "Bogus"}}}}, expected: false, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eligible, err := isClusterEligible(tt.spec, "cluster1", clusterLabels)
+			g.Expect(eligible).To(gomega.Equal(tt.expected))
+			g.Expect(err != nil).To(gomega.Equal(tt.err))
+		})
+	}
+}
+
+func TestSnapshotAgents(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	qjm := &XController{
+		agentList: []string{"agent-a", "agent-b", "agent-removed"},
+		agentMap: map[string]*queuejobdispatch.JobClusterAgent{
+			"agent-a": {AgentId: "agent-a"},
+			"agent-b": {AgentId: "agent-b"},
+		},
+		dispatchMap:     map[string]string{"default/aw-1": "agent-a", "default/aw-2": "agent-a"},
+		unhealthyAgents: map[string]*agentHealth{"agent-b": {unhealthySince: time.Now()}},
+	}
+
+	agents := qjm.snapshotAgents()
+	g.Expect(agents).To(gomega.HaveLen(2))
+	g.Expect(agents[0].agentId).To(gomega.Equal("agent-a"))
+	g.Expect(agents[0].healthy).To(gomega.BeTrue())
+	g.Expect(agents[0].dispatched).To(gomega.Equal(2))
+	g.Expect(agents[1].agentId).To(gomega.Equal("agent-b"))
+	g.Expect(agents[1].healthy).To(gomega.BeFalse())
+	g.Expect(agents[1].dispatched).To(gomega.BeZero())
+}
+
+func TestEnqueueClusterAgent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	qjm := &XController{clusterAgentQueue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+	defer qjm.clusterAgentQueue.ShutDown()
+	ca := &arbv1.ClusterAgent{ObjectMeta: metav1.ObjectMeta{Name: "agent-a"}}
+	qjm.enqueueClusterAgent(ca)
+	qjm.updateClusterAgent(ca, ca)
+	qjm.enqueueClusterAgent(cache.DeletedFinalStateUnknown{Key: "agent-b", Obj: ca})
+	g.Expect(qjm.clusterAgentQueue.Len()).To(gomega.Equal(2))
+}
+
+func TestSyncDeletedClusterAgent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	completed := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "completed", Namespace: "default"},
+		Status: arbv1.AppWrapperStatus{State: arbv1.AppWrapperStateCompleted}}
+	qjm := &XController{
+		agentList: []string{"agent-a", "agent-b"},
+		agentMap: map[string]*queuejobdispatch.JobClusterAgent{
+			"agent-a": {AgentId: "agent-a"},
+			"agent-b": {AgentId: "agent-b"},
+		},
+		registeredAgents:   map[string]*registeredAgent{"agent-a": {stopCh: make(chan struct{})}},
+		dispatchMap:        map[string]string{"default/completed": "agent-a", "default/deleted": "agent-a", "default/other": "agent-b"},
+		appWrapperLister:   newAppWrapperLister(g, completed),
+		clusterAgentLister: arblisters.NewClusterAgentLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+	}
+
+	g.Expect(qjm.syncClusterAgent(context.Background(), "agent-a")).To(gomega.Succeed())
+	g.Expect(qjm.agentList).To(gomega.Equal([]string{"agent-b"}))
+	g.Expect(qjm.agentMap).NotTo(gomega.HaveKey("agent-a"))
+	g.Expect(qjm.registeredAgents).To(gomega.BeEmpty())
+	// the AppWrappers dispatched to the removed agent are no longer tied to it
+	g.Expect(qjm.dispatchMap).To(gomega.Equal(map[string]string{"default/other": "agent-b"}))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// agentConditionPrefix prefixes the message of the conditions mirrored from an agent cluster
+func agentConditionPrefix(agentId string) string {
+	return fmt.Sprintf("Agent %s: ", agentId)
+}
+
+// isTerminalState returns whether the AppWrapper state is final
+func isTerminalState(state arbv1.AppWrapperState) bool {
+	return state == arbv1.AppWrapperStateCompleted || state == arbv1.AppWrapperStateFailed
+}
+
+// mergeAgentStatus mirrors the status of the copy of an AppWrapper in an agent cluster into the dispatcher
+// AppWrapper: state, pod counters, resource totals, pending pod conditions and conditions. The conditions of
+// the agent replace the ones previously mirrored from the same agent, with the agent id prefixed to their
+// message. The scheduling fields owned by the dispatcher are left unchanged.
+func mergeAgentStatus(aw *arbv1.AppWrapper, agentAW *arbv1.AppWrapper, agentId string) {
+	status, agentStatus := &aw.Status, &agentAW.Status
+	if len(agentStatus.State) > 0 {
+		status.State = agentStatus.State
+	}
+	status.Pending = agentStatus.Pending
+	status.Running = agentStatus.Running
+	status.Succeeded = agentStatus.Succeeded
+	status.Failed = agentStatus.Failed
+	status.TotalCPU = agentStatus.TotalCPU
+	status.TotalMemory = agentStatus.TotalMemory
+	status.TotalGPU = agentStatus.TotalGPU
+	status.PendingPodConditions = nil
+	for _, cond := range agentStatus.PendingPodConditions {
+		status.PendingPodConditions = append(status.PendingPodConditions, *cond.DeepCopy())
+	}
+
+	prefix := agentConditionPrefix(agentId)
+	var conditions []arbv1.AppWrapperCondition
+	for _, cond := range status.Conditions {
+		if !strings.HasPrefix(cond.Message, prefix) {
+			conditions = append(conditions, cond)
+		}
+	}
+	for _, cond := range agentStatus.Conditions {
+		mirrored := *cond.DeepCopy()
+		mirrored.Message = prefix + cond.Message
+		conditions = append(conditions, mirrored)
+	}
+	status.Conditions = conditions
+}
+
+// syncAgentStatus mirrors the status of an AppWrapper reported by an agent cluster into the dispatcher
+// AppWrapper. Reports from an agent the AppWrapper is not dispatched to are ignored. When the AppWrapper
+// terminates in the agent cluster its quota is released and its copy is deleted from the agent.
+func (cc *XController) syncAgentStatus(ctx context.Context, queueJobInEtcd *arbv1.AppWrapper, queueJobFromAgent *arbv1.AppWrapper) error {
+	agentId := queueJobFromAgent.Annotations[queuejobdispatch.AgentIdAnnotation]
+	cc.agentMutex.RLock()
+	dispatchedAgentId, _ := cc.dispatchedAgent(queueJobInEtcd)
+	agent := cc.agentMap[agentId]
+	cc.agentMutex.RUnlock()
+	// ignore stale copies left on an agent the AppWrapper was requeued from
+	if !queueJobInEtcd.Status.IsDispatched || dispatchedAgentId != agentId {
+		klog.V(4).Infof("[syncAgentStatus] Ignoring status of AppWrapper %s/%s from Agent %s, dispatched to '%s'.",
+			queueJobInEtcd.Namespace, queueJobInEtcd.Name, agentId, dispatchedAgentId)
+		return nil
+	}
+	// already terminated, later reports come from the deletion of the copy in the agent
+	if isTerminalState(queueJobInEtcd.Status.State) {
+		return nil
+	}
+
+	aw := queueJobInEtcd.DeepCopy()
+	mergeAgentStatus(aw, queueJobFromAgent, agentId)
+	if equality.Semantic.DeepEqual(aw.Status, queueJobInEtcd.Status) {
+		return nil
+	}
+	terminated := isTerminalState(aw.Status.State)
+	aw.Status.FilterIgnore = true // mirror agent status only
+	if err := cc.updateStatusInEtcdWithRetry(ctx, aw, "[syncAgentStatus] mirror agent status"); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	klog.V(3).Infof("[syncAgentStatus] Mirrored status of AppWrapper %s/%s from Agent %s: State=%s Running=%d Succeeded=%d Failed=%d",
+		aw.Namespace, aw.Name, agentId, aw.Status.State, aw.Status.Running, aw.Status.Succeeded, aw.Status.Failed)
+
+	if terminated {
+		klog.Infof("[syncAgentStatus] AppWrapper %s/%s terminated in Agent %s with state %s, releasing its resources.",
+			aw.Namespace, aw.Name, agentId, aw.Status.State)
+		if aw.Status.State == arbv1.AppWrapperStateCompleted {
+			cc.eventRecorder.Eventf(aw, v1.EventTypeNormal, EventReasonCompleted, "AppWrapper completed in Agent %s.", agentId)
+		} else {
+			cc.eventRecorder.Eventf(aw, v1.EventTypeWarning, EventReasonFailed, "AppWrapper failed in Agent %s.", agentId)
+		}
+		if cc.config.IsQuotaEnabled() && cc.quotaManager != nil {
+			cc.quotaManager.Release(aw)
+		}
+		queueJobKey, _ := GetQueueJobKey(aw)
+		cc.agentMutex.Lock()
+		delete(cc.dispatchMap, queueJobKey)
+		cc.agentMutex.Unlock()
+		if agent != nil {
+			agent.DeleteJob(ctx, aw)
+		}
+	}
+	return nil
+}
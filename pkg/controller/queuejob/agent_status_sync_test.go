@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func TestMergeAgentStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	aw := &arbv1.AppWrapper{Status: arbv1.AppWrapperStatus{
+		CanRun:          true,
+		IsDispatched:    true,
+		DispatchedAgent: "agent1",
+		State:           arbv1.AppWrapperStateEnqueued,
+		QueueJobState:   arbv1.AppWrapperCondDispatched,
+		Conditions: []arbv1.AppWrapperCondition{
+			{Type: arbv1.AppWrapperCondDispatched, Reason: "ClusterSelected", Message: "Selected cluster agent1."},
+			{Type: arbv1.AppWrapperCondQueueing, Reason: "AwaitingHeadOfLine", Message: "Agent agent1: old"},
+			{Type: arbv1.AppWrapperCondRunning, Reason: "PodsRunning", Message: "Agent agent0: from a previous dispatch"},
+		},
+	}}
+	agentAW := &arbv1.AppWrapper{Status: arbv1.AppWrapperStatus{
+		State:         arbv1.AppWrapperStateActive,
+		QueueJobState: arbv1.AppWrapperCondRunning,
+		Running:       2,
+		Pending:       1,
+		TotalCPU:      2000,
+		TotalGPU:      2,
+		PendingPodConditions: []arbv1.PendingPodSpec{
+			{PodName: "pod-3"},
+		},
+		Conditions: []arbv1.AppWrapperCondition{
+			{Type: arbv1.AppWrapperCondRunning, Reason: "PodsRunning", Message: "2 pods running."},
+		},
+	}}
+
+	mergeAgentStatus(aw, agentAW, "agent1")
+
+	g.Expect(aw.Status.State).To(gomega.Equal(arbv1.AppWrapperStateActive))
+	g.Expect(aw.Status.Running).To(gomega.Equal(int32(2)))
+	g.Expect(aw.Status.Pending).To(gomega.Equal(int32(1)))
+	g.Expect(aw.Status.TotalCPU).To(gomega.Equal(int32(2000)))
+	g.Expect(aw.Status.TotalGPU).To(gomega.Equal(int32(2)))
+	g.Expect(aw.Status.PendingPodConditions).To(gomega.HaveLen(1))
+	// scheduling fields owned by the dispatcher are kept
+	g.Expect(aw.Status.CanRun).To(gomega.BeTrue())
+	g.Expect(aw.Status.DispatchedAgent).To(gomega.Equal("agent1"))
+	g.Expect(aw.Status.QueueJobState).To(gomega.Equal(arbv1.AppWrapperCondDispatched))
+	var messages []string
+	for _, cond := range aw.Status.Conditions {
+		messages = append(messages, cond.Message)
+	}
+	g.Expect(messages).To(gomega.Equal([]string{"Selected cluster agent1.", "Agent agent0: from a previous dispatch", "Agent agent1: 2 pods running."}))
+	// the agent copy is not modified
+	g.Expect(agentAW.Status.Conditions[0].Message).To(gomega.Equal("2 pods running."))
+}
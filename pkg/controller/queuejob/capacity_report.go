@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultCapacityReportPeriod is the default period in seconds of the capacity reports of an agent cluster
+	defaultCapacityReportPeriod = 30
+	// maxFreeNodeSlots is the number of largest free node capacities in a capacity report
+	maxFreeNodeSlots = 10
+	// capacityReportStalePeriods is the number of report periods after which a capacity report is stale
+	capacityReportStalePeriods = 3
+)
+
+// capacityReportMaxAge returns the age after which the capacity report of an agent cluster is stale, the agent
+// clusters are expected to report their capacity at the period configured for the dispatcher
+func (qjm *XController) capacityReportMaxAge() time.Duration {
+	period := qjm.capacityReportPeriod
+	if period <= 0 {
+		period = defaultCapacityReportPeriod * time.Second
+	}
+	return capacityReportStalePeriods * period
+}
+
+// freeNodeResources returns the allocatable resources of the schedulable nodes left after the requests of the
+// active pods not created by AppWrappers, by node name
+func freeNodeResources(nodes []v1.Node, pods []v1.Pod) map[string]v1.ResourceList {
+	free := map[string]v1.ResourceList{}
+	for _, node := range nodes {
+		// skip unschedulable nodes
+		if node.Spec.Unschedulable {
+			continue
+		}
+		free[node.Name] = node.Status.Allocatable.DeepCopy()
+	}
+	for _, pod := range pods {
+		if _, ok := pod.GetLabels()["appwrappers.mcad.ibm.com"]; ok || pod.Status.Phase == v1.PodFailed || pod.Status.Phase == v1.PodSucceeded {
+			continue
+		}
+		nodeFree, ok := free[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for name, request := range container.Resources.Requests {
+				if available, found := nodeFree[name]; found {
+					available.Sub(request)
+					nodeFree[name] = available
+				}
+			}
+		}
+	}
+	return free
+}
+
+// sumResources adds up the resources of the nodes
+func sumResources(free map[string]v1.ResourceList) v1.ResourceList {
+	total := v1.ResourceList{}
+	for _, nodeFree := range free {
+		for name, quantity := range nodeFree {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+	return total
+}
+
+// largestFreeNodeSlots returns the count largest free node capacities, in decreasing order of CPU, then
+// memory, then GPU
+func largestFreeNodeSlots(free map[string]v1.ResourceList, count int) []v1.ResourceList {
+	names := make([]string, 0, len(free))
+	for name := range free {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := free[names[i]], free[names[j]]
+		for _, resource := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory, clusterstateapi.GPUResourceName} {
+			qa, qb := a[resource], b[resource]
+			if c := qa.Cmp(qb); c != 0 {
+				return c > 0
+			}
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > count {
+		names = names[:count]
+	}
+	slots := make([]v1.ResourceList, 0, len(names))
+	for _, name := range names {
+		slots = append(slots, free[name])
+	}
+	return slots
+}
+
+// quotaHeadroom converts the quota left in each quota tree, sorted by tree name
+func quotaHeadroom(headroom map[string]map[string]int64) []arbv1.QuotaTreeHeadroom {
+	var trees []arbv1.QuotaTreeHeadroom
+	for tree, resources := range headroom {
+		trees = append(trees, arbv1.QuotaTreeHeadroom{Tree: tree, Resources: resources})
+	}
+	sort.Slice(trees, func(i, j int) bool { return trees[i].Tree < trees[j].Tree })
+	return trees
+}
+
+// buildCapacityReport computes the capacity of the cluster available to AppWrappers
+func (qjm *XController) buildCapacityReport(ctx context.Context) (*arbv1.ClusterCapacityStatus, error) {
+	nodes, err := qjm.clients.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := qjm.clients.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	free := freeNodeResources(nodes.Items, pods.Items)
+	status := &arbv1.ClusterCapacityStatus{
+		Allocatable:          sumResources(free),
+		LargestFreeNodeSlots: largestFreeNodeSlots(free, maxFreeNodeSlots),
+		QueueLength:          int32(qjm.qjqueue.Length()),
+		LastUpdateTime:       metav1.Now(),
+	}
+	if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+		status.QuotaHeadroom = quotaHeadroom(qjm.quotaManager.GetQuotaHeadroom())
+	}
+	return status, nil
+}
+
+// reportCapacity publishes the capacity of the cluster in the ClusterCapacity read by the dispatcher
+func (qjm *XController) reportCapacity() {
+	ctx := context.Background()
+	status, err := qjm.buildCapacityReport(ctx)
+	if err != nil {
+		klog.Errorf("[reportCapacity] Failed to compute the capacity of the cluster, err=%v", err)
+		return
+	}
+	client := qjm.arbclients.WorkloadV1beta1().ClusterCapacities()
+	capacity, err := client.Get(ctx, arbv1.ClusterCapacityName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		capacity, err = client.Create(ctx, &arbv1.ClusterCapacity{ObjectMeta: metav1.ObjectMeta{Name: arbv1.ClusterCapacityName}}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		klog.Errorf("[reportCapacity] Failed to get ClusterCapacity %s, err=%v", arbv1.ClusterCapacityName, err)
+		return
+	}
+	capacity.Status = *status
+	if _, err := client.UpdateStatus(ctx, capacity, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("[reportCapacity] Failed to update ClusterCapacity %s, err=%v", arbv1.ClusterCapacityName, err)
+		return
+	}
+	klog.V(4).Infof("[reportCapacity] Reported allocatable capacity %v and queue length %d.", status.Allocatable, status.QueueLength)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFreeNodeResources(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	node := func(name string, cpu string, memory string, unschedulable bool) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Unschedulable: unschedulable},
+			Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(cpu),
+				v1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+	pod := func(nodeName string, cpu string, labels map[string]string, phase v1.PodPhase) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: v1.PodSpec{NodeName: nodeName, Containers: []v1.Container{{
+				Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+			}}},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	nodes := []v1.Node{
+		node("small", "2", "4Gi", false),
+		node("large", "8", "16Gi", false),
+		node("medium", "4", "32Gi", false),
+		node("cordoned", "16", "64Gi", true),
+	}
+	pods := []v1.Pod{
+		pod("large", "3", nil, v1.PodRunning),
+		pod("large", "1", map[string]string{"appwrappers.mcad.ibm.com": "aw"}, v1.PodRunning),
+		pod("medium", "2", nil, v1.PodSucceeded),
+		pod("cordoned", "1", nil, v1.PodRunning),
+	}
+
+	free := freeNodeResources(nodes, pods)
+	g.Expect(free).To(gomega.HaveLen(3))
+	large := free["large"]
+	g.Expect(large.Cpu().MilliValue()).To(gomega.Equal(int64(5000)))
+	medium := free["medium"]
+	g.Expect(medium.Cpu().MilliValue()).To(gomega.Equal(int64(4000)))
+
+	total := sumResources(free)
+	g.Expect(total.Cpu().MilliValue()).To(gomega.Equal(int64(11000)))
+	g.Expect(total.Memory().Value()).To(gomega.Equal(int64(52 * 1024 * 1024 * 1024)))
+
+	tests := []struct {
+		name     string
+		count    int
+		expected []string
+	}{
+		{name: "all slots", count: 10, expected: []string{"5", "4", "2"}},
+		{name: "largest slots", count: 2, expected: []string{"5", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cpus []string
+			for _, slot := range largestFreeNodeSlots(free, tt.count) {
+				cpus = append(cpus, slot.Cpu().String())
+			}
+			g.Expect(cpus).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+func TestQuotaHeadroom(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	trees := quotaHeadroom(map[string]map[string]int64{
+		"context-root": {"cpu": 1000},
+		"another-root": {"memory": 2048},
+	})
+	g.Expect(trees).To(gomega.HaveLen(2))
+	g.Expect(trees[0].Tree).To(gomega.Equal("another-root"))
+	g.Expect(trees[1].Resources).To(gomega.Equal(map[string]int64{"cpu": 1000}))
+	g.Expect(quotaHeadroom(nil)).To(gomega.BeEmpty())
+}
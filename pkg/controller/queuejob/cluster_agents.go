@@ -0,0 +1,222 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+)
+
+// clusterAgentHealthPeriod is the period of the connection health checks of agent clusters registered
+// through ClusterAgent resources
+const clusterAgentHealthPeriod = 30 * time.Second
+
+// registeredAgent is an agent created from a ClusterAgent resource
+type registeredAgent struct {
+	// generation of the ClusterAgent the agent was created from
+	generation int64
+	stopCh     chan struct{}
+}
+
+// kubeconfigSecretKey returns the key of the kubeconfig in the Secret referenced by a ClusterAgent
+func kubeconfigSecretKey(ref arbv1.SecretKeyReference) string {
+	if ref.Key == "" {
+		return arbv1.DefaultKubeconfigSecretKey
+	}
+	return ref.Key
+}
+
+// enqueueClusterAgent queues the registration of the agent of a ClusterAgent, it is the handler of the events of
+// the ClusterAgents: creating an agent reads a Secret and connects to the agent cluster, which must not block the
+// informer
+func (cc *XController) enqueueClusterAgent(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("[enqueueClusterAgent] Failed to get the key of %#v, err=%v", obj, err)
+		return
+	}
+	cc.clusterAgentQueue.Add(key)
+}
+
+// updateClusterAgent queues the re-registration of the agent when its ClusterAgent changes
+func (cc *XController) updateClusterAgent(oldObj, newObj interface{}) {
+	cc.enqueueClusterAgent(newObj)
+}
+
+func (cc *XController) clusterAgentWorker() {
+	for cc.processNextClusterAgent() {
+	}
+}
+
+func (cc *XController) processNextClusterAgent() bool {
+	key, quit := cc.clusterAgentQueue.Get()
+	if quit {
+		return false
+	}
+	defer cc.clusterAgentQueue.Done(key)
+	if err := cc.syncClusterAgent(context.Background(), key.(string)); err != nil {
+		klog.Errorf("[processNextClusterAgent] Failed to register agent cluster %s, err=%v", key, err)
+		cc.clusterAgentQueue.AddRateLimited(key)
+		return true
+	}
+	cc.clusterAgentQueue.Forget(key)
+	return true
+}
+
+// syncClusterAgent registers the agent of a ClusterAgent, or removes it when the ClusterAgent is deleted
+func (cc *XController) syncClusterAgent(ctx context.Context, name string) error {
+	ca, err := cc.clusterAgentLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return cc.removeClusterAgent(ctx, name)
+	}
+	if err != nil {
+		return err
+	}
+	return cc.registerClusterAgent(ctx, ca)
+}
+
+// removeClusterAgent tears down the agent of a deleted ClusterAgent and requeues the AppWrappers dispatched to it.
+// An error is returned when some AppWrappers could not be requeued, they are requeued again when the ClusterAgent
+// is synced again.
+func (cc *XController) removeClusterAgent(ctx context.Context, agentId string) error {
+	cc.agentMutex.Lock()
+	cc.removeAgentLocked(agentId)
+	cc.agentMutex.Unlock()
+	_, err := cc.requeueAppWrappersOfAgent(ctx, agentId, "AgentClusterRemoved", fmt.Sprintf("Agent cluster %s has been removed.", agentId))
+	return err
+}
+
+// registerClusterAgent creates the agent of a ClusterAgent, unless it already exists for the current generation.
+// An existing agent for an older generation is replaced once the new agent is created, it is kept if the new
+// agent cannot be created.
+func (cc *XController) registerClusterAgent(ctx context.Context, ca *arbv1.ClusterAgent) error {
+	cc.agentMutex.RLock()
+	registered, ok := cc.registeredAgents[ca.Name]
+	cc.agentMutex.RUnlock()
+	if ok && registered.generation == ca.Generation {
+		return nil
+	}
+
+	agent, err := cc.newClusterAgent(ctx, ca)
+	if err != nil {
+		cc.updateClusterAgentHealth(ctx, ca, err)
+		return err
+	}
+	cc.agentMutex.Lock()
+	cc.removeAgentLocked(ca.Name)
+	stopCh := make(chan struct{})
+	cc.agentMap[ca.Name] = agent
+	cc.agentList = append(cc.agentList, ca.Name)
+	cc.registeredAgents[ca.Name] = &registeredAgent{generation: ca.Generation, stopCh: stopCh}
+	go agent.Run(stopCh)
+	cc.agentMutex.Unlock()
+	klog.Infof("[registerClusterAgent] Registered agent cluster %s, generation %d.", ca.Name, ca.Generation)
+
+	cc.updateClusterAgentHealth(ctx, ca, agent.CheckHealth())
+	return nil
+}
+
+// newClusterAgent creates an agent from the kubeconfig in the Secret referenced by a ClusterAgent
+func (cc *XController) newClusterAgent(ctx context.Context, ca *arbv1.ClusterAgent) (*queuejobdispatch.JobClusterAgent, error) {
+	ref := ca.Spec.KubeconfigSecretRef
+	secret, err := cc.clients.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key := kubeconfigSecretKey(ref)
+	kubeconfig, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, key)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return queuejobdispatch.NewJobClusterAgentForConfig(ca.Name, ca.Spec.DeploymentName, ca.Name, ca.Spec.Labels,
+		ca.Spec.Weight, restConfig, cc.agentEventQueue, cc.capacityReportMaxAge())
+}
+
+// removeAgentLocked stops the informers of a registered agent and removes it, agentMutex must be held.
+// The AppWrappers dispatched to it are left to the caller.
+func (cc *XController) removeAgentLocked(agentId string) {
+	registered, ok := cc.registeredAgents[agentId]
+	if !ok {
+		return
+	}
+	close(registered.stopCh)
+	delete(cc.registeredAgents, agentId)
+	delete(cc.agentMap, agentId)
+	for i, id := range cc.agentList {
+		if id == agentId {
+			cc.agentList = append(cc.agentList[:i], cc.agentList[i+1:]...)
+			break
+		}
+	}
+	klog.Infof("[removeAgentLocked] Removed agent cluster %s.", agentId)
+}
+
+// updateClusterAgentHealth records the connection health of an agent cluster in the status of its ClusterAgent
+// when it changes
+func (cc *XController) updateClusterAgentHealth(ctx context.Context, ca *arbv1.ClusterAgent, healthErr error) {
+	healthy, message := healthErr == nil, ""
+	if healthErr != nil {
+		message = healthErr.Error()
+	}
+	if ca.Status.Healthy == healthy && ca.Status.Message == message && !ca.Status.LastTransitionTime.IsZero() {
+		return
+	}
+	updated := ca.DeepCopy()
+	if updated.Status.Healthy != healthy || updated.Status.LastTransitionTime.IsZero() {
+		updated.Status.LastTransitionTime = metav1.Now()
+	}
+	updated.Status.Healthy = healthy
+	updated.Status.Message = message
+	if _, err := cc.arbclients.WorkloadV1beta1().ClusterAgents().UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("[updateClusterAgentHealth] Failed to update status of ClusterAgent %s, err=%v", ca.Name, err)
+	}
+}
+
+// checkClusterAgents queues the registration of the ClusterAgents whose agent could not be created yet and
+// refreshes the connection health of the others
+func (cc *XController) checkClusterAgents() {
+	ctx := context.Background()
+	clusterAgents, err := cc.clusterAgentLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("[checkClusterAgents] Failed to list ClusterAgents, err=%v", err)
+		return
+	}
+	for _, ca := range clusterAgents {
+		cc.agentMutex.RLock()
+		agent, ok := cc.agentMap[ca.Name]
+		cc.agentMutex.RUnlock()
+		if !ok {
+			cc.clusterAgentQueue.Add(ca.Name)
+			continue
+		}
+		cc.updateClusterAgentHealth(ctx, ca, agent.CheckHealth())
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// concurrencyCount is the number of dispatched AppWrappers and of their pods of a namespace or of a user
+type concurrencyCount struct {
+	appwrappers int32
+	pods        int32
+}
+
+// concurrencyEntry is the contribution of a dispatched AppWrapper to the counts of its namespace and of its user.
+// The pods are counted once per generation of the AppWrapper.
+type concurrencyEntry struct {
+	uid        types.UID
+	generation int64
+	namespace  string
+	user       string
+	pods       int32
+}
+
+// concurrencyUsage counts the dispatched AppWrappers and their pods per namespace and per user, to check the
+// concurrency limits without listing all AppWrappers. It is maintained from the events of the AppWrapper informer.
+type concurrencyUsage struct {
+	mutex sync.RWMutex
+	// annotation of the AppWrappers with their user, no user is counted when empty
+	userAnnotation string
+	// dispatched AppWrappers: namespace/name
+	appwrappers map[string]*concurrencyEntry
+	namespaces  map[string]*concurrencyCount
+	users       map[string]*concurrencyCount
+}
+
+func newConcurrencyUsage(userAnnotation string) *concurrencyUsage {
+	return &concurrencyUsage{
+		userAnnotation: userAnnotation,
+		appwrappers:    map[string]*concurrencyEntry{},
+		namespaces:     map[string]*concurrencyCount{},
+		users:          map[string]*concurrencyCount{},
+	}
+}
+
+// getUser returns the user of an AppWrapper, empty if users are not counted or the AppWrapper has no user
+func (u *concurrencyUsage) getUser(aw *arbv1.AppWrapper) string {
+	if len(u.userAnnotation) == 0 {
+		return ""
+	}
+	return aw.Annotations[u.userAnnotation]
+}
+
+// update counts an AppWrapper while it is dispatched and stops counting it once it terminates.
+// podCount returns the number of pods of an AppWrapper, it is only called when the generation of the AppWrapper changes.
+func (u *concurrencyUsage) update(aw *arbv1.AppWrapper, podCount func(*arbv1.AppWrapper) int32) {
+	key := aw.Namespace + "/" + aw.Name
+	if !isCountedForConcurrency(aw) {
+		u.remove(key)
+		return
+	}
+	u.mutex.RLock()
+	entry, found := u.appwrappers[key]
+	u.mutex.RUnlock()
+	var pods int32
+	if found && entry.uid == aw.UID && entry.generation == aw.Generation {
+		pods = entry.pods
+	} else {
+		pods = podCount(aw)
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.removeLocked(key)
+	entry = &concurrencyEntry{uid: aw.UID, generation: aw.Generation, namespace: aw.Namespace, user: u.getUser(aw), pods: pods}
+	u.appwrappers[key] = entry
+	u.addLocked(u.namespaces, entry.namespace, entry.pods)
+	if len(entry.user) > 0 {
+		u.addLocked(u.users, entry.user, entry.pods)
+	}
+}
+
+// remove stops counting an AppWrapper
+func (u *concurrencyUsage) remove(key string) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.removeLocked(key)
+}
+
+func (u *concurrencyUsage) removeLocked(key string) {
+	entry, found := u.appwrappers[key]
+	if !found {
+		return
+	}
+	delete(u.appwrappers, key)
+	u.subtractLocked(u.namespaces, entry.namespace, entry.pods)
+	if len(entry.user) > 0 {
+		u.subtractLocked(u.users, entry.user, entry.pods)
+	}
+}
+
+func (u *concurrencyUsage) addLocked(counts map[string]*concurrencyCount, name string, pods int32) {
+	count, found := counts[name]
+	if !found {
+		count = &concurrencyCount{}
+		counts[name] = count
+	}
+	count.appwrappers++
+	count.pods += pods
+}
+
+func (u *concurrencyUsage) subtractLocked(counts map[string]*concurrencyCount, name string, pods int32) {
+	count, found := counts[name]
+	if !found {
+		return
+	}
+	count.appwrappers--
+	count.pods -= pods
+	if count.appwrappers <= 0 {
+		delete(counts, name)
+	}
+}
+
+// getCounts returns the counts of the namespace and of the user of an AppWrapper, without the AppWrapper itself
+func (u *concurrencyUsage) getCounts(aw *arbv1.AppWrapper) (namespaceCount concurrencyCount, userCount concurrencyCount) {
+	user := u.getUser(aw)
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	if count, found := u.namespaces[aw.Namespace]; found {
+		namespaceCount = *count
+	}
+	if count, found := u.users[user]; found && len(user) > 0 {
+		userCount = *count
+	}
+	if entry, found := u.appwrappers[aw.Namespace+"/"+aw.Name]; found {
+		namespaceCount.appwrappers--
+		namespaceCount.pods -= entry.pods
+		if len(user) > 0 && entry.user == user {
+			userCount.appwrappers--
+			userCount.pods -= entry.pods
+		}
+	}
+	return namespaceCount, userCount
+}
+
+// getPodCount returns the number of pods of an AppWrapper
+func (cc *XController) getPodCount(aw *arbv1.AppWrapper) int32 {
+	return int32(len(cc.GetAggregatedResourcesPerGenericItem(aw)))
+}
+
+// updateConcurrencyUsage is the handler of the additions and updates of AppWrappers for the concurrency limits
+func (cc *XController) updateConcurrencyUsage(obj interface{}) {
+	aw, ok := obj.(*arbv1.AppWrapper)
+	if !ok {
+		return
+	}
+	cc.concurrencyUsage.update(aw, cc.getPodCount)
+}
+
+// deleteConcurrencyUsage is the handler of the deletions of AppWrappers for the concurrency limits
+func (cc *XController) deleteConcurrencyUsage(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("[deleteConcurrencyUsage] Unable to get the key of deleted AppWrapper, err=%v", err)
+		return
+	}
+	cc.concurrencyUsage.remove(key)
+}
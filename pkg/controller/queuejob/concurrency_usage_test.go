@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func TestConcurrencyUsage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	podCounts := 0
+	podCount := func(*arbv1.AppWrapper) int32 {
+		podCounts++
+		return 3
+	}
+	dispatched := func(name string, user string) *arbv1.AppWrapper {
+		return &arbv1.AppWrapper{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID("uid-" + name), Generation: 1,
+				Annotations: map[string]string{"user": user}},
+			Status: arbv1.AppWrapperStatus{CanRun: true, State: arbv1.AppWrapperStateActive},
+		}
+	}
+	usage := newConcurrencyUsage("user")
+	first := dispatched("first", "alice")
+	second := dispatched("second", "bob")
+	usage.update(first, podCount)
+	usage.update(second, podCount)
+	queued := dispatched("queued", "alice")
+	queued.Status.CanRun = false
+	usage.update(queued, podCount)
+
+	namespaceCount, userCount := usage.getCounts(queued)
+	g.Expect(namespaceCount).To(gomega.Equal(concurrencyCount{appwrappers: 2, pods: 6}))
+	g.Expect(userCount).To(gomega.Equal(concurrencyCount{appwrappers: 1, pods: 3}))
+	// an AppWrapper is not counted against itself
+	namespaceCount, userCount = usage.getCounts(first)
+	g.Expect(namespaceCount).To(gomega.Equal(concurrencyCount{appwrappers: 1, pods: 3}))
+	g.Expect(userCount).To(gomega.Equal(concurrencyCount{}))
+
+	// the pods are only counted again when the generation changes
+	g.Expect(podCounts).To(gomega.Equal(2))
+	first.Status.Running = 3
+	first.Annotations["user"] = "bob"
+	usage.update(first, podCount)
+	g.Expect(podCounts).To(gomega.Equal(2))
+	_, userCount = usage.getCounts(queued)
+	g.Expect(userCount).To(gomega.Equal(concurrencyCount{}))
+	first.Generation = 2
+	usage.update(first, podCount)
+	g.Expect(podCounts).To(gomega.Equal(3))
+
+	// terminated and deleted AppWrappers are no longer counted
+	first.Status.State = arbv1.AppWrapperStateCompleted
+	usage.update(first, podCount)
+	namespaceCount, _ = usage.getCounts(queued)
+	g.Expect(namespaceCount).To(gomega.Equal(concurrencyCount{appwrappers: 1, pods: 3}))
+	cc := &XController{concurrencyUsage: usage}
+	cc.deleteConcurrencyUsage(cache.DeletedFinalStateUnknown{Key: "ns/second", Obj: second})
+	namespaceCount, userCount = usage.getCounts(queued)
+	g.Expect(namespaceCount).To(gomega.Equal(concurrencyCount{}))
+	g.Expect(userCount).To(gomega.Equal(concurrencyCount{}))
+	g.Expect(usage.namespaces).To(gomega.BeEmpty())
+	g.Expect(usage.users).To(gomega.BeEmpty())
+}
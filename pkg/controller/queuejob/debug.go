@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
+)
+
+// debugAppWrapper is the state of an AppWrapper as seen by the scheduling loop
+type debugAppWrapper struct {
+	Namespace      string                       `json:"namespace"`
+	Name           string                       `json:"name"`
+	SystemPriority float64                      `json:"systemPriority"`
+	State          arbv1.AppWrapperState        `json:"state,omitempty"`
+	QueueJobState  string                       `json:"queueJobState,omitempty"`
+	CanRun         bool                         `json:"canRun"`
+	QueuePosition  *arbv1.QueuePosition         `json:"queuePosition,omitempty"`
+	Diagnostics    *arbv1.SchedulingDiagnostics `json:"schedulingDiagnostics,omitempty"`
+}
+
+// debugQueue is the content of the sub queues of the scheduling queue, in scheduling order
+type debugQueue struct {
+	Active        []debugAppWrapper `json:"active"`
+	Unschedulable []debugAppWrapper `json:"unschedulable"`
+}
+
+// debugCapacity is the last capacity computed to dispatch AppWrappers in agent mode
+type debugCapacity struct {
+	Capacity v1.ResourceList `json:"capacity,omitempty"`
+	Time     *time.Time      `json:"time,omitempty"`
+}
+
+// debugAgent is the state of an agent cluster in dispatcher mode
+type debugAgent struct {
+	ID          string          `json:"id"`
+	ClusterName string          `json:"clusterName,omitempty"`
+	Resources   v1.ResourceList `json:"resources,omitempty"`
+	Healthy     bool            `json:"healthy"`
+	AppWrappers []string        `json:"appWrappers,omitempty"`
+}
+
+// debugAgents is the state of the agent clusters and the agent cluster of every dispatched AppWrapper
+type debugAgents struct {
+	Agents      []debugAgent      `json:"agents"`
+	DispatchMap map[string]string `json:"dispatchMap"`
+}
+
+func newDebugAppWrapper(aw *arbv1.AppWrapper, positions map[string]*arbv1.QueuePosition) debugAppWrapper {
+	key, _ := GetQueueJobKey(aw)
+	return debugAppWrapper{
+		Namespace:      aw.Namespace,
+		Name:           aw.Name,
+		SystemPriority: aw.Status.SystemPriority,
+		State:          aw.Status.State,
+		QueueJobState:  string(aw.Status.QueueJobState),
+		CanRun:         aw.Status.CanRun,
+		QueuePosition:  positions[key],
+		Diagnostics:    aw.Status.SchedulingDiagnostics,
+	}
+}
+
+// DebugHandler returns the read-only JSON API exposing the internal state of the controller under /debug/
+func (qjm *XController) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/queue", qjm.debugGet(qjm.debugQueue))
+	mux.HandleFunc("/debug/scheduling", qjm.debugGet(qjm.debugScheduling))
+	mux.HandleFunc("/debug/capacity", qjm.debugGet(qjm.debugCapacity))
+	mux.HandleFunc("/debug/quota", qjm.debugGet(qjm.debugQuota))
+	mux.HandleFunc("/debug/agents", qjm.debugGet(qjm.debugAgents))
+	return mux
+}
+
+// debugGet serves the JSON rendering of the value returned by get to GET requests
+func (qjm *XController) debugGet(get func() interface{}) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			resp.Header().Set("Allow", http.MethodGet)
+			http.Error(resp, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(resp)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(get()); err != nil {
+			klog.Errorf("[debugGet] Failed to write the response to %s, err=%v", req.URL.Path, err)
+		}
+	}
+}
+
+func (qjm *XController) debugQueue() interface{} {
+	positions := qjm.QueuePositions()
+	queue := debugQueue{Active: []debugAppWrapper{}, Unschedulable: []debugAppWrapper{}}
+	var queued []*arbv1.AppWrapper
+	for _, key := range qjm.qjqueue.Keys() {
+		if aw := qjm.listerCopy(key); aw != nil {
+			queued = append(queued, aw)
+		}
+	}
+	SortBySchedulingOrder(queued)
+	for _, aw := range queued {
+		if qjm.qjqueue.IfExistUnschedulableQ(aw) {
+			queue.Unschedulable = append(queue.Unschedulable, newDebugAppWrapper(aw, positions))
+		} else {
+			queue.Active = append(queue.Active, newDebugAppWrapper(aw, positions))
+		}
+	}
+	return queue
+}
+
+func (qjm *XController) debugScheduling() interface{} {
+	aw := qjm.listerCopy(qjm.getSchedulingKey())
+	if aw == nil {
+		return nil
+	}
+	return newDebugAppWrapper(aw, qjm.QueuePositions())
+}
+
+func (qjm *XController) debugCapacity() interface{} {
+	qjm.lastCapacityMutex.RLock()
+	defer qjm.lastCapacityMutex.RUnlock()
+	if qjm.lastCapacity == nil {
+		return debugCapacity{}
+	}
+	computed := qjm.lastCapacityTime
+	return debugCapacity{Capacity: resourceToResourceList(qjm.lastCapacity), Time: &computed}
+}
+
+func (qjm *XController) debugQuota() interface{} {
+	if !qjm.config.IsQuotaEnabled() || qjm.quotaManager == nil {
+		return []quota.QuotaNodeUsage{}
+	}
+	usage := qjm.quotaManager.GetQuotaNodeUsage()
+	if usage == nil {
+		return []quota.QuotaNodeUsage{}
+	}
+	return usage
+}
+
+func (qjm *XController) debugAgents() interface{} {
+	qjm.agentMutex.RLock()
+	defer qjm.agentMutex.RUnlock()
+	agents := debugAgents{Agents: []debugAgent{}, DispatchMap: map[string]string{}}
+	dispatched := map[string][]string{}
+	for key, agentId := range qjm.dispatchMap {
+		agents.DispatchMap[key] = agentId
+		dispatched[agentId] = append(dispatched[agentId], key)
+	}
+	for _, agentId := range qjm.agentList {
+		agent, ok := qjm.agentMap[agentId]
+		if !ok {
+			continue
+		}
+		_, unhealthy := qjm.unhealthyAgents[agentId]
+		sort.Strings(dispatched[agentId])
+		agents.Agents = append(agents.Agents, debugAgent{
+			ID:          agentId,
+			ClusterName: agent.ClusterName,
+			Resources:   resourceToResourceList(agent.AggrResources()),
+			Healthy:     !unhealthy,
+			AppWrappers: dispatched[agentId],
+		})
+	}
+	return agents
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
+)
+
+func debugRequest(g *gomega.WithT, handler http.Handler, method string, path string, v interface{}) int {
+	req := httptest.NewRequest(method, path, nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code == http.StatusOK {
+		g.Expect(rr.Header().Get("Content-Type")).To(gomega.Equal("application/json"))
+		g.Expect(json.Unmarshal(rr.Body.Bytes(), v)).To(gomega.Succeed())
+	}
+	return rr.Code
+}
+
+func TestDebugQueue(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	high := queuedAppWrapper("high", 5, now, nil)
+	low := queuedAppWrapper("low", 1, now, nil)
+	qjm := &XController{qjqueue: NewSchedulingQueue(), appWrapperLister: newAppWrapperLister(g, high, low)}
+	g.Expect(qjm.qjqueue.Add(high)).To(gomega.Succeed())
+	g.Expect(qjm.qjqueue.AddUnschedulableIfNotPresent(low)).To(gomega.Succeed())
+	qjm.queuePositions = map[string]*arbv1.QueuePosition{"default/high": {Global: 1}}
+	handler := qjm.DebugHandler()
+
+	var queue debugQueue
+	g.Expect(debugRequest(g, handler, http.MethodGet, "/debug/queue", &queue)).To(gomega.Equal(http.StatusOK))
+	g.Expect(queue.Active).To(gomega.HaveLen(1))
+	g.Expect(queue.Active[0].Name).To(gomega.Equal("high"))
+	g.Expect(queue.Active[0].SystemPriority).To(gomega.Equal(float64(5)))
+	g.Expect(queue.Active[0].QueuePosition.Global).To(gomega.Equal(int32(1)))
+	g.Expect(queue.Unschedulable).To(gomega.HaveLen(1))
+	g.Expect(queue.Unschedulable[0].Name).To(gomega.Equal("low"))
+
+	var scheduling *debugAppWrapper
+	g.Expect(debugRequest(g, handler, http.MethodGet, "/debug/scheduling", &scheduling)).To(gomega.Equal(http.StatusOK))
+	g.Expect(scheduling).To(gomega.BeNil())
+
+	// the API is read-only
+	g.Expect(debugRequest(g, handler, http.MethodPost, "/debug/queue", nil)).To(gomega.Equal(http.StatusMethodNotAllowed))
+}
+
+func TestDebugAgents(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	agentA := &queuejobdispatch.JobClusterAgent{AgentId: "agent-a", ClusterName: "a"}
+	agentA.SetAggrResources(&clusterstateapi.Resource{MilliCPU: 2000, GPU: 1})
+	qjm := &XController{
+		agentList: []string{"agent-a", "agent-b"},
+		agentMap: map[string]*queuejobdispatch.JobClusterAgent{
+			"agent-a": agentA,
+			"agent-b": {AgentId: "agent-b", ClusterName: "b"},
+		},
+		dispatchMap:     map[string]string{"default/aw": "agent-a"},
+		unhealthyAgents: map[string]*agentHealth{"agent-b": {unhealthySince: time.Now()}},
+	}
+
+	var agents debugAgents
+	g.Expect(debugRequest(g, qjm.DebugHandler(), http.MethodGet, "/debug/agents", &agents)).To(gomega.Equal(http.StatusOK))
+	g.Expect(agents.DispatchMap).To(gomega.Equal(map[string]string{"default/aw": "agent-a"}))
+	g.Expect(agents.Agents).To(gomega.HaveLen(2))
+	g.Expect(agents.Agents[0].Healthy).To(gomega.BeTrue())
+	g.Expect(agents.Agents[0].AppWrappers).To(gomega.Equal([]string{"default/aw"}))
+	g.Expect(agents.Agents[0].Resources.Cpu().MilliValue()).To(gomega.Equal(int64(2000)))
+	g.Expect(agents.Agents[1].Healthy).To(gomega.BeFalse())
+	g.Expect(agents.Agents[1].Resources).To(gomega.BeEmpty())
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/clientset/versioned/scheme"
+)
+
+// eventComponent is the source component of the events recorded for AppWrappers
+const eventComponent = "mcad-controller"
+
+// Reasons of the events recorded for AppWrappers
+const (
+	EventReasonHeadOfLine               = "HeadOfLine"
+	EventReasonBackoff                  = "Backoff"
+	EventReasonDispatched               = "Dispatched"
+	EventReasonItemCreationFailed       = "ItemCreationFailed"
+	EventReasonMinPodsNotRunning        = "MinPodsNotRunning"
+	EventReasonDispatchDeadlineExceeded = "DispatchDeadlineExceeded"
+	EventReasonPodsFailedScheduling     = "PodsFailedScheduling"
+	EventReasonPreempted                = "Preempted"
+	EventReasonCompleted                = "Completed"
+	EventReasonFailed                   = "Failed"
+	EventReasonRestarting               = "Restarting"
+)
+
+// newEventRecorder creates a recorder of the events of AppWrappers
+func newEventRecorder(client kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: eventComponent})
+}
@@ -222,6 +222,15 @@ func (h *Heap) List() []interface{} {
 	return list
 }
 
+// ListKeys returns the keys of all the items.
+func (h *Heap) ListKeys() []string {
+	keys := make([]string, 0, len(h.data.items))
+	for key := range h.data.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // newHeap returns a Heap which can be used to queue up items to process.
 func newHeap(keyFn KeyFunc, lessFn LessFunc) *Heap {
 	return &Heap{
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// holdCompletionEvaluationPeriod is the period of the evaluation of the AppWrappers in the RunningHoldCompletion state.
+// Their completion depends on their pods, which are not watched.
+var holdCompletionEvaluationPeriod = 5 * time.Second
+
+// needsItemEvaluation returns true if the generic items of an AppWrapper report its completion or failure or if
+// later stages of the AppWrapper wait for the readiness of its items
+func needsItemEvaluation(aw *arbv1.AppWrapper) bool {
+	for _, genericItem := range aw.Spec.AggrResources.GenericItems {
+		if len(genericItem.CompletionStatus) > 0 || len(genericItem.FailureStatus) > 0 {
+			return true
+		}
+	}
+	_, hasNextStage := getNextStage(aw, aw.Status.CurrentStage)
+	return hasNextStage
+}
+
+// enqueueItemEvaluation queues the evaluation of the generic items of an AppWrapper, it is the handler of the events
+// of the items
+func (cc *XController) enqueueItemEvaluation(namespace string, appwrapperName string) {
+	if cc.itemEventQueue == nil {
+		return
+	}
+	cc.itemEventQueue.Add(namespace + "/" + appwrapperName)
+}
+
+// enqueueItemEvaluationAfter queues the evaluation of the generic items of an AppWrapper after a delay
+func (cc *XController) enqueueItemEvaluationAfter(aw *arbv1.AppWrapper, delay time.Duration) {
+	if cc.itemEventQueue == nil {
+		return
+	}
+	cc.itemEventQueue.AddAfter(aw.Namespace+"/"+aw.Name, delay)
+}
+
+func (cc *XController) itemEventWorker() {
+	for cc.processNextItemEvent() {
+	}
+}
+
+func (cc *XController) processNextItemEvent() bool {
+	key, quit := cc.itemEventQueue.Get()
+	if quit {
+		return false
+	}
+	defer cc.itemEventQueue.Done(key)
+	obj, exists, err := cc.appwrapperInformer.Informer().GetStore().GetByKey(key.(string))
+	if err != nil || !exists {
+		klog.V(4).Infof("[processNextItemEvent] AppWrapper %s not found in cache, ignoring the events of its items", key)
+		return true
+	}
+	aw, ok := obj.(*arbv1.AppWrapper)
+	if !ok {
+		return true
+	}
+	cc.evaluateItems(aw)
+	cc.requeueHeldCompletion(key.(string))
+	return true
+}
+
+// requeueHeldCompletion evaluates an AppWrapper again after holdCompletionEvaluationPeriod while it is in the
+// RunningHoldCompletion state, its pods may terminate after the last change of its items
+func (cc *XController) requeueHeldCompletion(key string) {
+	obj, exists, err := cc.appwrapperInformer.Informer().GetStore().GetByKey(key)
+	if err != nil || !exists {
+		return
+	}
+	if aw, ok := obj.(*arbv1.AppWrapper); ok && aw.Status.State == arbv1.AppWrapperStateRunningHoldCompletion {
+		klog.V(6).Infof("[requeueHeldCompletion] AppWrapper '%s/%s' holds its completion, evaluating again in %v", aw.Namespace, aw.Name, holdCompletionEvaluationPeriod)
+		cc.enqueueItemEvaluationAfter(aw, holdCompletionEvaluationPeriod)
+	}
+}
+
+// evaluateItems moves a dispatched AppWrapper to Completed or Failed according to the status of its generic items
+// and dispatches the items of its next stage once the items of the current stage are ready
+func (cc *XController) evaluateItems(aw *arbv1.AppWrapper) {
+	if aw.Status.State != arbv1.AppWrapperStateActive && aw.Status.State != arbv1.AppWrapperStateRunningHoldCompletion {
+		return
+	}
+	klog.V(6).Infof("[evaluateItems] Evaluating the generic items of AppWrapper '%s/%s' Version=%s State=%s", aw.Namespace, aw.Name, aw.ResourceVersion, aw.Status.State)
+	for _, genericItem := range aw.Spec.AggrResources.GenericItems {
+		if len(genericItem.CompletionStatus) > 0 || len(genericItem.FailureStatus) > 0 {
+			// the copy in the informer cache must not be modified
+			cc.UpdateQueueJobs(aw.DeepCopy())
+			break
+		}
+	}
+	if _, hasNextStage := getNextStage(aw, aw.Status.CurrentStage); hasNextStage {
+		// use the latest copy, the status may have been updated by the completion check
+		if latestObj, exists, err := cc.appwrapperInformer.Informer().GetStore().GetByKey(aw.Namespace + "/" + aw.Name); err == nil && exists {
+			if latestAw, ok := latestObj.(*arbv1.AppWrapper); ok {
+				aw = latestAw
+			}
+		}
+		cc.UpdateQueueJobStages(aw)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clientset "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/clientset/versioned"
+	informerFactory "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/informers/externalversions"
+)
+
+func TestNeedsItemEvaluation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tests := []struct {
+		name         string
+		items        []arbv1.AppWrapperGenericResource
+		currentStage int
+		expected     bool
+	}{
+		{name: "no items", expected: false},
+		{name: "items without status", items: []arbv1.AppWrapperGenericResource{{}, {}}, expected: false},
+		{name: "completion status", items: []arbv1.AppWrapperGenericResource{{}, {CompletionStatus: "Complete"}}, expected: true},
+		{name: "failure status", items: []arbv1.AppWrapperGenericResource{{FailureStatus: "Failed"}}, expected: true},
+		{name: "next stage", items: []arbv1.AppWrapperGenericResource{{Stage: 0}, {Stage: 1}}, currentStage: 0, expected: true},
+		{name: "last stage", items: []arbv1.AppWrapperGenericResource{{Stage: 0}, {Stage: 1}}, currentStage: 1, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aw := &arbv1.AppWrapper{}
+			aw.Spec.AggrResources.GenericItems = tt.items
+			aw.Status.CurrentStage = tt.currentStage
+			g.Expect(needsItemEvaluation(aw)).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+func TestEnqueueItemEvaluation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	aw := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "aw", Namespace: "default"}}
+
+	// without a queue the events of the items are ignored
+	cc := &XController{}
+	cc.enqueueItemEvaluation("default", "aw")
+	cc.enqueueItemEvaluationAfter(aw, time.Second)
+
+	cc.itemEventQueue = workqueue.NewDelayingQueue()
+	defer cc.itemEventQueue.ShutDown()
+	// the events of the items of an AppWrapper are coalesced until it is evaluated
+	cc.enqueueItemEvaluation("default", "aw")
+	cc.enqueueItemEvaluation("default", "aw")
+	g.Expect(cc.itemEventQueue.Len()).To(gomega.Equal(1))
+	key, _ := cc.itemEventQueue.Get()
+	g.Expect(key).To(gomega.Equal("default/aw"))
+	cc.itemEventQueue.Done(key)
+
+	cc.enqueueItemEvaluationAfter(aw, 10*time.Millisecond)
+	g.Expect(cc.itemEventQueue.Len()).To(gomega.Equal(0))
+	g.Eventually(cc.itemEventQueue.Len).Should(gomega.Equal(1))
+}
+
+func TestRequeueHeldCompletion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	period := holdCompletionEvaluationPeriod
+	holdCompletionEvaluationPeriod = 10 * time.Millisecond
+	defer func() { holdCompletionEvaluationPeriod = period }()
+
+	client, err := clientset.NewForConfig(&rest.Config{Host: "http://127.0.0.1:1"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	cc := &XController{
+		appwrapperInformer: informerFactory.NewSharedInformerFactory(client, 0).Workload().V1beta1().AppWrappers(),
+		itemEventQueue:     workqueue.NewDelayingQueue(),
+	}
+	defer cc.itemEventQueue.ShutDown()
+	store := cc.appwrapperInformer.Informer().GetStore()
+	aw := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "aw", Namespace: "default"},
+		Status: arbv1.AppWrapperStatus{State: arbv1.AppWrapperStateRunningHoldCompletion, Running: 1}}
+	g.Expect(store.Add(aw)).To(gomega.Succeed())
+
+	// an AppWrapper holding its completion is evaluated again, its pods may terminate without any item event
+	cc.enqueueItemEvaluation("default", "aw")
+	g.Expect(cc.processNextItemEvent()).To(gomega.BeTrue())
+	g.Expect(cc.itemEventQueue.Len()).To(gomega.Equal(0))
+	g.Eventually(cc.itemEventQueue.Len).Should(gomega.Equal(1))
+
+	// the evaluations stop once the AppWrapper is completed
+	completed := aw.DeepCopy()
+	completed.Status.State = arbv1.AppWrapperStateCompleted
+	completed.Status.Running = 0
+	g.Expect(store.Update(completed)).To(gomega.Succeed())
+	g.Expect(cc.processNextItemEvent()).To(gomega.BeTrue())
+	g.Consistently(cc.itemEventQueue.Len, 50*time.Millisecond).Should(gomega.Equal(0))
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"time"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/metrics"
+)
+
+// metricsUpdatePeriod is the period of the update of the queue and quota metrics
+const metricsUpdatePeriod = 10 * time.Second
+
+// updateMetrics records the depth of the scheduling queues and the usage of the quota tree nodes
+func (qjm *XController) updateMetrics() {
+	metrics.SetQueueDepth(qjm.qjqueue.Length(), qjm.qjqueue.UnschedulableLength())
+	if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+		metrics.SetQuotaNodeUsage(qjm.quotaManager.GetQuotaNodeUsage())
+	}
+}
+
+// recordDispatch records a successful dispatch attempt and the time the AppWrapper waited in the queue
+func recordDispatch(aw *arbv1.AppWrapper, reason string) {
+	metrics.DispatchAttempts.WithLabelValues(metrics.OutcomeDispatched, reason).Inc()
+	if !aw.Status.ControllerFirstTimestamp.IsZero() {
+		metrics.TimeInQueue.Observe(time.Since(aw.Status.ControllerFirstTimestamp.Time).Seconds())
+	}
+}
+
+// recordDispatchFailure records a failed dispatch attempt
+func recordDispatchFailure(reason string) {
+	metrics.DispatchAttempts.WithLabelValues(metrics.OutcomeFailed, reason).Inc()
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+// queuePositionUpdatePeriod is the period of the computation of the positions of the queued AppWrappers
+const queuePositionUpdatePeriod = 30 * time.Second
+
+// estimatedDispatchTimeTolerance is the smallest change of the estimated dispatch time published in the status of
+// an AppWrapper
+const estimatedDispatchTimeTolerance = time.Minute
+
+// defaultQuotaNode is the quota tree node charged for AppWrappers without a label for the tree
+const defaultQuotaNode = "default"
+
+// resourceRelease is the release of the resources of a running AppWrapper at its expected completion time
+type resourceRelease struct {
+	at        time.Time
+	resources *clusterstateapi.Resource
+}
+
+// expectedDispatchDuration returns the expected dispatch duration of an AppWrapper, falling back to its dispatch
+// duration limit. It returns 0 when the AppWrapper has neither.
+func expectedDispatchDuration(aw *arbv1.AppWrapper) time.Duration {
+	if aw.Spec.SchedSpec.DispatchDuration.Expected > 0 {
+		return time.Duration(aw.Spec.SchedSpec.DispatchDuration.Expected) * time.Second
+	}
+	if aw.Spec.SchedSpec.DispatchDuration.Limit > 0 {
+		return time.Duration(aw.Spec.SchedSpec.DispatchDuration.Limit) * time.Second
+	}
+	return 0
+}
+
+// isQueued returns whether an AppWrapper is waiting for dispatch
+func isQueued(aw *arbv1.AppWrapper) bool {
+	return aw.DeletionTimestamp == nil && !aw.Status.CanRun &&
+		(aw.Status.State == arbv1.AppWrapperStateEnqueued || aw.Status.State == "")
+}
+
+// computeQueuePositions returns the positions of queued AppWrappers, given in scheduling order, globally and among the
+// AppWrappers charged to the same leaf node of each of the given quota trees
+func computeQueuePositions(queued []*arbv1.AppWrapper, quotaTrees []string) []*arbv1.QueuePosition {
+	trees := append([]string(nil), quotaTrees...)
+	sort.Strings(trees)
+	leafCounts := make(map[arbv1.QuotaLeafPosition]int32)
+	positions := make([]*arbv1.QueuePosition, len(queued))
+	for i, aw := range queued {
+		position := &arbv1.QueuePosition{Global: int32(i + 1)}
+		for _, tree := range trees {
+			node, ok := aw.Labels[tree]
+			if !ok {
+				node = defaultQuotaNode
+			}
+			leaf := arbv1.QuotaLeafPosition{Tree: tree, Node: node}
+			leafCounts[leaf]++
+			leaf.Position = leafCounts[leaf]
+			position.QuotaLeaves = append(position.QuotaLeaves, leaf)
+		}
+		positions[i] = position
+	}
+	return positions
+}
+
+// estimateDispatchTimes simulates the dispatch of queued AppWrappers, given by their demands in scheduling order, as
+// the running AppWrappers release their resources. Every dispatched AppWrapper releases its resources after its
+// expected duration, or never when the duration is 0. The free resources may be negative when the running AppWrappers
+// are not all accounted for by the cluster yet. The estimated dispatch time is nil for AppWrappers that never fit.
+func estimateDispatchTimes(now time.Time, free *clusterstateapi.Resource, releases []resourceRelease,
+	demands []*clusterstateapi.Resource, durations []time.Duration) []*time.Time {
+	available := free.Clone()
+	pending := append([]resourceRelease(nil), releases...)
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].at.Before(pending[j].at) })
+	estimates := make([]*time.Time, len(demands))
+	t := now
+	for i, demand := range demands {
+		// AppWrappers that never fit do not hold back the AppWrappers behind them
+		eventually := available.Clone()
+		for _, release := range pending {
+			eventually.Add(release.resources)
+		}
+		if !demand.LessEqual(eventually) {
+			continue
+		}
+		for !demand.LessEqual(available) && len(pending) > 0 {
+			if pending[0].at.After(t) {
+				t = pending[0].at
+			}
+			available.Add(pending[0].resources)
+			pending = pending[1:]
+		}
+		estimate := t
+		estimates[i] = &estimate
+		subtractResource(available, demand)
+		if durations[i] > 0 {
+			release := resourceRelease{at: t.Add(durations[i]), resources: demand}
+			j := sort.Search(len(pending), func(k int) bool { return pending[k].at.After(release.at) })
+			pending = append(pending, resourceRelease{})
+			copy(pending[j+1:], pending[j:])
+			pending[j] = release
+		}
+	}
+	return estimates
+}
+
+// subtractResource subtracts resources without clamping the result at 0
+func subtractResource(r *clusterstateapi.Resource, rr *clusterstateapi.Resource) {
+	r.MilliCPU -= rr.MilliCPU
+	r.Memory -= rr.Memory
+	r.GPU -= rr.GPU
+}
+
+// setQueuePosition records the position of a queued AppWrapper. To limit the status updates, the position is replaced
+// only when it moves or its estimated dispatch time changes by estimatedDispatchTimeTolerance or more. It returns
+// whether the position was replaced.
+func setQueuePosition(aw *arbv1.AppWrapper, position *arbv1.QueuePosition, now time.Time) bool {
+	previous := aw.Status.QueuePosition
+	if previous != nil && previous.Global == position.Global &&
+		equality.Semantic.DeepEqual(previous.QuotaLeaves, position.QuotaLeaves) {
+		switch {
+		case previous.EstimatedDispatchTime == nil && position.EstimatedDispatchTime == nil:
+			return false
+		case previous.EstimatedDispatchTime != nil && position.EstimatedDispatchTime != nil:
+			delta := previous.EstimatedDispatchTime.Sub(position.EstimatedDispatchTime.Time)
+			if delta < estimatedDispatchTimeTolerance && delta > -estimatedDispatchTimeTolerance {
+				return false
+			}
+		}
+	}
+	aw.Status.QueuePosition = position.DeepCopy()
+	aw.Status.QueuePosition.LastUpdateTime = metav1.NewTime(now)
+	return true
+}
+
+// isQueuePositionUpdate returns whether an AppWrapper update only changed its queue position
+func isQueuePositionUpdate(oldAW *arbv1.AppWrapper, newAW *arbv1.AppWrapper) bool {
+	if oldAW.Generation != newAW.Generation || !equality.Semantic.DeepEqual(oldAW.Labels, newAW.Labels) ||
+		equality.Semantic.DeepEqual(oldAW.Status.QueuePosition, newAW.Status.QueuePosition) {
+		return false
+	}
+	oldStatus := oldAW.Status.DeepCopy()
+	newStatus := newAW.Status.DeepCopy()
+	for _, status := range []*arbv1.AppWrapperStatus{oldStatus, newStatus} {
+		status.QueuePosition = nil
+		status.Sender = ""
+		status.FilterIgnore = false
+	}
+	return equality.Semantic.DeepEqual(oldStatus, newStatus)
+}
+
+// queuedAppWrappers returns copies of the AppWrappers waiting for dispatch in scheduling order, starting with the
+// AppWrapper being scheduled. The scheduling worker mutates the queued objects, so their state is read from the lister.
+func (qjm *XController) queuedAppWrappers() []*arbv1.AppWrapper {
+	schedulingKey := qjm.getSchedulingKey()
+	var queued []*arbv1.AppWrapper
+	for _, key := range qjm.qjqueue.Keys() {
+		if key == schedulingKey {
+			continue
+		}
+		if aw := qjm.listerCopy(key); aw != nil && isQueued(aw) {
+			queued = append(queued, aw)
+		}
+	}
+	SortBySchedulingOrder(queued)
+	if aw := qjm.listerCopy(schedulingKey); aw != nil && isQueued(aw) {
+		queued = append([]*arbv1.AppWrapper{aw}, queued...)
+	}
+	return queued
+}
+
+// listerCopy returns a copy of the AppWrapper with the given namespace/name key from the lister, or nil
+func (qjm *XController) listerCopy(key string) *arbv1.AppWrapper {
+	if key == "" {
+		return nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	aw, err := qjm.appWrapperLister.AppWrappers(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return aw.DeepCopy()
+}
+
+// estimateQueuedDispatchTimes estimates the dispatch times of queued AppWrappers, given in scheduling order, from the
+// resources of the cluster and the expected completion times of the running AppWrappers
+func (qjm *XController) estimateQueuedDispatchTimes(queued []*arbv1.AppWrapper, now time.Time) ([]*time.Time, error) {
+	appWrappers, err := qjm.appWrapperLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	free := qjm.allocatableCapacity()
+	var releases []resourceRelease
+	for _, aw := range appWrappers {
+		if !aw.Status.CanRun || aw.Status.State == arbv1.AppWrapperStateCompleted ||
+			aw.Status.State == arbv1.AppWrapperStateFailed || aw.Status.State == arbv1.AppWrapperStateDeleted {
+			continue
+		}
+		resources := qjm.GetAggregatedResources(aw)
+		subtractResource(free, resources)
+		if duration := expectedDispatchDuration(aw); duration > 0 {
+			start := aw.Status.ControllerFirstDispatchTimestamp.Time
+			if start.IsZero() {
+				start = now
+			}
+			releases = append(releases, resourceRelease{at: start.Add(duration), resources: resources})
+		}
+	}
+	demands := make([]*clusterstateapi.Resource, len(queued))
+	durations := make([]time.Duration, len(queued))
+	for i, aw := range queued {
+		demands[i] = qjm.GetAggregatedResources(aw)
+		durations[i] = expectedDispatchDuration(aw)
+	}
+	return estimateDispatchTimes(now, free, releases, demands, durations), nil
+}
+
+// updateQueuePositions computes the positions of the queued AppWrappers and publishes them in their status. The
+// dispatch times are only estimated in agent mode, where the resources of the cluster are known.
+func (qjm *XController) updateQueuePositions() {
+	queued := qjm.queuedAppWrappers()
+	var quotaTrees []string
+	if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+		quotaTrees = qjm.quotaManager.GetValidQuotaLabels()
+	}
+	positions := computeQueuePositions(queued, quotaTrees)
+	now := time.Now()
+	if !qjm.isDispatcher && len(queued) > 0 {
+		estimates, err := qjm.estimateQueuedDispatchTimes(queued, now)
+		if err != nil {
+			klog.Errorf("[updateQueuePositions] Unable to estimate the dispatch times, err=%v", err)
+		}
+		for i, estimate := range estimates {
+			if estimate != nil {
+				t := metav1.NewTime(*estimate)
+				positions[i].EstimatedDispatchTime = &t
+			}
+		}
+	}
+
+	current := make(map[string]*arbv1.QueuePosition, len(queued))
+	for i, aw := range queued {
+		key, _ := GetQueueJobKey(aw)
+		current[key] = positions[i]
+	}
+	qjm.queuePositionMutex.Lock()
+	qjm.queuePositions = current
+	qjm.queuePositionMutex.Unlock()
+
+	for i, aw := range queued {
+		qjm.publishQueuePosition(aw, positions[i], now)
+	}
+}
+
+// publishQueuePosition records the position of a queued AppWrapper in its status. Conflicting updates are dropped,
+// the position is published again during the next update period. The AppWrapper being scheduled and the AppWrappers
+// at the head of the line or in backoff are skipped, their status is owned by the scheduling worker.
+func (qjm *XController) publishQueuePosition(aw *arbv1.AppWrapper, position *arbv1.QueuePosition, now time.Time) {
+	if key, _ := GetQueueJobKey(aw); key == qjm.getSchedulingKey() {
+		return
+	}
+	current, err := qjm.getAppWrapper(aw.Namespace, aw.Name, "[publishQueuePosition]")
+	if err != nil || !isQueued(current) || current.Status.QueueJobState == arbv1.AppWrapperCondHeadOfLine ||
+		current.Status.QueueJobState == arbv1.AppWrapperCondBackoff {
+		return
+	}
+	if !setQueuePosition(current, position, now) {
+		return
+	}
+	current.Status.FilterIgnore = true // Update QueuePosition only
+	if err := qjm.updateStatusInEtcd(context.Background(), current, "[publishQueuePosition]"); err != nil {
+		klog.V(4).Infof("[publishQueuePosition] Unable to publish the queue position of '%s/%s', err=%v", aw.Namespace, aw.Name, err)
+	}
+}
+
+// QueuePositions returns the latest positions of the queued AppWrappers, keyed by namespace/name
+func (qjm *XController) QueuePositions() map[string]*arbv1.QueuePosition {
+	qjm.queuePositionMutex.RLock()
+	defer qjm.queuePositionMutex.RUnlock()
+	positions := make(map[string]*arbv1.QueuePosition, len(qjm.queuePositions))
+	for key, position := range qjm.queuePositions {
+		positions[key] = position.DeepCopy()
+	}
+	return positions
+}
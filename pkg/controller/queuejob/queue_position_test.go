@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	arblisters "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+func queuedAppWrapper(name string, priority float64, arrival time.Time, labels map[string]string) *arbv1.AppWrapper {
+	return &arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Status: arbv1.AppWrapperStatus{
+			State:                    arbv1.AppWrapperStateEnqueued,
+			SystemPriority:           priority,
+			ControllerFirstTimestamp: metav1.NewMicroTime(arrival),
+		},
+	}
+}
+
+func newAppWrapperLister(g *gomega.WithT, aws ...*arbv1.AppWrapper) arblisters.AppWrapperLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, aw := range aws {
+		g.Expect(indexer.Add(aw)).To(gomega.Succeed())
+	}
+	return arblisters.NewAppWrapperLister(indexer)
+}
+
+func TestPriorityQueueKeys(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	q := NewPriorityQueue()
+	late := queuedAppWrapper("late", 1, now.Add(time.Minute), nil)
+	high := queuedAppWrapper("high", 5, now.Add(2*time.Minute), nil)
+	early := queuedAppWrapper("early", 1, now, nil)
+	g.Expect(q.Add(late)).To(gomega.Succeed())
+	g.Expect(q.Add(high)).To(gomega.Succeed())
+	g.Expect(q.AddUnschedulableIfNotPresent(early)).To(gomega.Succeed())
+	g.Expect(q.Keys()).To(gomega.ConsistOf("default/late", "default/high", "default/early"))
+
+	queued := []*arbv1.AppWrapper{late, high, early}
+	SortBySchedulingOrder(queued)
+	var names []string
+	for _, aw := range queued {
+		names = append(names, aw.Name)
+	}
+	g.Expect(names).To(gomega.Equal([]string{"high", "early", "late"}))
+}
+
+func TestQueuedAppWrappers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	scheduling := queuedAppWrapper("scheduling", 1, now.Add(time.Hour), nil)
+	high := queuedAppWrapper("high", 5, now, nil)
+	low := queuedAppWrapper("low", 1, now, nil)
+	qjm := &XController{qjqueue: NewSchedulingQueue(), appWrapperLister: newAppWrapperLister(g, scheduling, high, low)}
+	g.Expect(qjm.qjqueue.Add(low)).To(gomega.Succeed())
+	g.Expect(qjm.qjqueue.Add(high)).To(gomega.Succeed())
+	g.Expect(qjm.qjqueue.AddUnschedulableIfNotPresent(scheduling)).To(gomega.Succeed())
+	qjm.schedulingAWAtomicSet(scheduling)
+
+	queued := qjm.queuedAppWrappers()
+	g.Expect(queued).To(gomega.HaveLen(3))
+	g.Expect([]string{queued[0].Name, queued[1].Name, queued[2].Name}).To(gomega.Equal([]string{"scheduling", "high", "low"}))
+	// the queued objects are copies of the lister objects
+	g.Expect(queued[1]).NotTo(gomega.BeIdenticalTo(high))
+}
+
+// TestPublishQueuePositionSkipsScheduledAppWrappers validates that the status of the AppWrapper being scheduled and of
+// the AppWrappers at the head of the line or in backoff is never written, the controller has no API client to do so
+func TestPublishQueuePositionSkipsScheduledAppWrappers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	scheduling := queuedAppWrapper("scheduling", 1, now, nil)
+	headOfLine := queuedAppWrapper("hol", 1, now, nil)
+	headOfLine.Status.QueueJobState = arbv1.AppWrapperCondHeadOfLine
+	backoff := queuedAppWrapper("backoff", 1, now, nil)
+	backoff.Status.QueueJobState = arbv1.AppWrapperCondBackoff
+	qjm := &XController{appWrapperLister: newAppWrapperLister(g, scheduling, headOfLine, backoff)}
+	qjm.schedulingAWAtomicSet(scheduling)
+
+	for _, aw := range []*arbv1.AppWrapper{scheduling, headOfLine, backoff} {
+		g.Expect(func() { qjm.publishQueuePosition(aw, &arbv1.QueuePosition{Global: 1}, now) }).NotTo(gomega.Panic())
+	}
+}
+
+func TestComputeQueuePositions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	queued := []*arbv1.AppWrapper{
+		queuedAppWrapper("a", 1, now, map[string]string{"context": "team-a"}),
+		queuedAppWrapper("b", 1, now, map[string]string{"context": "team-b"}),
+		queuedAppWrapper("c", 1, now, nil),
+		queuedAppWrapper("d", 1, now, map[string]string{"context": "team-a"}),
+	}
+
+	positions := computeQueuePositions(queued, []string{"context"})
+	g.Expect(positions).To(gomega.HaveLen(4))
+	g.Expect(positions[3].Global).To(gomega.Equal(int32(4)))
+	g.Expect(positions[3].QuotaLeaves).To(gomega.Equal([]arbv1.QuotaLeafPosition{{Tree: "context", Node: "team-a", Position: 2}}))
+	g.Expect(positions[2].QuotaLeaves).To(gomega.Equal([]arbv1.QuotaLeafPosition{{Tree: "context", Node: defaultQuotaNode, Position: 1}}))
+
+	positions = computeQueuePositions(queued, nil)
+	g.Expect(positions[1].Global).To(gomega.Equal(int32(2)))
+	g.Expect(positions[1].QuotaLeaves).To(gomega.BeEmpty())
+}
+
+func TestEstimateDispatchTimes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	gpus := func(n int64) *clusterstateapi.Resource { return &clusterstateapi.Resource{GPU: n} }
+	releases := []resourceRelease{
+		{at: now.Add(20 * time.Minute), resources: gpus(4)},
+		{at: now.Add(-time.Minute), resources: gpus(2)},
+	}
+
+	// 1 GPU free now, 2 more from an overdue AppWrapper, 4 more in 20 minutes
+	estimates := estimateDispatchTimes(now, gpus(1), releases,
+		[]*clusterstateapi.Resource{gpus(1), gpus(2), gpus(4), gpus(16), gpus(2)},
+		[]time.Duration{0, 10 * time.Minute, time.Hour, 0, 0})
+	g.Expect(estimates).To(gomega.HaveLen(5))
+	g.Expect(*estimates[0]).To(gomega.Equal(now))
+	g.Expect(*estimates[1]).To(gomega.Equal(now))
+	g.Expect(*estimates[2]).To(gomega.Equal(now.Add(20 * time.Minute)))
+	g.Expect(estimates[3]).To(gomega.BeNil())
+	// released by the second AppWrapper 10 minutes after its dispatch
+	g.Expect(*estimates[4]).To(gomega.Equal(now.Add(20 * time.Minute)))
+
+	// running AppWrappers not accounted for by the cluster yet
+	estimates = estimateDispatchTimes(now, gpus(-2), releases,
+		[]*clusterstateapi.Resource{gpus(1)}, []time.Duration{0})
+	g.Expect(*estimates[0]).To(gomega.Equal(now.Add(20 * time.Minute)))
+}
+
+func TestSetQueuePosition(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	aw := &arbv1.AppWrapper{}
+	position := func(global int32, eta time.Duration) *arbv1.QueuePosition {
+		estimate := metav1.NewTime(now.Add(eta))
+		return &arbv1.QueuePosition{Global: global, EstimatedDispatchTime: &estimate}
+	}
+
+	g.Expect(setQueuePosition(aw, position(3, time.Hour), now)).To(gomega.BeTrue())
+	g.Expect(aw.Status.QueuePosition.Global).To(gomega.Equal(int32(3)))
+	g.Expect(aw.Status.QueuePosition.LastUpdateTime.Time).To(gomega.Equal(now))
+
+	// small changes of the estimated dispatch time are not published
+	g.Expect(setQueuePosition(aw, position(3, time.Hour+estimatedDispatchTimeTolerance/2), now)).To(gomega.BeFalse())
+	g.Expect(setQueuePosition(aw, position(3, time.Hour+estimatedDispatchTimeTolerance), now)).To(gomega.BeTrue())
+
+	g.Expect(setQueuePosition(aw, position(2, time.Hour+estimatedDispatchTimeTolerance), now)).To(gomega.BeTrue())
+	g.Expect(aw.Status.QueuePosition.Global).To(gomega.Equal(int32(2)))
+
+	g.Expect(setQueuePosition(aw, &arbv1.QueuePosition{Global: 2}, now)).To(gomega.BeTrue())
+	g.Expect(setQueuePosition(aw, &arbv1.QueuePosition{Global: 2}, now)).To(gomega.BeFalse())
+}
+
+func TestIsQueuePositionUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	oldAW := queuedAppWrapper("a", 1, time.Now(), nil)
+	newAW := oldAW.DeepCopy()
+	newAW.Status.QueuePosition = &arbv1.QueuePosition{Global: 1}
+	newAW.Status.FilterIgnore = true
+	newAW.Status.Sender = "before [publishQueuePosition]"
+	g.Expect(isQueuePositionUpdate(oldAW, newAW)).To(gomega.BeTrue())
+
+	newAW.Status.State = arbv1.AppWrapperStateActive
+	g.Expect(isQueuePositionUpdate(oldAW, newAW)).To(gomega.BeFalse())
+
+	g.Expect(isQueuePositionUpdate(oldAW, oldAW.DeepCopy())).To(gomega.BeFalse())
+}
@@ -22,7 +22,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
 	"reflect"
 	"runtime/debug"
 	"sort"
@@ -32,6 +31,7 @@ import (
 
 	"github.com/eapache/go-resiliency/retrier"
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/attribute"
 
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
 	clientset "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/clientset/versioned"
@@ -40,11 +40,13 @@ import (
 	arblisters "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
 	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/metrics"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/metrics/adapter"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobdispatch"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobresources/genericresource"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota/quotaforestmanager"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/tracing"
 	qmutils "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/quotaplugins/util"
 
 	v1 "k8s.io/api/core/v1"
@@ -52,12 +54,15 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
 
@@ -77,10 +82,17 @@ type XController struct {
 
 	// Captures all available resources in the cluster
 	genericresources *genericresource.GenericResources
+	// AppWrappers whose generic items are to be evaluated for completion, failure and readiness: namespace/name
+	itemEventQueue workqueue.DelayingInterface
+	// Restarting AppWrappers waiting for the backoff of their restart policy: namespace/name
+	restartQueue workqueue.DelayingInterface
 
 	clients    *kubernetes.Clientset
 	arbclients *clientset.Clientset
 
+	// Records the lifecycle transitions of AppWrappers as events
+	eventRecorder record.EventRecorder
+
 	// A store of jobs
 	appWrapperLister arblisters.AppWrapperLister
 	appWrapperSynced func() bool
@@ -108,10 +120,52 @@ type XController struct {
 	// Agent map: agentID -> JobClusterAgent
 	agentMap  map[string]*queuejobdispatch.JobClusterAgent
 	agentList []string
-
-	// Map for AppWrapper -> JobClusterAgent
+	// agentMap and agentList change at runtime as ClusterAgents are added, updated and deleted
+	agentMutex sync.RWMutex
+
+	// Agents registered through ClusterAgent resources: agentID -> registeredAgent
+	registeredAgents     map[string]*registeredAgent
+	clusterAgentInformer arbinformers.ClusterAgentInformer
+	clusterAgentLister   arblisters.ClusterAgentLister
+	clusterAgentSynced   func() bool
+	// ClusterAgents whose agent is to be registered or removed, by name
+	clusterAgentQueue workqueue.RateLimitingInterface
+
+	// LimitRanges whose container defaults apply to the demand of generic items. The informer is started
+	// before the quota manager is built and stopped when the stop channel given to Run is closed.
+	limitRangeInformer corev1informers.LimitRangeInformer
+	limitRangeSynced   func() bool
+	limitRangeStopCh   chan struct{}
+
+	// Map for AppWrapper -> JobClusterAgent, guarded by agentMutex
 	dispatchMap map[string]string
 
+	// Unhealthy agents: agentID -> agentHealth, and AppWrappers requeued from them: agentID -> AppWrappers.
+	// Both are guarded by agentMutex.
+	unhealthyAgents       map[string]*agentHealth
+	lostAppWrappers       map[string][]*arbv1.AppWrapper
+	agentFailureThreshold int
+	agentLostGracePeriod  time.Duration
+
+	// Policy used to rank agent clusters in dispatcher mode
+	agentSelectionPolicy string
+
+	// Period of the capacity reports published for the dispatcher in agent mode, 0 when disabled
+	capacityReportPeriod time.Duration
+
+	// Latest positions of the queued AppWrappers: namespace/name -> position
+	queuePositions     map[string]*arbv1.QueuePosition
+	queuePositionMutex sync.RWMutex
+
+	// Last capacity computed by allocatableCapacity and the time it was computed
+	lastCapacity      *clusterstateapi.Resource
+	lastCapacityTime  time.Time
+	lastCapacityMutex sync.RWMutex
+
+	// Times the AppWrappers were added to the event queue, to trace their wait: namespace/name -> time
+	enqueueTimes      map[string]time.Time
+	enqueueTimesMutex sync.Mutex
+
 	// Metrics API Server
 	metricsAdapter *adapter.MetricsAdapter
 
@@ -123,7 +177,14 @@ type XController struct {
 
 	// Active Scheduling AppWrapper
 	schedulingAW    *arbv1.AppWrapper
+	schedulingKey   string // namespace/name of schedulingAW, read by the other goroutines instead of its mutable fields
 	schedulingMutex sync.RWMutex
+
+	// AppWrappers held in queue by concurrency limits: queueJobKey -> AppWrapper
+	concurrencyWaiters map[string]*arbv1.AppWrapper
+	// Dispatched AppWrappers and pods per namespace and per user, only maintained when concurrency limits are set
+	concurrencyUsage *concurrencyUsage
+	concurrencyMutex   sync.Mutex
 }
 
 type JobAndClusterAgent struct {
@@ -190,8 +251,11 @@ func (qjm *XController) UpdateQueueJobStatus(queuejob *arbv1.AppWrapper) error {
 // May be moved to controller runtime can help.
 func (qjm *XController) allocatableCapacity() *clusterstateapi.Resource {
 	capacity := clusterstateapi.EmptyResource()
-	nodes, _ := qjm.clients.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
 	startTime := time.Now()
+	defer func() {
+		metrics.AllocatableCapacityDuration.Observe(time.Since(startTime).Seconds())
+	}()
+	nodes, _ := qjm.clients.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
 	for _, node := range nodes.Items {
 		// skip unschedulable nodes
 		if node.Spec.Unschedulable {
@@ -217,6 +281,10 @@ func (qjm *XController) allocatableCapacity() *clusterstateapi.Resource {
 		}
 	}
 	klog.Infof("[allocatableCapacity] The available capacity to dispatch appwrapper is %v and time took to calculate is %v", capacity, time.Since(startTime))
+	qjm.lastCapacityMutex.Lock()
+	qjm.lastCapacity = capacity.Clone()
+	qjm.lastCapacityTime = startTime
+	qjm.lastCapacityMutex.Unlock()
 	return capacity
 }
 
@@ -233,8 +301,10 @@ func NewJobController(restConfig *rest.Config, mcadConfig *config.MCADConfigurat
 		qjqueue: NewSchedulingQueue(),
 		// cache is turned-off, issue: https://github.com/project-codeflare/multi-cluster-app-dispatcher/issues/588
 		// cache:        clusterstatecache.New(config),
-		schedulingAW: nil,
+		schedulingAW:       nil,
+		concurrencyWaiters: map[string]*arbv1.AppWrapper{},
 	}
+	cc.eventRecorder = newEventRecorder(cc.clients)
 	// TODO: work on enabling metrics adapter for correct MCAD mode
 	// metrics adapter is implemented through dynamic client which looks at all the
 	// resources installed in the cluster to construct cache. May be this is need in
@@ -242,6 +312,9 @@ func NewJobController(restConfig *rest.Config, mcadConfig *config.MCADConfigurat
 	// cc.metricsAdapter = adapter.New(serverOption, config, cc.cache)
 
 	cc.genericresources = genericresource.NewAppWrapperGenericResource(restConfig)
+	cc.itemEventQueue = workqueue.NewNamedDelayingQueue("appwrapper-items")
+	cc.genericresources.AddItemEventHandler(cc.enqueueItemEvaluation)
+	cc.restartQueue = workqueue.NewNamedDelayingQueue("appwrapper-restarts")
 
 	appWrapperClient, err := clientset.NewForConfig(restConfig)
 	if err != nil {
@@ -271,9 +344,27 @@ func NewJobController(restConfig *rest.Config, mcadConfig *config.MCADConfigurat
 				DeleteFunc: cc.deleteQueueJob,
 			},
 		})
+	if cc.config.HasConcurrencyLimits() {
+		cc.concurrencyUsage = newConcurrencyUsage(cc.config.ConcurrencyLimits.UserAnnotation)
+		cc.appwrapperInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    cc.updateConcurrencyUsage,
+			UpdateFunc: func(oldObj, newObj interface{}) { cc.updateConcurrencyUsage(newObj) },
+			DeleteFunc: cc.deleteConcurrencyUsage,
+		})
+	}
 	cc.appWrapperLister = cc.appwrapperInformer.Lister()
 	cc.appWrapperSynced = cc.appwrapperInformer.Informer().HasSynced
 
+	cc.limitRangeInformer = informers.NewSharedInformerFactory(cc.clients, 0).Core().V1().LimitRanges()
+	cc.limitRangeSynced = cc.limitRangeInformer.Informer().HasSynced
+	genericresource.SetLimitRangeLister(cc.limitRangeInformer.Lister())
+	// the demands of the dispatched AppWrappers given to the quota manager depend on the LimitRange defaults
+	cc.limitRangeStopCh = make(chan struct{})
+	go cc.limitRangeInformer.Informer().Run(cc.limitRangeStopCh)
+	if !cache.WaitForCacheSync(cc.limitRangeStopCh, cc.limitRangeSynced) {
+		klog.Warningf("[Controller] LimitRange cache not synced, demands of generic items may miss LimitRange defaults")
+	}
+
 	// Setup Quota
 	if mcadConfig.IsQuotaEnabled() {
 		dispatchedAWDemands, dispatchedAWs := cc.getDispatchedAppWrappers(restConfig)
@@ -289,31 +380,51 @@ func NewJobController(restConfig *rest.Config, mcadConfig *config.MCADConfigurat
 
 	// Set dispatcher mode or agent mode
 	cc.isDispatcher = extConfig.IsDispatcher()
+	cc.agentSelectionPolicy = extConfig.AgentSelectionPolicyOrDefault()
 	if cc.isDispatcher {
 		klog.Infof("[Controller] Dispatcher mode")
 	} else {
 		klog.Infof("[Controller] Agent mode")
 	}
 
+	// the capacity reports of the agents are stale after a few periods
+	cc.capacityReportPeriod = time.Duration(extConfig.CapacityReportPeriodOrDefault(defaultCapacityReportPeriod)) * time.Second
+
 	// create agents and agentMap
 	cc.agentMap = map[string]*queuejobdispatch.JobClusterAgent{}
 	cc.agentList = []string{}
 	for _, agentConfig := range extConfig.AgentConfigs {
 		agentData := strings.Split(agentConfig, ":")
-		jobClusterAgent := queuejobdispatch.NewJobClusterAgent(agentConfig, cc.agentEventQueue)
+		jobClusterAgent := queuejobdispatch.NewJobClusterAgent(agentConfig, cc.agentEventQueue, cc.capacityReportMaxAge())
 		if jobClusterAgent != nil {
 			cc.agentMap["/root/kubernetes/"+agentData[0]] = jobClusterAgent
 			cc.agentList = append(cc.agentList, "/root/kubernetes/"+agentData[0])
 		}
 	}
 
-	if cc.isDispatcher && len(cc.agentMap) == 0 {
-		klog.Errorf("Dispatcher mode: no agent information")
-		return nil
+	// watch ClusterAgents to register agent clusters at runtime
+	cc.registeredAgents = map[string]*registeredAgent{}
+	if cc.isDispatcher {
+		cc.clusterAgentInformer = informerFactory.NewSharedInformerFactory(appWrapperClient, 0).Workload().V1beta1().ClusterAgents()
+		cc.clusterAgentQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "clusteragents")
+		cc.clusterAgentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    cc.enqueueClusterAgent,
+			UpdateFunc: cc.updateClusterAgent,
+			DeleteFunc: cc.enqueueClusterAgent,
+		})
+		cc.clusterAgentLister = cc.clusterAgentInformer.Lister()
+		cc.clusterAgentSynced = cc.clusterAgentInformer.Informer().HasSynced
+		if len(cc.agentMap) == 0 {
+			klog.Infof("Dispatcher mode: no agent configured, waiting for ClusterAgent resources")
+		}
 	}
 
 	// create (empty) dispatchMap
 	cc.dispatchMap = map[string]string{}
+	cc.unhealthyAgents = map[string]*agentHealth{}
+	cc.lostAppWrappers = map[string][]*arbv1.AppWrapper{}
+	cc.agentFailureThreshold = int(extConfig.AgentFailureThresholdOrDefault(defaultAgentFailureThreshold))
+	cc.agentLostGracePeriod = time.Duration(extConfig.AgentLostGracePeriodOrDefault(defaultAgentLostGracePeriod)) * time.Second
 
 	return cc
 }
@@ -345,6 +456,7 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 		newjob.Status.FilterIgnore = true // update QueueJobState only
 		cleanAppWrapper := false
 		generatedCondition := false
+		preemptionReason := ""
 		// If dispatch deadline is exceeded no matter what the state of AW, kill the job and set status as Failed.
 		if (newjob.Status.State == arbv1.AppWrapperStateActive) && (newjob.Spec.SchedSpec.DispatchDuration.Limit > 0) {
 			if newjob.Spec.SchedSpec.DispatchDuration.Overrun {
@@ -362,13 +474,36 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 				newjob.Status.QueueJobState = arbv1.AppWrapperCondFailed
 				newjob.Status.Running = 0
 				updateNewJob = newjob.DeepCopy()
+				metrics.Preemptions.WithLabelValues("DispatchDeadlineExceeded").Inc()
+				qjm.eventRecorder.Eventf(newjob, v1.EventTypeWarning, EventReasonDispatchDeadlineExceeded,
+					"Dispatch deadline exceeded, allowed to run for %v seconds.", newjob.Spec.SchedSpec.DispatchDuration.Limit)
+
+				if canRestart(updateNewJob) {
+					// remove the items and release quota before the AW is returned to the queue
+					if err := qjm.Cleanup(ctx, updateNewJob); err != nil {
+						klog.Warningf("[PreemptQueueJobs] Failed to delete resources of '%s/%s' after dispatch deadline exceeded, err=%v", newjob.Namespace, newjob.Name, err)
+						return
+					}
+					qjm.qjqueue.Delete(updateNewJob)
+					qjm.restartFailedAppWrapper(updateNewJob, "DispatchDeadlineExceeded",
+						fmt.Sprintf("Dispatch deadline exceeded. allowed to run for %v seconds", newjob.Spec.SchedSpec.DispatchDuration.Limit))
+					if err := qjm.updateStatusInEtcdWithRetry(ctx, updateNewJob, "PreemptQueueJobs - CanRun: false -- DispatchDeadlineExceeded, restarting"); err != nil {
+						klog.Warningf("[PreemptQueueJobs] status update  CanRun: false -- DispatchDeadlineExceeded, restarting for '%s/%s' failed", newjob.Namespace, newjob.Name)
+						return
+					}
+					qjm.recordFailureEvent(updateNewJob, true, fmt.Sprintf("Dispatch deadline exceeded. allowed to run for %v seconds", newjob.Spec.SchedSpec.DispatchDuration.Limit))
+					return
+				}
 
+				// Failed is a terminal state, so the worker will not clean up the items; remove them and release quota here
+				if err := qjm.Cleanup(ctx, updateNewJob); err != nil {
+					klog.Warningf("[PreemptQueueJobs] Failed to delete resources of '%s/%s' after dispatch deadline exceeded, err=%v", newjob.Namespace, newjob.Name, err)
+				}
 				err := qjm.updateStatusInEtcdWithRetry(ctx, updateNewJob, "PreemptQueueJobs - CanRun: false -- DispatchDeadlineExceeded")
 				if err != nil {
 					klog.Warningf("[PreemptQueueJobs] status update  CanRun: false -- DispatchDeadlineExceeded for '%s/%s' failed", newjob.Namespace, newjob.Name)
 					return
 				}
-				// cannot use cleanup AW, since it puts AW back in running state
 				qjm.qjqueue.AddUnschedulableIfNotPresent(updateNewJob)
 				generatedCondition = true
 
@@ -414,6 +549,7 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 
 			updateNewJob = newjob.DeepCopy()
 			generatedCondition = true
+			preemptionReason = EventReasonMinPodsNotRunning
 		} else if newjob.Status.Running == 0 && newjob.Status.Succeeded == 0 && newjob.Status.State == arbv1.AppWrapperStateActive {
 			// If pods failed scheduling generate new preempt condition
 			message = fmt.Sprintf("Pods failed scheduling failed=%v, running=%v.", len(newjob.Status.PendingPodConditions), newjob.Status.Running)
@@ -430,6 +566,7 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 
 			updateNewJob = newjob.DeepCopy()
 			generatedCondition = true
+			preemptionReason = EventReasonPodsFailedScheduling
 		}
 
 		if generatedCondition {
@@ -438,6 +575,10 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 				klog.Warningf("[PreemptQueueJobs] status update for '%s/%s' failed, skipping app wrapper err =%v", newjob.Namespace, newjob.Name, err)
 				return
 			}
+			if preemptionReason != "" {
+				metrics.Preemptions.WithLabelValues(preemptionReason).Inc()
+				qjm.eventRecorder.Event(updateNewJob, v1.EventTypeWarning, preemptionReason, message)
+			}
 
 			if cleanAppWrapper {
 				klog.V(4).Infof("[PreemptQueueJobs] Deleting AppWrapper %s/%s due to maximum number of re-queueing(s) exceeded.", newjob.Namespace, newjob.Name)
@@ -446,6 +587,7 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 				// Only back-off AWs that are in state running and not in state Failed
 				if updateNewJob.Status.State != arbv1.AppWrapperStateFailed {
 					klog.Infof("[PreemptQueueJobs] Adding preempted AppWrapper %s/%s to back off queue.", newjob.Namespace, newjob.Name)
+					metrics.Requeuings.WithLabelValues(preemptionReason).Inc()
 					qjm.backoff(ctx, updateNewJob, "PreemptionTriggered", string(message))
 				}
 			}
@@ -453,7 +595,9 @@ func (qjm *XController) PreemptQueueJobs(inspectAw *arbv1.AppWrapper) {
 	}
 }
 
-func (qjm *XController) preemptAWJobs(ctx context.Context, preemptAWs []*arbv1.AppWrapper) {
+func (qjm *XController) preemptAWJobs(ctx context.Context, preemptor *arbv1.AppWrapper, preemptAWs []*arbv1.AppWrapper) {
+	ctx, span := tracing.Start(ctx, "preemptAWJobs", attribute.Int("preemptions", len(preemptAWs)))
+	defer span.End()
 	if preemptAWs == nil {
 		return
 	}
@@ -477,7 +621,11 @@ func (qjm *XController) preemptAWJobs(ctx context.Context, preemptAWs []*arbv1.A
 				continue
 			}
 			klog.Warningf("[preemptAWJobs] status update for '%s/%s' failed, err=%v", aw.Namespace, aw.Name, err)
+			continue
 		}
+		metrics.Preemptions.WithLabelValues("QuotaPreemption").Inc()
+		qjm.eventRecorder.Eventf(apiCacheAWJob, v1.EventTypeWarning, EventReasonPreempted,
+			"Preempted to release quota for AppWrapper %s/%s.", preemptor.Namespace, preemptor.Name)
 	}
 }
 
@@ -565,7 +713,7 @@ func (qjm *XController) GetAggregatedResourcesPerGenericItem(cqj *arbv1.AppWrapp
 
 	// Get all pods and related resources
 	for _, genericItem := range cqj.Spec.AggrResources.GenericItems {
-		itemsList, _ := genericresource.GetListOfPodResourcesFromOneGenericItem(&genericItem)
+		itemsList, _ := genericresource.GetListOfPodResourcesFromOneGenericItem(&genericItem, cqj.Namespace)
 		for i := 0; i < len(itemsList); i++ {
 			retVal = append(retVal, itemsList[i])
 		}
@@ -577,25 +725,28 @@ func (qjm *XController) GetAggregatedResourcesPerGenericItem(cqj *arbv1.AppWrapp
 // Gets all objects owned by AW from API server, check user supplied status and set whole AW status
 func (qjm *XController) getAppWrapperCompletionStatus(caw *arbv1.AppWrapper) arbv1.AppWrapperState {
 
+	// A single failed item fails the whole AppWrapper, so failures are checked before completion
+	for i, genericItem := range caw.Spec.AggrResources.GenericItems {
+		if len(genericItem.FailureStatus) > 0 {
+			name := getGenericItemName(&genericItem)
+			if len(name) == 0 {
+				klog.Warningf("[getAppWrapperCompletionStatus] object name not present for appwrapper: '%s/%s", caw.Namespace, caw.Name)
+			}
+			klog.V(4).Infof("[getAppWrapperCompletionStatus] Checking if item %d named %s failed for appwrapper: '%s/%s'...", i+1, name, caw.Namespace, caw.Name)
+			if qjm.genericresources.IsItemFailed(&genericItem, caw.Namespace, caw.Name, name) {
+				klog.Infof("[getAppWrapperCompletionStatus] Item %d named %s failed for appwrapper: '%s/%s'", i+1, name, caw.Namespace, caw.Name)
+				setItemState(caw, i, arbv1.AppWrapperItemStateFailed)
+				return arbv1.AppWrapperStateFailed
+			}
+		}
+	}
+
 	// Get all pods and related resources
 	countCompletionRequired := 0
+	allCompleted := true
 	for i, genericItem := range caw.Spec.AggrResources.GenericItems {
 		if len(genericItem.CompletionStatus) > 0 {
-			objectName := genericItem.GenericTemplate
-			var unstruct unstructured.Unstructured
-			unstruct.Object = make(map[string]interface{})
-			var blob interface{}
-			if err := jsons.Unmarshal(objectName.Raw, &blob); err != nil {
-				klog.Errorf("[getAppWrapperCompletionStatus] Error unmarshalling, err=%#v", err)
-			}
-			unstruct.Object = blob.(map[string]interface{}) // set object to the content of the blob after Unmarshalling
-			name := ""
-			if md, ok := unstruct.Object["metadata"]; ok {
-				metadata := md.(map[string]interface{})
-				if objectName, ok := metadata["name"]; ok {
-					name = objectName.(string)
-				}
-			}
+			name := getGenericItemName(&genericItem)
 			if len(name) == 0 {
 				klog.Warningf("[getAppWrapperCompletionStatus] object name not present for appwrapper: '%s/%s", caw.Namespace, caw.Name)
 			}
@@ -603,15 +754,21 @@ func (qjm *XController) getAppWrapperCompletionStatus(caw *arbv1.AppWrapper) arb
 			status := qjm.genericresources.IsItemCompleted(&genericItem, caw.Namespace, caw.Name, name)
 			if !status {
 				klog.V(4).Infof("[getAppWrapperCompletionStatus] Item %d named %s not completed for appwrapper: '%s/%s'", i+1, name, caw.Namespace, caw.Name)
-				// early termination because a required item is not completed
-				return caw.Status.State
+				// keep checking the remaining items so the state of every item is recorded
+				allCompleted = false
+				continue
 			}
+			setItemState(caw, i, arbv1.AppWrapperItemStateCompleted)
 
 			// only consider count completion required for valid items
 			countCompletionRequired = countCompletionRequired + 1
 
 		}
 	}
+	if !allCompleted {
+		// a required item is not completed
+		return caw.Status.State
+	}
 	klog.V(4).Infof("[getAppWrapperCompletionStatus] App wrapper '%s/%s' countCompletionRequired %d, podsRunning %d, podsPending %d", caw.Namespace, caw.Name, countCompletionRequired, caw.Status.Running, caw.Status.Pending)
 
 	// Set new status only when completion required flag is present in genericitems array
@@ -628,11 +785,32 @@ func (qjm *XController) getAppWrapperCompletionStatus(caw *arbv1.AppWrapper) arb
 	return caw.Status.State
 }
 
+// getGenericItemName returns the metadata.name of the generic item template, or an empty string if it is not set
+func getGenericItemName(genericItem *arbv1.AppWrapperGenericResource) string {
+	var blob interface{}
+	if err := jsons.Unmarshal(genericItem.GenericTemplate.Raw, &blob); err != nil {
+		klog.Errorf("[getGenericItemName] Error unmarshalling, err=%#v", err)
+		return ""
+	}
+	object, ok := blob.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if md, ok := object["metadata"]; ok {
+		if metadata, ok := md.(map[string]interface{}); ok {
+			if objectName, ok := metadata["name"].(string); ok {
+				return objectName
+			}
+		}
+	}
+	return ""
+}
+
 func (qjm *XController) GetAggregatedResources(cqj *arbv1.AppWrapper) *clusterstateapi.Resource {
 	allocated := clusterstateapi.EmptyResource()
 
 	for _, genericItem := range cqj.Spec.AggrResources.GenericItems {
-		qjv, err := genericresource.GetResources(&genericItem)
+		qjv, err := genericresource.GetResources(&genericItem, cqj.Namespace)
 		if err != nil {
 			klog.V(8).Infof("[GetAggregatedResources] Failure aggregating resources for %s/%s, err=%#v, genericItem=%#v",
 				cqj.Namespace, cqj.Name, err, genericItem)
@@ -810,7 +988,9 @@ func (qjm *XController) getAggregatedAvailableResourcesPriority(unallocatedClust
 				klog.V(10).Infof("[getAggAvaiResPri] %s: Getting job key for: %s/%s.", time.Now().String(), value.Namespace, value.Name)
 				queueJobKey, _ := GetQueueJobKey(value)
 				klog.V(10).Infof("[getAggAvaiResPri] %s: Getting dispatchid for: %s.", time.Now().String(), queueJobKey)
+				qjm.agentMutex.RLock()
 				dispatchedAgentId := qjm.dispatchMap[queueJobKey]
+				qjm.agentMutex.RUnlock()
 
 				// If this is not in the same cluster then skip
 				if strings.Compare(dispatchedAgentId, agentId) != 0 {
@@ -843,7 +1023,7 @@ func (qjm *XController) getAggregatedAvailableResourcesPriority(unallocatedClust
 		} else if value.Status.CanRun {
 			qjv := clusterstateapi.EmptyResource()
 			for _, genericItem := range value.Spec.AggrResources.GenericItems {
-				res, _ := genericresource.GetResources(&genericItem)
+				res, _ := genericresource.GetResources(&genericItem, value.Namespace)
 				qjv.Add(res)
 				klog.V(10).Infof("[getAggAvaiResPri] Subtract all resources %+v in genericItem=%T for job %s/%s which can-run is set to: %v but state is still pending.", qjv, genericItem, value.Namespace, value.Name, value.Status.CanRun)
 			}
@@ -882,53 +1062,11 @@ func addPreemptableAWs(preemptableAWs map[float64][]string, value *arbv1.AppWrap
 	klog.V(10).Infof("[getAggAvaiResPri] %s: Added %s/%s to candidate preemptable job with priority %f.", time.Now().String(), value.Namespace, value.Name, value.Status.SystemPriority)
 }
 
-func (qjm *XController) chooseAgent(ctx context.Context, qj *arbv1.AppWrapper) string {
-
-	qjAggrResources := qjm.GetAggregatedResources(qj)
-	klog.V(2).Infof("[chooseAgent] Aggregated Resources of XQJ %s/%s: %v\n", qj.Namespace, qj.Name, qjAggrResources)
-
-	agentId := qjm.agentList[rand.Int()%len(qjm.agentList)]
-	klog.V(2).Infof("[chooseAgent] Agent %s is chosen randomly\n", agentId)
-	unallocatedResources := qjm.agentMap[agentId].AggrResources
-	priorityindex := qj.Status.SystemPriority
-	resources, proposedPreemptions := qjm.getAggregatedAvailableResourcesPriority(unallocatedResources, priorityindex, qj, agentId)
-
-	klog.V(2).Infof("[chooseAgent] Aggr Resources of Agent %s: %v\n", agentId, resources)
-
-	if qjAggrResources.LessEqual(resources) {
-		klog.V(2).Infof("[chooseAgent] Agent %s has enough resources\n", agentId)
-
-		// Now evaluate quota
-		if qjm.config.IsQuotaEnabled() {
-			if qjm.quotaManager != nil {
-				if fits, preemptAWs, _ := qjm.quotaManager.Fits(qj, qjAggrResources, nil, proposedPreemptions); fits {
-					klog.V(2).Infof("[chooseAgent] AppWrapper %s/%s has enough quota.\n", qj.Namespace, qj.Name)
-					qjm.preemptAWJobs(ctx, preemptAWs)
-					return agentId
-				} else {
-					klog.V(2).Infof("[chooseAgent] AppWrapper %s/%s does not have enough quota\n", qj.Namespace, qj.Name)
-				}
-			} else {
-				klog.Errorf("[chooseAgent] Quota evaluation is enable but not initialize. AppWrapper %s/%s does not have enough quota\n", qj.Namespace, qj.Name)
-			}
-		} else {
-			// Quota is not enabled to return selected agent
-			return agentId
-		}
-	} else {
-		klog.V(2).Infof("[chooseAgent] Agent %s does not have enough resources\n", agentId)
-	}
-	return ""
-}
-
 // Thread to find queue-job(QJ) for next schedule
-func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
-	ctx := context.Background()
+func (qjm *XController) ScheduleNext(ctx context.Context, qj *arbv1.AppWrapper) {
 	var err error = nil
 	// TODO: do we really need locking now since we have a single thread processing an AW ?
-	qjm.schedulingMutex.Lock()
-	qjm.schedulingAW = qj
-	qjm.schedulingMutex.Unlock()
+	qjm.schedulingAWAtomicSet(qj)
 	// ensure that current active appwrapper is reset at the end of this function, to prevent
 	// the appwrapper from being added in syncjob
 	defer qjm.schedulingAWAtomicSet(nil)
@@ -936,7 +1074,11 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 	scheduleNextRetrier := retrier.New(retrier.ExponentialBackoff(1, 100*time.Millisecond), &EtcdErrorClassifier{})
 	scheduleNextRetrier.SetJitter(0.05)
 	// Retry the execution
-	err = scheduleNextRetrier.Run(func() error {
+	attempt := 0
+	err = scheduleNextRetrier.Run(func() (retryErr error) {
+		attempt++
+		ctx, span := tracing.Start(ctx, "ScheduleNext", tracing.AttemptKey.Int(attempt))
+		defer func() { tracing.End(span, retryErr) }()
 		klog.Infof("[ScheduleNext] activeQ.Pop %s/%s *Delay=%.6f seconds RemainingLength=%d &qj=%p Version=%s Status=%+v", qj.Namespace, qj.Name, time.Now().Sub(qj.Status.ControllerFirstTimestamp.Time).Seconds(), qjm.qjqueue.Length(), qj,
 			qj.ResourceVersion, qj.Status)
 
@@ -1012,6 +1154,13 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 			qjm.schedulingAWAtomicSet(qj)
 		}
 
+		// Concurrency limits are checked before quota evaluation so that over-limit AppWrappers
+		// stay queued instead of cycling through backoff
+		if msg := qjm.getConcurrencyLimitMessage(qj); len(msg) > 0 {
+			klog.Infof("[ScheduleNext] AppWrapper '%s/%s' held in queue: %s", qj.Namespace, qj.Name, msg)
+			return qjm.holdForConcurrencyLimit(ctx, qj, msg)
+		}
+
 		qj.Status.QueueJobState = arbv1.AppWrapperCondHeadOfLine
 		qjm.addOrUpdateCondition(qj, arbv1.AppWrapperCondHeadOfLine, v1.ConditionTrue, "FrontOfQueue.", "")
 
@@ -1026,6 +1175,7 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 			}
 			return retryErr
 		}
+		qjm.eventRecorder.Event(qj, v1.EventTypeNormal, EventReasonHeadOfLine, "AppWrapper is at the head of the queue.")
 		qjm.qjqueue.AddUnschedulableIfNotPresent(qj) // working on qj, avoid other threads putting it back to activeQ
 
 		klog.V(4).Infof("[ScheduleNext] after Pop qjqLength=%d qj %s/%s Version=%s activeQ=%t Unsched=%t Status=%v", qjm.qjqueue.Length(), qj.Namespace, qj.Name, qj.ResourceVersion, qjm.qjqueue.IfExistActiveQ(qj), qjm.qjqueue.IfExistUnschedulableQ(qj), qj.Status)
@@ -1038,12 +1188,15 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 		dispatchFailedReason := "AppWrapperNotRunnable."
 		dispatchFailedMessage := ""
 		if qjm.isDispatcher { // Dispatcher Mode
-			agentId := qjm.chooseAgent(ctx, qj)
+			agentId, agentReason, agentMessage := qjm.chooseAgent(ctx, qj)
 			if agentId != "" { // A proper agent is found.
 				// Update states (CanRun=True) of XQJ in API Server
 				// Add XQJ -> Agent Map
 				apiCacheAWJob, retryErr := qjm.getAppWrapper(qj.Namespace, qj.Name, "[ScheduleNext] [Dispatcher Mode] get appwrapper")
 				if retryErr != nil {
+					if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+						qjm.quotaManager.Release(qj)
+					}
 					if apierrors.IsNotFound(retryErr) {
 						klog.Warningf("[ScheduleNext] app wrapper '%s/%s' not found skipping dispatch", qj.Namespace, qj.Name)
 						return nil
@@ -1058,11 +1211,24 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 					apiCacheAWJob.DeepCopyInto(qj)
 				}
 				qj.Status.CanRun = true
+				qj.Status.SchedulingDiagnostics = nil
+				qj.Status.QueuePosition = nil
+				qjm.addOrUpdateCondition(qj, arbv1.AppWrapperCondDispatched, v1.ConditionTrue, agentReason, agentMessage)
 				queueJobKey, _ := GetQueueJobKey(qj)
+				qjm.agentMutex.Lock()
 				qjm.dispatchMap[queueJobKey] = agentId
+				qjm.agentMutex.Unlock()
+				qj.Status.DispatchedAgent = agentId
 				klog.V(10).Infof("[ScheduleNext] [Dispatcher Mode] %s/%s, %s: ScheduleNextBeforeEtcd", qj.Namespace, qj.Name, time.Now().Sub(qj.CreationTimestamp.Time))
 				retryErr = qjm.updateStatusInEtcd(ctx, qj, "[ScheduleNext] [Dispatcher Mode] - setCanRun")
 				if retryErr != nil {
+					// the AppWrapper is not dispatched, release the quota allocated in the selected agent
+					qjm.agentMutex.Lock()
+					delete(qjm.dispatchMap, queueJobKey)
+					qjm.agentMutex.Unlock()
+					if qjm.config.IsQuotaEnabled() && qjm.quotaManager != nil {
+						qjm.quotaManager.Release(qj)
+					}
 					if apierrors.IsConflict(err) {
 						klog.Warningf("[ScheduleNext] [Dispatcher Mode] Conflict error detected when updating status in etcd for app wrapper '%s/%s, status = %+v. Retrying update.", qj.Namespace, qj.Name, qj.Status)
 					} else {
@@ -1071,10 +1237,16 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 					return retryErr
 				}
 				klog.V(10).Infof("[ScheduleNext] [Dispatcher Mode] %s/%s, %s: ScheduleNextAfterEtcd", qj.Namespace, qj.Name, time.Now().Sub(qj.CreationTimestamp.Time))
+				recordDispatch(qj, agentReason)
+				qjm.eventRecorder.Event(qj, v1.EventTypeNormal, EventReasonDispatched, agentMessage)
 				return nil
 			} else {
-				dispatchFailedMessage = "Cannot find a cluster with enough resources to dispatch AppWrapper."
+				dispatchFailedReason = agentReason
+				dispatchFailedMessage = agentMessage
 				klog.V(2).Infof("[ScheduleNex] [Dispatcher Mode] %s %s\n", dispatchFailedReason, dispatchFailedMessage)
+				setSchedulingDiagnostics(qj, newSchedulingDiagnostics(dispatchFailedMessage, qjm.GetAggregatedResources(qj), nil, nil, nil,
+					qjm.qjqueue.CountAhead(qj)), time.Now())
+				recordDispatchFailure(dispatchFailedReason)
 				qjm.backoff(ctx, qj, dispatchFailedReason, dispatchFailedMessage)
 			}
 		} else { // Agent Mode
@@ -1099,6 +1271,7 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 				}
 				// cache now is a method inside the controller.
 				// The reimplementation should fix issue : https://github.com/project-codeflare/multi-cluster-app-dispatcher/issues/550
+				_, capacitySpan := tracing.Start(ctx, "allocatableCapacity")
 				var unallocatedResources = clusterstateapi.EmptyResource()
 				unallocatedResources = qjm.allocatableCapacity()
 				for unallocatedResources.IsEmpty() {
@@ -1107,8 +1280,12 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 						break
 					}
 				}
+				capacitySpan.End()
+				_, availableSpan := tracing.Start(ctx, "getAggregatedAvailableResourcesPriority")
 				resources, proposedPreemptions := qjm.getAggregatedAvailableResourcesPriority(
 					unallocatedResources, priorityindex, qj, "")
+				availableSpan.SetAttributes(attribute.Int("proposedPreemptions", len(proposedPreemptions)))
+				availableSpan.End()
 				klog.Infof("[ScheduleNext] [Agent Mode] Appwrapper '%s/%s' with resources %v to be scheduled on aggregated idle resources %v", qj.Namespace, qj.Name, aggqj, resources)
 
 				// Jobs dispatched with quota management may be borrowing quota from other tree nodes making those jobs preemptable, regardless of their priority.
@@ -1163,22 +1340,33 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 						// Allocate consumer into quota tree and check if there are enough resources to dispatch it
 						var msg string
 						var preemptAWs []*arbv1.AppWrapper
+						_, fitsSpan := tracing.Start(ctx, "quota.Fits")
 						quotaFits, preemptAWs, msg = qjm.quotaManager.Fits(qj, aggqj, resources, proposedPreemptions)
+						fitsSpan.SetAttributes(attribute.Bool("fits", quotaFits), attribute.Int("preemptions", len(preemptAWs)),
+							attribute.String("message", msg))
+						fitsSpan.End()
 						klog.Info("%s %s %s", quotaFits, preemptAWs, msg)
 
 						if quotaFits {
 							klog.Infof("[ScheduleNext] [Agent mode] quota evaluation successful for app wrapper '%s/%s' activeQ=%t Unsched=%t &qj=%p Version=%s Status=%+v",
 								qj.Namespace, qj.Name, time.Now().Sub(HOLStartTime), qjm.qjqueue.IfExistActiveQ(qj), qjm.qjqueue.IfExistUnschedulableQ(qj), qj, qj.ResourceVersion, qj.Status)
 							// Set any jobs that are marked for preemption
-							qjm.preemptAWJobs(ctx, preemptAWs)
+							qjm.preemptAWJobs(ctx, qj, preemptAWs)
 						} else { // Not enough free quota to dispatch appwrapper
 							dispatchFailedMessage = "Insufficient quota and/or resources to dispatch AppWrapper."
 							dispatchFailedReason = "quota limit exceeded"
+							victims := preemptAWs
+							if len(victims) == 0 {
+								victims = proposedPreemptions
+							}
+							setSchedulingDiagnostics(qj, newSchedulingDiagnostics(strings.TrimSpace(dispatchFailedMessage+" "+msg), aggqj, resources,
+								qjm.quotaManager.GetQuotaRejections(qj, aggqj), victims, qjm.qjqueue.CountAhead(qj)), time.Now())
 							klog.Infof("[ScheduleNext] [Agent Mode] Blocking dispatch for app wrapper '%s/%s' due to quota limits, activeQ=%t Unsched=%t &qj=%p Version=%s Status=%+v msg=%s",
 								qj.Namespace, qj.Name, time.Now().Sub(HOLStartTime), qjm.qjqueue.IfExistActiveQ(qj), qjm.qjqueue.IfExistUnschedulableQ(qj), qj, qj.ResourceVersion, qj.Status, msg)
 							// Call update etcd here to retrigger AW execution for failed quota
 							// TODO: quota management tests fail if this is converted into go-routine, need to inspect why?
-							qjm.backoff(context.Background(), qj, dispatchFailedReason, dispatchFailedMessage)
+							recordDispatchFailure(dispatchFailedReason)
+							qjm.backoff(ctx, qj, dispatchFailedReason, dispatchFailedMessage)
 						}
 						fits = quotaFits
 					} else {
@@ -1198,12 +1386,15 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 					} else { // Not enough free resources to dispatch HOL
 						fits = false
 						dispatchFailedMessage = "Insufficient resources to dispatch AppWrapper."
+						setSchedulingDiagnostics(qj, newSchedulingDiagnostics(dispatchFailedMessage, aggqj, resources, nil, proposedPreemptions,
+							qjm.qjqueue.CountAhead(qj)), time.Now())
 						klog.Infof("[ScheduleNext] [Agent Mode] Failed to dispatch app wrapper '%s/%s' due to insufficient resources, activeQ=%t Unsched=%t &qj=%p Version=%s Status=%+v",
 							qj.Namespace, qj.Name, qjm.qjqueue.IfExistActiveQ(qj),
 							qjm.qjqueue.IfExistUnschedulableQ(qj), qj, qj.ResourceVersion, qj.Status)
 						// TODO: Remove forwarded logic as a big AW will never be forwarded
 						forwarded = true
 						// should we call backoff or update etcd?
+						recordDispatchFailure(dispatchFailedReason)
 						qjm.backoff(ctx, qj, dispatchFailedReason, dispatchFailedMessage)
 					}
 				}
@@ -1219,6 +1410,8 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 						return retryErr
 					}
 					tempAW.Status.CanRun = true
+					tempAW.Status.SchedulingDiagnostics = nil
+					tempAW.Status.QueuePosition = nil
 					tempAW.Status.FilterIgnore = true // update CanRun & Spec.  no need to trigger event
 					retryErr = qjm.updateStatusInEtcd(ctx, tempAW, "ScheduleNext - setCanRun")
 					if retryErr != nil {
@@ -1237,6 +1430,7 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 						return retryErr
 					}
 					tempAW.DeepCopyInto(qj)
+					recordDispatch(qj, "")
 					forwarded = true
 				}
 
@@ -1267,6 +1461,7 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 				if qjm.quotaManager != nil && quotaFits {
 					qjm.quotaManager.Release(qj)
 				}
+				recordDispatchFailure(dispatchFailedReason)
 				qjm.backoff(ctx, qj, dispatchFailedReason, dispatchFailedMessage)
 			}
 		}
@@ -1285,7 +1480,9 @@ func (qjm *XController) ScheduleNext(qj *arbv1.AppWrapper) {
 
 // Update AppWrappers in etcd
 // todo: This is a current workaround for duplicate message bug.
-func (cc *XController) updateEtcd(ctx context.Context, currentAppwrapper *arbv1.AppWrapper, caller string) (*arbv1.AppWrapper, error) {
+func (cc *XController) updateEtcd(ctx context.Context, currentAppwrapper *arbv1.AppWrapper, caller string) (_ *arbv1.AppWrapper, err error) {
+	ctx, span := tracing.Start(ctx, "updateEtcd", tracing.CallerKey.String(caller))
+	defer func() { tracing.End(span, err) }()
 	klog.V(4).Infof("[updateEtcd] trying to update '%s/%s' called by '%s'", currentAppwrapper.Namespace, currentAppwrapper.Name, caller)
 	currentAppwrapper.Status.Sender = "before " + caller // set Sender string to indicate code location
 	currentAppwrapper.Status.Local = false               // for Informer FilterFunc to pickup
@@ -1302,7 +1499,9 @@ func (cc *XController) updateEtcd(ctx context.Context, currentAppwrapper *arbv1.
 	return updatedAppwrapper.DeepCopy(), nil
 }
 
-func (cc *XController) updateStatusInEtcd(ctx context.Context, currentAppwrapper *arbv1.AppWrapper, caller string) error {
+func (cc *XController) updateStatusInEtcd(ctx context.Context, currentAppwrapper *arbv1.AppWrapper, caller string) (err error) {
+	ctx, span := tracing.Start(ctx, "updateStatusInEtcd", tracing.CallerKey.String(caller))
+	defer func() { tracing.End(span, err) }()
 	klog.V(4).Infof("[updateStatusInEtcd] trying to update '%s/%s' called by '%s'", currentAppwrapper.Namespace, currentAppwrapper.Name, caller)
 	currentAppwrapper.Status.Sender = "before " + caller // set Sender string to indicate code location
 	updatedAppwrapper, err := cc.arbclients.WorkloadV1beta1().AppWrappers(currentAppwrapper.Namespace).UpdateStatus(ctx, currentAppwrapper, metav1.UpdateOptions{})
@@ -1318,14 +1517,19 @@ func (cc *XController) updateStatusInEtcd(ctx context.Context, currentAppwrapper
 	return nil
 }
 
-func (cc *XController) updateStatusInEtcdWithRetry(ctx context.Context, source *arbv1.AppWrapper, caller string) error {
+func (cc *XController) updateStatusInEtcdWithRetry(ctx context.Context, source *arbv1.AppWrapper, caller string) (err error) {
+	ctx, span := tracing.Start(ctx, "updateStatusInEtcdWithRetry", tracing.CallerKey.String(caller))
+	defer func() { tracing.End(span, err) }()
 	klog.V(4).Infof("[updateStatusInEtcdWithMergeFunction] trying to update '%s/%s' version '%s' called by '%s'", source.Namespace, source.Name, source.ResourceVersion, caller)
 	source.Status.Sender = "before " + caller // set Sender string to indicate code location
 	updateStatusRetrierRetrier := retrier.New(retrier.ExponentialBackoff(1, 100*time.Millisecond), &EtcdErrorClassifier{})
 	updateStatusRetrierRetrier.SetJitter(0.05)
 	updatedAW := source.DeepCopy()
-	err := updateStatusRetrierRetrier.RunCtx(ctx, func(localContext context.Context) error {
-		var retryErr error
+	attempt := 0
+	err = updateStatusRetrierRetrier.RunCtx(ctx, func(localContext context.Context) (retryErr error) {
+		attempt++
+		localContext, attemptSpan := tracing.Start(localContext, "UpdateStatus", tracing.AttemptKey.Int(attempt))
+		defer func() { tracing.End(attemptSpan, retryErr) }()
 		updatedAW, retryErr = cc.arbclients.WorkloadV1beta1().AppWrappers(updatedAW.Namespace).UpdateStatus(localContext, updatedAW, metav1.UpdateOptions{})
 		if retryErr != nil && apierrors.IsConflict(retryErr) {
 			dest, retryErr := cc.getAppWrapper(source.Namespace, source.Name, caller)
@@ -1355,7 +1559,9 @@ func (qjm *XController) addOrUpdateCondition(aw *arbv1.AppWrapper, condType arbv
 	var dupConditionExists bool = false
 	if aw.Status.Conditions != nil && len(aw.Status.Conditions) > 0 {
 		// Find a matching condition based on fields not related to timestamps
-		for _, condition := range aw.Status.Conditions {
+		for i := range aw.Status.Conditions {
+			// update the condition in place, its timestamps record the latest occurrence
+			condition := &aw.Status.Conditions[i]
 			if condition.Type == condType && condition.Status == condStatus &&
 				condition.Reason == condReason && condition.Message == condMsg {
 				oldLastUpdateMicroTime := condition.LastUpdateMicroTime
@@ -1376,8 +1582,63 @@ func (qjm *XController) addOrUpdateCondition(aw *arbv1.AppWrapper, condType arbv
 	}
 }
 
-func (qjm *XController) backoff(ctx context.Context, q *arbv1.AppWrapper, reason string, message string) {
+// getConcurrencyLimitMessage checks the AppWrapper against the concurrency limits, returns an empty
+// string if it can be dispatched or the reason it must stay queued otherwise.
+func (qjm *XController) getConcurrencyLimitMessage(qj *arbv1.AppWrapper) string {
+	if !qjm.config.HasConcurrencyLimits() || qjm.concurrencyUsage == nil {
+		return ""
+	}
+	namespaceCount, userCount := qjm.concurrencyUsage.getCounts(qj)
+	return getConcurrencyLimitMessage(qjm.config.ConcurrencyLimits, qj, namespaceCount, userCount, qjm.getPodCount(qj))
+}
+
+// holdForConcurrencyLimit keeps the AppWrapper queued until a dispatched AppWrapper terminates.
+// The status is only updated when it changes, to avoid triggering another scheduling cycle.
+func (qjm *XController) holdForConcurrencyLimit(ctx context.Context, qj *arbv1.AppWrapper, message string) error {
+	queueJobKey, _ := GetQueueJobKey(qj)
+	qjm.concurrencyMutex.Lock()
+	qjm.concurrencyWaiters[queueJobKey] = qj
+	qjm.concurrencyMutex.Unlock()
+	qjm.qjqueue.AddUnschedulableIfNotPresent(qj)
+
+	oldStatus := qj.Status.DeepCopy()
+	qj.Status.QueueJobState = arbv1.AppWrapperCondQueueing
+	qjm.addOrUpdateCondition(qj, arbv1.AppWrapperCondQueueing, v1.ConditionTrue, "ConcurrencyLimitReached", message)
+	if equality.Semantic.DeepEqual(*oldStatus, qj.Status) {
+		return nil
+	}
+	qj.Status.FilterIgnore = true // update QueueJobState only
+	err := qjm.updateStatusInEtcd(ctx, qj, "[holdForConcurrencyLimit] setConcurrencyLimitReached")
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		klog.Warningf("[holdForConcurrencyLimit] Unable to update status of app wrapper '%s/%s', err=%v", qj.Namespace, qj.Name, err)
+		return nil
+	}
+	return err
+}
+
+// releaseConcurrencyWaiters enqueues the AppWrappers held by concurrency limits for another
+// scheduling cycle.  It is called when a dispatched AppWrapper terminates or is deleted.
+func (cc *XController) releaseConcurrencyWaiters() {
+	cc.concurrencyMutex.Lock()
+	waiters := cc.concurrencyWaiters
+	cc.concurrencyWaiters = map[string]*arbv1.AppWrapper{}
+	cc.concurrencyMutex.Unlock()
+	for _, qj := range waiters {
+		apiCacheAWJob, err := cc.getAppWrapper(qj.Namespace, qj.Name, "[releaseConcurrencyWaiters]")
+		if err != nil {
+			continue
+		}
+		if !apiCacheAWJob.Status.CanRun {
+			klog.V(4).Infof("[releaseConcurrencyWaiters] releasing '%s/%s'", qj.Namespace, qj.Name)
+			cc.qjqueue.MoveToActiveQueueIfExists(apiCacheAWJob)
+			cc.enqueue(apiCacheAWJob)
+		}
+	}
+}
 
+func (qjm *XController) backoff(ctx context.Context, q *arbv1.AppWrapper, reason string, message string) {
+	ctx, span := tracing.Start(ctx, "backoff", attribute.String("reason", reason))
+	defer span.End()
 	etcUpdateRetrier := retrier.New(retrier.ExponentialBackoff(10, 100*time.Millisecond), &EtcdErrorClassifier{})
 	err := etcUpdateRetrier.Run(func() error {
 		apiCacheAWJob, retryErr := qjm.getAppWrapper(q.Namespace, q.Name, "[backoff] - Rejoining")
@@ -1400,6 +1661,7 @@ func (qjm *XController) backoff(ctx context.Context, q *arbv1.AppWrapper, reason
 	if err != nil {
 		klog.Errorf("[backoff] Failed to update status for %s/%s.  Continuing with possible stale object without updating conditions. err=%s", q.Namespace, q.Name, err)
 	}
+	qjm.eventRecorder.Eventf(q, v1.EventTypeWarning, EventReasonBackoff, "%s %s", reason, message)
 	qjm.qjqueue.AddUnschedulableIfNotPresent(q)
 	klog.V(3).Infof("[backoff] %s/%s move to unschedulableQ before sleep for %d seconds. activeQ=%t Unsched=%t &qj=%p Version=%s Status=%+v", q.Namespace, q.Name,
 		qjm.config.BackoffTimeOrDefault(defaultBackoffTime), qjm.qjqueue.IfExistActiveQ(q), qjm.qjqueue.IfExistUnschedulableQ(q), q, q.ResourceVersion, q.Status)
@@ -1410,27 +1672,105 @@ func (qjm *XController) backoff(ctx context.Context, q *arbv1.AppWrapper, reason
 		qjm.config.BackoffTimeOrDefault(defaultBackoffTime), qjm.qjqueue.IfExistActiveQ(q), qjm.qjqueue.IfExistUnschedulableQ(q), q, q.ResourceVersion, q.Status)
 }
 
+// restartFailedAppWrapper returns a failed AppWrapper to the queue if its restart policy allows it.
+// The caller is responsible for cleaning up the resources of the AppWrapper and for updating its status.
+// Returns false when the AppWrapper has exhausted its restarts and must remain in the Failed state.
+func (qjm *XController) restartFailedAppWrapper(aw *arbv1.AppWrapper, reason string, message string) bool {
+	if !canRestart(aw) {
+		return false
+	}
+	metrics.Requeuings.WithLabelValues(reason).Inc()
+	aw.Status.NumberOfRestarts += 1
+	aw.Status.State = arbv1.AppWrapperStateEnqueued
+	aw.Status.QueueJobState = arbv1.AppWrapperCondRestarting
+	aw.Status.CanRun = false
+	aw.Status.IsDispatched = false
+	aw.Status.PendingPodConditions = nil
+	// the dispatch deadline applies to each attempt, it is reset when the AppWrapper is dispatched again
+	aw.Status.ControllerFirstDispatchTimestamp = metav1.MicroTime{}
+	cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondRestarting, v1.ConditionTrue, reason, getRestartMessage(aw, message))
+	aw.Status.Conditions = append(aw.Status.Conditions, cond)
+	klog.Infof("[restartFailedAppWrapper] Restarting failed AppWrapper %s/%s, restart %d of %d, reason: %s.", aw.Namespace, aw.Name,
+		aw.Status.NumberOfRestarts, aw.Spec.RestartPolicy.MaxRestarts, reason)
+	return true
+}
+
+// getRestartMessage returns the message of the condition and of the event of a restarted AppWrapper
+func getRestartMessage(aw *arbv1.AppWrapper, message string) string {
+	return fmt.Sprintf("Restart %d of %d after failure: %s", aw.Status.NumberOfRestarts, aw.Spec.RestartPolicy.MaxRestarts, message)
+}
+
+// recordFailureEvent records the Failed event of an AppWrapper, or its Restarting event when it was returned to the queue
+func (qjm *XController) recordFailureEvent(aw *arbv1.AppWrapper, restarted bool, message string) {
+	if restarted {
+		qjm.eventRecorder.Event(aw, v1.EventTypeWarning, EventReasonRestarting, getRestartMessage(aw, message))
+		return
+	}
+	qjm.eventRecorder.Event(aw, v1.EventTypeWarning, EventReasonFailed, message)
+}
+
 // Run starts AppWrapper Controller
 func (cc *XController) Run(stopCh <-chan struct{}) {
 	go cc.appwrapperInformer.Informer().Run(stopCh)
+	go func() {
+		<-stopCh
+		close(cc.limitRangeStopCh)
+	}()
 
 	cache.WaitForCacheSync(stopCh, cc.appWrapperSynced)
 
 	if cc.isDispatcher {
-		go wait.Until(cc.UpdateAgent, 2*time.Second, stopCh) // In the Agent?
+		cc.rebuildDispatchMap()
+		cc.agentMutex.RLock()
 		for _, jobClusterAgent := range cc.agentMap {
 			go jobClusterAgent.Run(stopCh)
 		}
+		cc.agentMutex.RUnlock()
+		go cc.clusterAgentInformer.Informer().Run(stopCh)
+		cache.WaitForCacheSync(stopCh, cc.clusterAgentSynced)
+		go func() {
+			<-stopCh
+			cc.clusterAgentQueue.ShutDown()
+		}()
+		go wait.Until(cc.clusterAgentWorker, 0, stopCh)
+		go wait.Until(cc.checkClusterAgents, clusterAgentHealthPeriod, stopCh)
+		go wait.Until(cc.UpdateAgent, 2*time.Second, stopCh) // In the Agent?
+		go wait.Until(cc.checkAgentHealth, agentHealthPeriod, stopCh)
+		go wait.Until(cc.reconcileAgentJobs, agentReconcilePeriod, stopCh)
 		go wait.Until(cc.agentEventQueueWorker, time.Second, stopCh) // Update Agent Worker
+	} else {
+		cc.genericresources.StartItemInformers(stopCh)
+		go func() {
+			<-stopCh
+			cc.itemEventQueue.ShutDown()
+		}()
+		go wait.Until(cc.itemEventWorker, 0, stopCh)
+		if cc.capacityReportPeriod > 0 {
+			go wait.Until(cc.reportCapacity, cc.capacityReportPeriod, stopCh)
+		}
 	}
 
+	go wait.Until(cc.updateMetrics, metricsUpdatePeriod, stopCh)
+	go wait.Until(cc.updateQueuePositions, queuePositionUpdatePeriod, stopCh)
+	go func() {
+		<-stopCh
+		cc.restartQueue.ShutDown()
+	}()
+	go wait.Until(cc.restartWorker, 0, stopCh)
 	go wait.Until(cc.worker, 0, stopCh)
 }
 
 func (qjm *XController) UpdateAgent() {
 	ctx := context.Background()
 	klog.V(3).Infof("[Controller] Update AggrResources for All Agents\n")
+	qjm.agentMutex.RLock()
+	agents := make([]*queuejobdispatch.JobClusterAgent, 0, len(qjm.agentMap))
 	for _, jobClusterAgent := range qjm.agentMap {
+		agents = append(agents, jobClusterAgent)
+	}
+	qjm.agentMutex.RUnlock()
+	// query the agents without holding the lock, an unreachable agent may take a while to time out
+	for _, jobClusterAgent := range agents {
 		jobClusterAgent.UpdateAggrResources(ctx)
 	}
 }
@@ -1439,6 +1779,10 @@ func (qjm *XController) UpdateAgent() {
 // Do not use event queues! Running AWs move to Completed, from which it will never transition to any other state.
 // State transition: Running->RunningHoldCompletion->Completed
 func (qjm *XController) UpdateQueueJobs(newjob *arbv1.AppWrapper) {
+	// in dispatcher mode the pods run in the agent clusters, the status is mirrored from the agents
+	if qjm.isDispatcher {
+		return
+	}
 
 	if newjob.Status.State == arbv1.AppWrapperStateActive || newjob.Status.State == arbv1.AppWrapperStateRunningHoldCompletion {
 		err := qjm.UpdateQueueJobStatus(newjob)
@@ -1449,7 +1793,15 @@ func (qjm *XController) UpdateQueueJobs(newjob *arbv1.AppWrapper) {
 		}
 		klog.V(6).Infof("[UpdateQueueJobs] %s/%s: qjqueue=%t &qj=%p Version=%s Status=%+v", newjob.Namespace, newjob.Name, qjm.qjqueue.IfExist(newjob), newjob, newjob.ResourceVersion, newjob.Status)
 		// set appwrapper status to Complete or RunningHoldCompletion
+		itemsBefore := newjob.DeepCopy().Status.Items
 		derivedAwStatus := qjm.getAppWrapperCompletionStatus(newjob)
+		if derivedAwStatus == newjob.Status.State && !equality.Semantic.DeepEqual(itemsBefore, newjob.Status.Items) {
+			newjob.Status.FilterIgnore = true // Update item states
+			err := qjm.updateStatusInEtcdWithRetry(context.Background(), newjob.DeepCopy(), "[UpdateQueueJobs] setItemStates")
+			if err != nil {
+				klog.Errorf("[UpdateQueueJobs]  Error updating status 'setItemStates' AppWrapper: '%s/%s',Status=%+v, err=%+v.", newjob.Namespace, newjob.Name, newjob.Status, err)
+			}
+		}
 
 		klog.Infof("[UpdateQueueJobs]  Got completion status '%s' for app wrapper '%s/%s' Version=%s Status.CanRun=%t Status.State=%s, pod counts [Pending: %d, Running: %d, Succeded: %d, Failed %d]", derivedAwStatus, newjob.Namespace, newjob.Name, newjob.ResourceVersion,
 			newjob.Status.CanRun, newjob.Status.State, newjob.Status.Pending, newjob.Status.Running, newjob.Status.Succeeded, newjob.Status.Failed)
@@ -1501,6 +1853,8 @@ func (qjm *XController) UpdateQueueJobs(newjob *arbv1.AppWrapper) {
 				}
 				// TODO: Implement retry
 				klog.Errorf("[UpdateQueueJobs]  Error updating status 'setCompleted' AppWrapper: '%s/%s',Status=%+v, err=%+v.", newjob.Namespace, newjob.Name, newjob.Status, err)
+			} else {
+				qjm.eventRecorder.Event(updateQj, v1.EventTypeNormal, EventReasonCompleted, "All items of the AppWrapper completed.")
 			}
 			if qjm.quotaManager != nil {
 				qjm.quotaManager.Release(updateQj)
@@ -1509,11 +1863,135 @@ func (qjm *XController) UpdateQueueJobs(newjob *arbv1.AppWrapper) {
 			qjm.eventQueue.Delete(updateQj)
 			qjm.qjqueue.Delete(updateQj)
 		}
+		// Set appwrapper status to failed, remove its items and release its quota
+		if derivedAwStatus == arbv1.AppWrapperStateFailed {
+			newjob.Status.State = derivedAwStatus
+			newjob.Status.CanRun = false
+			newjob.Status.QueueJobState = arbv1.AppWrapperCondFailed
+			newjob.Status.FilterIgnore = true // Update AppWrapperCondFailed
+			updateQj := newjob.DeepCopy()
+			if err := qjm.Cleanup(context.Background(), updateQj); err != nil {
+				klog.Errorf("[UpdateQueueJobs] Failed to delete resources associated with failed app wrapper: '%s/%s', err %v", newjob.Namespace, newjob.Name, err)
+			}
+			// Delete AW from both queue's, a restarted AW is enqueued again by the informer once its status is updated
+			qjm.eventQueue.Delete(updateQj)
+			qjm.qjqueue.Delete(updateQj)
+			restarted := qjm.restartFailedAppWrapper(updateQj, "ItemFailed", "One or more generic items reported a failure condition.")
+			if !restarted {
+				index := getIndexOfMatchedCondition(updateQj, arbv1.AppWrapperCondFailed, "ItemFailed")
+				cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondFailed, v1.ConditionTrue, "ItemFailed", "One or more generic items reported a failure condition.")
+				if index < 0 {
+					updateQj.Status.Conditions = append(updateQj.Status.Conditions, cond)
+				} else {
+					updateQj.Status.Conditions[index] = *cond.DeepCopy()
+				}
+			}
+			err := qjm.updateStatusInEtcdWithRetry(context.Background(), updateQj, "[UpdateQueueJobs] setFailed")
+			if err != nil {
+				klog.Errorf("[UpdateQueueJobs]  Error updating status 'setFailed' AppWrapper: '%s/%s',Status=%+v, err=%+v.", newjob.Namespace, newjob.Name, updateQj.Status, err)
+			} else {
+				qjm.recordFailureEvent(updateQj, restarted, "One or more generic items reported a failure condition.")
+			}
+		}
 		klog.Infof("[UpdateQueueJobs]  Done getting completion status for app wrapper '%s/%s' Version=%s Status.CanRun=%t Status.State=%s, pod counts [Pending: %d, Running: %d, Succeded: %d, Failed %d]", newjob.Namespace, newjob.Name, newjob.ResourceVersion,
 			newjob.Status.CanRun, newjob.Status.State, newjob.Status.Pending, newjob.Status.Running, newjob.Status.Succeeded, newjob.Status.Failed)
 	}
 }
 
+// UpdateQueueJobStages creates the generic items of the next stage of a dispatched AppWrapper once all items of
+// the current and earlier stages are ready. The AppWrapper is failed when one of these items does not become
+// ready within its readiness timeout.
+func (qjm *XController) UpdateQueueJobStages(aw *arbv1.AppWrapper) {
+	if aw.Status.State != arbv1.AppWrapperStateActive || !aw.Status.CanRun {
+		return
+	}
+	nextStage, hasNextStage := getNextStage(aw, aw.Status.CurrentStage)
+	if !hasNextStage {
+		return
+	}
+	ctx := context.Background()
+	newjob := aw.DeepCopy()
+	for i := range newjob.Spec.AggrResources.GenericItems {
+		genericItem := &newjob.Spec.AggrResources.GenericItems[i]
+		if genericItem.Stage > newjob.Status.CurrentStage {
+			continue
+		}
+		genericItemName := getGenericItemName(genericItem)
+		ready, creationTime := qjm.genericresources.IsItemReady(genericItem, newjob.Namespace, newjob.Name, genericItemName)
+		if ready {
+			if genericItem.Readiness != nil {
+				setItemState(newjob, i, arbv1.AppWrapperItemStateReady)
+			}
+			continue
+		}
+		// the object of the item may never appear, its readiness timeout then starts when its stage was dispatched
+		timeoutStart := creationTime
+		if timeoutStart.IsZero() {
+			timeoutStart = getStageDispatchTime(newjob, genericItem.Stage)
+		}
+		if genericItem.Readiness != nil && genericItem.Readiness.TimeoutInSeconds > 0 && !timeoutStart.IsZero() &&
+			time.Now().After(timeoutStart.Add(time.Duration(genericItem.Readiness.TimeoutInSeconds)*time.Second)) {
+			message := fmt.Sprintf("Item %s of stage %d did not become ready within %d seconds.", genericItemName, genericItem.Stage, genericItem.Readiness.TimeoutInSeconds)
+			qjm.failStagedAppWrapper(ctx, newjob, "StageTimeout", message)
+			return
+		}
+		if genericItem.Readiness != nil && genericItem.Readiness.TimeoutInSeconds > 0 && !timeoutStart.IsZero() {
+			// evaluate the stage again when the item times out, the item may not change until then
+			qjm.enqueueItemEvaluationAfter(newjob, time.Until(timeoutStart.Add(time.Duration(genericItem.Readiness.TimeoutInSeconds)*time.Second)))
+		}
+		klog.V(4).Infof("[UpdateQueueJobStages] Waiting for item %s of stage %d of app wrapper '%s/%s' to become ready.", genericItemName, genericItem.Stage, newjob.Namespace, newjob.Name)
+		return
+	}
+
+	klog.V(4).Infof("[UpdateQueueJobStages] Dispatching the items of stage %d of app wrapper '%s/%s'.", nextStage, newjob.Namespace, newjob.Name)
+	for i, ar := range newjob.Spec.AggrResources.GenericItems {
+		if ar.Stage != nextStage {
+			continue
+		}
+		itemStatus, err := qjm.genericresources.SyncQueueJob(newjob, &ar)
+		if itemStatus != nil {
+			itemStatus.Index = i
+			setItemStatus(newjob, *itemStatus)
+		}
+		if err != nil {
+			klog.Errorf("[UpdateQueueJobStages] Error dispatching generic item of stage %d for app wrapper='%s/%s' err=%v", nextStage, newjob.Namespace, newjob.Name, err)
+			qjm.eventRecorder.Eventf(newjob, v1.EventTypeWarning, EventReasonItemCreationFailed, "Failed to create an item of stage %d: %v", nextStage, err)
+			qjm.failStagedAppWrapper(ctx, newjob, "ItemCreationFailure.", fmt.Sprintf("%s/%s creation failure: %+v", newjob.Namespace, newjob.Name, err))
+			return
+		}
+	}
+	newjob.Status.CurrentStage = nextStage
+	qjm.addOrUpdateCondition(newjob, arbv1.AppWrapperCondDispatched, v1.ConditionTrue, "StageDispatched", getStageDispatchedMessage(nextStage))
+	newjob.Status.FilterIgnore = true // Update CurrentStage
+	if err := qjm.updateStatusInEtcdWithRetry(ctx, newjob, "[UpdateQueueJobStages] setCurrentStage"); err != nil {
+		klog.Errorf("[UpdateQueueJobStages] Error updating status 'setCurrentStage' AppWrapper: '%s/%s',Status=%+v, err=%+v.", newjob.Namespace, newjob.Name, newjob.Status, err)
+	}
+}
+
+// failStagedAppWrapper fails an AppWrapper whose stages could not be completed, removing its items and releasing its quota.
+func (qjm *XController) failStagedAppWrapper(ctx context.Context, aw *arbv1.AppWrapper, reason string, message string) {
+	aw.Status.State = arbv1.AppWrapperStateFailed
+	aw.Status.QueueJobState = arbv1.AppWrapperCondFailed
+	aw.Status.CanRun = false
+	if err := qjm.Cleanup(ctx, aw); err != nil {
+		klog.Errorf("[failStagedAppWrapper] Failed to delete resources associated with failed app wrapper: '%s/%s', err %v", aw.Namespace, aw.Name, err)
+	}
+	// Delete AW from both queue's, a restarted AW is enqueued again by the informer once its status is updated
+	qjm.eventQueue.Delete(aw)
+	qjm.qjqueue.Delete(aw)
+	restarted := qjm.restartFailedAppWrapper(aw, reason, message)
+	if !restarted && !isLastConditionDuplicate(aw, arbv1.AppWrapperCondFailed, v1.ConditionTrue, reason, message) {
+		cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondFailed, v1.ConditionTrue, reason, message)
+		aw.Status.Conditions = append(aw.Status.Conditions, cond)
+	}
+	aw.Status.FilterIgnore = true // Update AppWrapperCondFailed
+	if err := qjm.updateStatusInEtcdWithRetry(ctx, aw, "[failStagedAppWrapper] setFailed"); err != nil {
+		klog.Errorf("[failStagedAppWrapper] Error updating status 'setFailed' AppWrapper: '%s/%s',Status=%+v, err=%+v.", aw.Namespace, aw.Name, aw.Status, err)
+		return
+	}
+	qjm.recordFailureEvent(aw, restarted, message)
+}
+
 func (cc *XController) addQueueJob(obj interface{}) {
 	firstTime := metav1.NowMicro()
 	qj, ok := obj.(*arbv1.AppWrapper)
@@ -1548,58 +2026,10 @@ func (cc *XController) addQueueJob(obj interface{}) {
 
 	klog.V(6).Infof("[Informer-addQJ] enqueue %s/%s &qj=%p Version=%s Status=%+v", qj.Namespace, qj.Name, qj, qj.ResourceVersion, qj.Status)
 
-	// Requeue the item to be processed again in 30 seconds.
-	// TODO: tune the frequency of reprocessing an AW
-	hasCompletionStatus := false
-	for _, genericItem := range qj.Spec.AggrResources.GenericItems {
-		if len(genericItem.CompletionStatus) > 0 {
-			hasCompletionStatus = true
-		}
-	}
-	// When an AW enters a system with completionstatus keep checking the AW until completed
-	// updatequeuejobs now runs as a part of informer machinery. optimization here is to not use etcd to pullout submitted AWs and operate
-	// on stale AWs. This has potential to improve performance at scale.
-	if hasCompletionStatus {
-		requeueIntervalForCompletionStatus := 5 * time.Second
-		key, err := cache.MetaNamespaceKeyFunc(qj)
-		if err != nil {
-			klog.Warningf("[Informer-addQJ] Error getting AW %s/%s from cache cannot determine completion status", qj.Namespace, qj.Name)
-			// TODO: should we return from this loop?
-		}
-		go func() {
-			for {
-				time.Sleep(requeueIntervalForCompletionStatus)
-				latestObj, exists, err := cc.appwrapperInformer.Informer().GetStore().GetByKey(key)
-				if err != nil || !exists {
-					klog.Warningf("[Informer-addQJ] Recent copy of AW %s/%s not found in cache,stopping check for completion status", qj.Namespace, qj.Name)
-					break
-				} else {
-					var latestAw *arbv1.AppWrapper
-					if latestObj != nil {
-						latestAw = latestObj.(*arbv1.AppWrapper)
-					} else {
-						latestAw = qj
-					}
-					if latestAw.Status.State != arbv1.AppWrapperStateActive && latestAw.Status.State != arbv1.AppWrapperStateEnqueued && latestAw.Status.State != arbv1.AppWrapperStateRunningHoldCompletion {
-						klog.V(2).Infof("[Informer-addQJ] Stopping requeue for AW %s/%s with status %s", latestAw.Namespace, latestAw.Name, latestAw.Status.State)
-						AwinEtcd, err := cc.arbclients.WorkloadV1beta1().AppWrappers(latestAw.Namespace).Get(context.Background(), latestAw.Name, metav1.GetOptions{})
-						if apierrors.IsNotFound(err) {
-							klog.V(2).Infof("[Informer-addQJ] Stopped requeueing of AW %s due to error %v\n", latestAw.Name, err)
-							break
-						} else if AwinEtcd.Status.State == latestAw.Status.State && err != nil {
-							break // Exit the loop
-						}
-					}
-					// Enqueue the latest copy of the AW.
-					if (qj.Status.State != arbv1.AppWrapperStateCompleted && qj.Status.State != arbv1.AppWrapperStateFailed) && hasCompletionStatus {
-						cc.UpdateQueueJobs(latestAw)
-						klog.V(2).Infof("[Informer-addQJ] requeing AW %s/%s to determine completion status for AW", qj.Namespace, qj.Name)
-					}
-
-				}
-
-			}
-		}()
+	// The completion, failure and readiness of the generic items are evaluated when the items change. Evaluate them
+	// now as well, the items may have changed while the controller was not running.
+	if needsItemEvaluation(qj) {
+		cc.enqueueItemEvaluation(qj.Namespace, qj.Name)
 	}
 
 	if qj.Spec.SchedSpec.MinAvailable > 0 {
@@ -1657,10 +2087,34 @@ func (cc *XController) updateQueueJob(oldObj, newObj interface{}) {
 		return
 	}
 
+	// A dispatched AppWrapper terminated, give AppWrappers held by concurrency limits another chance
+	if isCountedForConcurrency(oldQJ) && !isCountedForConcurrency(newQJ) {
+		cc.releaseConcurrencyWaiters()
+	}
+
+	// Evaluate the items of a dispatched AppWrapper or of a new stage, they may be complete or ready already
+	if (oldQJ.Status.State != newQJ.Status.State || oldQJ.Status.CurrentStage != newQJ.Status.CurrentStage) && needsItemEvaluation(newQJ) {
+		cc.enqueueItemEvaluation(newQJ.Namespace, newQJ.Name)
+	}
+
+	// Queue position updates of queued AppWrappers do not require any work. The AppWrappers that are not in the
+	// queue, such as the one being scheduled, are enqueued as for any other update.
+	if isQueuePositionUpdate(oldQJ, newQJ) && cc.qjqueue.IfExist(newQJ) {
+		klog.V(10).Infof("[Informer-updateQJ] '%s/%s' queue position update ignored Version=%s", newQJ.Namespace, newQJ.Name, newQJ.ResourceVersion)
+		return
+	}
+
 	if equality.Semantic.DeepEqual(newQJ.Status, oldQJ.Status) {
 		klog.V(6).Infof("[Informer-updateQJ] No change to status field of AppWrapper: '%s/%s', oldAW=%+v, newAW=%+v.", newQJ.Namespace, newQJ.Name, oldQJ.Status, newQJ.Status)
 	}
 
+	// Restarted AWs wait for the backoff of their restart policy before getting added to enqueue.
+	if delay := getRestartDelay(newQJ, time.Now()); delay > 0 {
+		klog.V(6).Infof("[Informer-updateQJ] '%s/%s' restarting, delaying enqueue by %.6f seconds Version=%s", newQJ.Namespace, newQJ.Name, delay.Seconds(), newQJ.ResourceVersion)
+		cc.enqueueRestartAfter(newQJ, delay)
+		return
+	}
+
 	klog.V(6).Infof("[Informer-updateQJ] '%s/%s' *Delay=%.6f seconds normal enqueue Version=%s Status=%v", newQJ.Namespace, newQJ.Name, time.Now().Sub(newQJ.Status.ControllerFirstTimestamp.Time).Seconds(), newQJ.ResourceVersion, newQJ.Status)
 	notBackedoff := true
 	for _, cond := range newQJ.Status.Conditions {
@@ -1713,6 +2167,14 @@ func (cc *XController) deleteQueueJob(obj interface{}) {
 	}
 	cc.qjqueue.Delete(qj)
 	cc.eventQueue.Delete(qj)
+	cc.takeEnqueueTime(qj)
+	queueJobKey, _ := GetQueueJobKey(qj)
+	cc.concurrencyMutex.Lock()
+	delete(cc.concurrencyWaiters, queueJobKey)
+	cc.concurrencyMutex.Unlock()
+	if isCountedForConcurrency(qj) {
+		cc.releaseConcurrencyWaiters()
+	}
 }
 
 func (cc *XController) enqueue(obj interface{}) error {
@@ -1725,6 +2187,7 @@ func (cc *XController) enqueue(obj interface{}) error {
 	if err != nil {
 		klog.Errorf("[enqueue] Fail to enqueue %s/%s to eventQueue, ignore.  *Delay=%.6f seconds Version=%s Status=%+v err=%#v", qj.Namespace, qj.Name, time.Now().Sub(qj.Status.ControllerFirstTimestamp.Time).Seconds(), qj.ResourceVersion, qj.Status, err)
 	} else {
+		cc.recordEnqueueTime(qj)
 		klog.V(10).Infof("[enqueue] %s/%s *Delay=%.6f seconds eventQueue.Add_byEnqueue Version=%s Status=%+v", qj.Namespace, qj.Name, time.Now().Sub(qj.Status.ControllerFirstTimestamp.Time).Seconds(), qj.ResourceVersion, qj.Status)
 	}
 	return err
@@ -1737,6 +2200,9 @@ func (cc *XController) enqueueIfNotPresent(obj interface{}) error {
 	}
 
 	err := cc.eventQueue.AddIfNotPresent(aw) // add to FIFO queue if not in, update object & keep position if already in FIFO queue
+	if err == nil {
+		cc.recordEnqueueTime(aw)
+	}
 	return err
 }
 
@@ -1787,16 +2253,7 @@ func (cc *XController) updateQueueJobStatus(ctx context.Context, queueJobFromAge
 		}
 		return err
 	}
-	if len(queueJobFromAgent.Status.State) == 0 || queueJobInEtcd.Status.State == queueJobFromAgent.Status.State {
-		return nil
-	}
-	new_flag := queueJobFromAgent.Status.State
-	queueJobInEtcd.Status.State = new_flag
-	_, err = cc.arbclients.WorkloadV1beta1().AppWrappers(queueJobInEtcd.Namespace).Update(ctx, queueJobInEtcd, metav1.UpdateOptions{})
-	if err != nil {
-		return err
-	}
-	return nil
+	return cc.syncAgentStatus(ctx, queueJobInEtcd, queueJobFromAgent)
 }
 
 func (cc *XController) worker() {
@@ -1816,6 +2273,7 @@ func (cc *XController) worker() {
 			return nil
 		}
 		klog.V(10).Infof("[worker] '%s/%s' *Delay=%.6f seconds eventQueue.Pop_begin &newQJ=%p Version=%s Status=%+v", queuejob.Namespace, queuejob.Name, time.Now().Sub(queuejob.Status.ControllerFirstTimestamp.Time).Seconds(), queuejob, queuejob.ResourceVersion, queuejob.Status)
+		enqueuedAt, _ := cc.takeEnqueueTime(queuejob)
 
 		if queuejob == nil {
 			if acc, err := meta.Accessor(obj); err != nil {
@@ -1827,7 +2285,7 @@ func (cc *XController) worker() {
 
 		// asmalvan - starts
 		// TODO: Should this be part of ScheduleNext() method?
-		if queuejob.Status.State == arbv1.AppWrapperStateCompleted {
+		if queuejob.Status.State == arbv1.AppWrapperStateCompleted || queuejob.Status.State == arbv1.AppWrapperStateFailed {
 			return nil
 		}
 
@@ -1873,7 +2331,8 @@ func (cc *XController) worker() {
 		// compares it with available unallocated cluster resources, performs quota check
 		// if everything passes then CanRun is set to true and AW is ready for dispatch
 		if !queuejob.Status.CanRun && (queuejob.Status.State != arbv1.AppWrapperStateActive) {
-			cc.ScheduleNext(queuejob)
+			ctx, span := cc.startDispatchAttempt(ctx, queuejob, enqueuedAt)
+			cc.ScheduleNext(ctx, queuejob)
 			// When an AW passes ScheduleNext gate then we want to progress AW to Running to begin with
 			// Sync queuejob will not unwrap an AW to spawn genericItems
 			if queuejob.Status.CanRun {
@@ -1888,10 +2347,13 @@ func (cc *XController) worker() {
 				// 	return err
 				// }
 				if err := cc.syncQueueJob(ctx, queuejob); err != nil {
+					tracing.End(span, err)
 					// If any error, requeue it.
 					return err
 				}
 			}
+			span.SetAttributes(attribute.Bool("dispatched", queuejob.Status.CanRun))
+			span.End()
 		}
 		// asmalvan- ends
 
@@ -1911,6 +2373,8 @@ func (cc *XController) worker() {
 }
 
 func (cc *XController) syncQueueJob(ctx context.Context, qj *arbv1.AppWrapper) error {
+	ctx, span := tracing.Start(ctx, "syncQueueJob")
+	defer span.End()
 	cacheAWJob, err := cc.getAppWrapper(qj.Namespace, qj.Name, "[syncQueueJob] get fresh appwrapper ")
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -1984,6 +2448,8 @@ func (cc *XController) syncQueueJob(ctx context.Context, qj *arbv1.AppWrapper) e
 // pods according to what is specified in the job.Spec.
 // Does NOT modify <activePods>.
 func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper, podPhaseChanges bool) error {
+	ctx, span := tracing.Start(ctx, "manageQueueJob")
+	defer span.End()
 
 	if !cc.isDispatcher { // Agent Mode
 		// Job is Complete only update pods if needed.
@@ -2021,13 +2487,25 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 			qj.Status.State = arbv1.AppWrapperStateActive
 			klog.V(4).Infof("[manageQueueJob] App wrapper '%s/%s' BeforeDispatchingToEtcd Version=%s Status=%+v", qj.Namespace, qj.Name, qj.ResourceVersion, qj.Status)
 			dispatched := true
+			restarted := false
 			dispatchFailureReason := "ItemCreationFailure."
 			dispatchFailureMessage := ""
+			// only the items of the first stage are created, later stages are created by UpdateQueueJobStages
+			firstStage := getFirstStage(qj)
 			if dispatched {
 				// Handle generic resources
-				for _, ar := range qj.Spec.AggrResources.GenericItems {
+				for i, ar := range qj.Spec.AggrResources.GenericItems {
+					if ar.Stage != firstStage {
+						continue
+					}
 					klog.V(10).Infof("[manageQueueJob] before dispatch Generic.SyncQueueJob %s/%s Version=%sStatus.CanRun=%t, Status.State=%s", qj.Namespace, qj.Name, qj.ResourceVersion, qj.Status.CanRun, qj.Status.State)
-					_, err00 := cc.genericresources.SyncQueueJob(qj, &ar)
+					_, itemSpan := tracing.Start(ctx, "SyncQueueJob", attribute.Int("item", i))
+					itemStatus, err00 := cc.genericresources.SyncQueueJob(qj, &ar)
+					tracing.End(itemSpan, err00)
+					if itemStatus != nil {
+						itemStatus.Index = i
+						setItemStatus(qj, *itemStatus)
+					}
 					if err00 != nil {
 						if apierrors.IsInvalid(err00) {
 							klog.Warningf("[manageQueueJob] Invalid generic item sent for dispatching by app wrapper='%s/%s' err=%v", qj.Namespace, qj.Name, err00)
@@ -2035,6 +2513,7 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 							klog.Errorf("[manageQueueJob] Error dispatching generic item for app wrapper='%s/%s' type=%v err=%v", qj.Namespace, qj.Name, err00)
 						}
 						dispatchFailureMessage = fmt.Sprintf("%s/%s creation failure: %+v", qj.Namespace, qj.Name, err00)
+						cc.eventRecorder.Eventf(qj, v1.EventTypeWarning, EventReasonItemCreationFailed, "Failed to create an item: %v", err00)
 						dispatched = false
 					}
 				}
@@ -2042,6 +2521,16 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 
 			if dispatched { // set AppWrapperCondRunning if all resources are successfully dispatched
 				qj.Status.QueueJobState = arbv1.AppWrapperCondDispatched
+				if qj.Status.ControllerFirstDispatchTimestamp.IsZero() {
+					qj.Status.ControllerFirstDispatchTimestamp = metav1.NowMicro()
+					if !qj.Status.ControllerFirstTimestamp.IsZero() {
+						metrics.DispatchLatency.Observe(qj.Status.ControllerFirstDispatchTimestamp.Sub(qj.Status.ControllerFirstTimestamp.Time).Seconds())
+					}
+				}
+				qj.Status.CurrentStage = firstStage
+				if _, hasNextStage := getNextStage(qj, firstStage); hasNextStage {
+					cc.addOrUpdateCondition(qj, arbv1.AppWrapperCondDispatched, v1.ConditionTrue, "StageDispatched", getStageDispatchedMessage(firstStage))
+				}
 				index := getIndexOfMatchedCondition(qj, arbv1.AppWrapperCondDispatched, "AppWrapperRunnable")
 				if index < 0 {
 					cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondDispatched, v1.ConditionTrue, "AppWrapperRunnable", "")
@@ -2059,10 +2548,6 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 				qj.Status.State = arbv1.AppWrapperStateFailed
 				qj.Status.QueueJobState = arbv1.AppWrapperCondFailed
 				qj.Status.CanRun = false
-				if !isLastConditionDuplicate(qj, arbv1.AppWrapperCondFailed, v1.ConditionTrue, dispatchFailureReason, dispatchFailureMessage) {
-					cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondFailed, v1.ConditionTrue, dispatchFailureReason, dispatchFailureMessage)
-					qj.Status.Conditions = append(qj.Status.Conditions, cond)
-				}
 				// clean up app wrapper resources including quota
 				if err00 := cc.Cleanup(ctx, qj); err00 != nil {
 					klog.Errorf("Failed to delete resources associated with app wrapper: '%s/%s', err %v", qj.Namespace, qj.Name, err00)
@@ -2070,6 +2555,11 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 					return err00
 				}
 				cc.qjqueue.Delete(qj)
+				restarted = cc.restartFailedAppWrapper(qj, dispatchFailureReason, dispatchFailureMessage)
+				if !restarted && !isLastConditionDuplicate(qj, arbv1.AppWrapperCondFailed, v1.ConditionTrue, dispatchFailureReason, dispatchFailureMessage) {
+					cond := GenerateAppWrapperCondition(arbv1.AppWrapperCondFailed, v1.ConditionTrue, dispatchFailureReason, dispatchFailureMessage)
+					qj.Status.Conditions = append(qj.Status.Conditions, cond)
+				}
 			}
 
 			qj.Status.FilterIgnore = true // update State & QueueJobState after dispatch
@@ -2078,6 +2568,11 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 				klog.Errorf("[manageQueueJob] Error updating status 'afterEtcdDispatching' for  AppWrapper: '%s/%s',Status=%+v, err=%+v.", qj.Namespace, qj.Name, qj.Status, err)
 				return err
 			}
+			if dispatched {
+				cc.eventRecorder.Event(qj, v1.EventTypeNormal, EventReasonDispatched, "The items of the AppWrapper were created.")
+			} else {
+				cc.recordFailureEvent(qj, restarted, dispatchFailureMessage)
+			}
 			return nil
 		} else if qj.Status.CanRun && qj.Status.State == arbv1.AppWrapperStateActive {
 			klog.Infof("[manageQueueJob] Getting completion status for app wrapper '%s/%s' Version=%s Status.CanRun=%t Status.State=%s, pod counts [Pending: %d, Running: %d, Succeded: %d, Failed %d]", qj.Namespace, qj.Name, qj.ResourceVersion,
@@ -2130,11 +2625,16 @@ func (cc *XController) manageQueueJob(ctx context.Context, qj *arbv1.AppWrapper,
 				klog.V(10).Infof("[manageQueueJob] [Dispatcher]  '%s/%s', %s: WorkerBeforeDispatch", qj.Namespace, qj.Name, time.Now().Sub(qj.CreationTimestamp.Time))
 			}
 
-			queuejobKey, _ := GetQueueJobKey(qj)
-			if agentId, ok := cc.dispatchMap[queuejobKey]; ok {
+			cc.agentMutex.RLock()
+			agentId, ok := cc.dispatchedAgent(qj)
+			agent, agentOk := cc.agentMap[agentId]
+			cc.agentMutex.RUnlock()
+			if ok && agentOk {
 				klog.V(10).Infof("[manageQueueJob] [Dispatcher]  Dispatched AppWrapper %s/%s to Agent ID: %s.", qj.Namespace, qj.Name, agentId)
-				cc.agentMap[agentId].CreateJob(ctx, qj)
+				agent.CreateJob(ctx, qj)
 				qj.Status.IsDispatched = true
+			} else if ok {
+				klog.Errorf("[manageQueueJob] [Dispatcher]  Agent %s of AppWrapper %s/%s is no longer registered.", agentId, qj.Namespace, qj.Name)
 			} else {
 				klog.Errorf("[manageQueueJob] [Dispatcher]  AppWrapper %s/%s not found in dispatcher mapping.", qj.Namespace, qj.Name)
 			}
@@ -2158,8 +2658,28 @@ func (cc *XController) Cleanup(ctx context.Context, appwrapper *arbv1.AppWrapper
 	klog.V(3).Infof("[Cleanup] begin AppWrapper '%s/%s' Version=%s", appwrapper.Namespace, appwrapper.Name, appwrapper.ResourceVersion)
 	var err *multierror.Error
 	if !cc.isDispatcher {
+		// delete the objects recorded in the status directly, the templates may have changed since they were created
+		cleanedUp := map[int]bool{}
+		var remainingItems []arbv1.AppWrapperItemStatus
+		for _, item := range appwrapper.Status.Items {
+			if err00 := cc.genericresources.CleanupItem(&item); err00 != nil && !CanIgnoreAPIError(err00) {
+				klog.Errorf("[Cleanup] Error deleting item %s, GVK=%s.%s.%s from app wrapper='%s/%s' err=%v.",
+					item.Name, item.Group, item.Version, item.Kind, appwrapper.Namespace, appwrapper.Name, err00)
+				err = multierror.Append(err, err00)
+				remainingItems = append(remainingItems, item)
+				continue
+			}
+			klog.V(3).Infof("[Cleanup] Deleted item '%s', GVK=%s.%s.%s from app wrapper='%s/%s'",
+				item.Name, item.Group, item.Version, item.Kind, appwrapper.Namespace, appwrapper.Name)
+			cleanedUp[item.Index] = true
+		}
+		appwrapper.Status.Items = remainingItems
+		appwrapper.Status.BlockingItem = getBlockingItem(remainingItems)
 		if appwrapper.Spec.AggrResources.GenericItems != nil {
-			for _, ar := range appwrapper.Spec.AggrResources.GenericItems {
+			for i, ar := range appwrapper.Spec.AggrResources.GenericItems {
+				if cleanedUp[i] {
+					continue
+				}
 				genericResourceName, gvk, err00 := cc.genericresources.Cleanup(appwrapper, &ar)
 				if err00 != nil && !CanIgnoreAPIError(err00) && !IsJsonSyntaxError(err00) {
 					klog.Errorf("[Cleanup] Error deleting generic item %s, from app wrapper='%s/%s' err=%v.",
@@ -2178,12 +2698,20 @@ func (cc *XController) Cleanup(ctx context.Context, appwrapper *arbv1.AppWrapper
 		}
 	} else {
 		if appwrapper.Status.IsDispatched {
-			queuejobKey, _ := GetQueueJobKey(appwrapper)
-			if obj, ok := cc.dispatchMap[queuejobKey]; ok {
-				cc.agentMap[obj].DeleteJob(ctx, appwrapper)
+			cc.agentMutex.RLock()
+			agentId, ok := cc.dispatchedAgent(appwrapper)
+			agent, agentOk := cc.agentMap[agentId]
+			cc.agentMutex.RUnlock()
+			if ok && agentOk {
+				agent.DeleteJob(ctx, appwrapper)
 			}
 			appwrapper.Status.IsDispatched = false
 		}
+		queuejobKey, _ := GetQueueJobKey(appwrapper)
+		cc.agentMutex.Lock()
+		delete(cc.dispatchMap, queuejobKey)
+		cc.agentMutex.Unlock()
+		appwrapper.Status.DispatchedAgent = ""
 	}
 
 	// Release quota if quota is enabled and quota manager instance exists
@@ -2255,11 +2783,23 @@ func (cc *XController) IsActiveAppWrapper(name, namespace string) bool {
 			strings.Compare(cc.schedulingAW.Name, name) != 0)
 }
 func (qjm *XController) schedulingAWAtomicSet(qj *arbv1.AppWrapper) {
+	key := ""
+	if qj != nil {
+		key, _ = GetQueueJobKey(qj)
+	}
 	qjm.schedulingMutex.Lock()
 	qjm.schedulingAW = qj
+	qjm.schedulingKey = key
 	qjm.schedulingMutex.Unlock()
 }
 
+// getSchedulingKey returns the namespace/name key of the AppWrapper being scheduled, or an empty string
+func (qjm *XController) getSchedulingKey() string {
+	qjm.schedulingMutex.RLock()
+	defer qjm.schedulingMutex.RUnlock()
+	return qjm.schedulingKey
+}
+
 func IsJsonSyntaxError(err error) bool {
 	var tt *jsons.SyntaxError
 	if err == nil {
@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// enqueueRestartAfter queues a restarting AppWrapper once the backoff of its restart policy has elapsed. The
+// updates received during the backoff are coalesced, the AppWrapper is read again when the backoff has elapsed.
+func (cc *XController) enqueueRestartAfter(aw *arbv1.AppWrapper, delay time.Duration) {
+	if cc.restartQueue == nil {
+		return
+	}
+	cc.restartQueue.AddAfter(aw.Namespace+"/"+aw.Name, delay)
+}
+
+func (cc *XController) restartWorker() {
+	for cc.processNextRestart() {
+	}
+}
+
+func (cc *XController) processNextRestart() bool {
+	key, quit := cc.restartQueue.Get()
+	if quit {
+		return false
+	}
+	defer cc.restartQueue.Done(key)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key.(string))
+	if err != nil {
+		klog.Errorf("[processNextRestart] Invalid AppWrapper key %s, err=%v", key, err)
+		return true
+	}
+	aw, err := cc.appWrapperLister.AppWrappers(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Errorf("[processNextRestart] Failed to get AppWrapper %s, err=%v", key, err)
+		}
+		return true
+	}
+	// the AppWrapper may have been restarted again during the backoff
+	if delay := getRestartDelay(aw, time.Now()); delay > 0 {
+		cc.restartQueue.AddAfter(key, delay)
+		return true
+	}
+	klog.V(6).Infof("[processNextRestart] '%s/%s' restart backoff elapsed, enqueue Version=%s", aw.Namespace, aw.Name, aw.ResourceVersion)
+	// the copy in the lister cache must not be modified
+	cc.enqueue(aw.DeepCopy())
+	return true
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func restartingAppWrapper(name string, backoff int, restartedAt time.Time) *arbv1.AppWrapper {
+	return &arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       arbv1.AppWrapperSpec{RestartPolicy: arbv1.RestartPolicy{MaxRestarts: 3, BackoffInSeconds: backoff}},
+		Status: arbv1.AppWrapperStatus{
+			State:            arbv1.AppWrapperStateEnqueued,
+			QueueJobState:    arbv1.AppWrapperCondRestarting,
+			NumberOfRestarts: 1,
+			Conditions: []arbv1.AppWrapperCondition{
+				{Type: arbv1.AppWrapperCondRestarting, LastTransitionMicroTime: metav1.NewMicroTime(restartedAt)},
+			},
+		},
+	}
+}
+
+func TestEnqueueRestartAfter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	aw := restartingAppWrapper("aw", 10, time.Now())
+
+	// without a queue the restarts are ignored
+	cc := &XController{}
+	cc.enqueueRestartAfter(aw, time.Millisecond)
+
+	cc.restartQueue = workqueue.NewDelayingQueue()
+	defer cc.restartQueue.ShutDown()
+	// the updates of an AppWrapper received during its backoff are coalesced
+	cc.enqueueRestartAfter(aw, 10*time.Millisecond)
+	cc.enqueueRestartAfter(aw.DeepCopy(), 10*time.Millisecond)
+	cc.enqueueRestartAfter(aw.DeepCopy(), 20*time.Millisecond)
+	g.Expect(cc.restartQueue.Len()).To(gomega.Equal(0))
+	g.Eventually(cc.restartQueue.Len).Should(gomega.Equal(1))
+	g.Consistently(cc.restartQueue.Len, 50*time.Millisecond).Should(gomega.Equal(1))
+}
+
+func TestProcessNextRestart(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	elapsed := restartingAppWrapper("elapsed", 1, now.Add(-time.Minute))
+	waiting := restartingAppWrapper("waiting", 60, now)
+	cc := &XController{
+		appWrapperLister: newAppWrapperLister(g, elapsed, waiting),
+		eventQueue:       cache.NewFIFO(GetQueueJobKey),
+		restartQueue:     workqueue.NewDelayingQueue(),
+	}
+	defer cc.restartQueue.ShutDown()
+
+	// the latest copy of an AppWrapper whose backoff has elapsed is enqueued
+	cc.restartQueue.Add("default/elapsed")
+	g.Expect(cc.processNextRestart()).To(gomega.BeTrue())
+	g.Expect(cc.eventQueue.ListKeys()).To(gomega.ConsistOf("default/elapsed"))
+
+	// an AppWrapper restarted again during the backoff waits for its new backoff
+	cc.restartQueue.Add("default/waiting")
+	g.Expect(cc.processNextRestart()).To(gomega.BeTrue())
+	g.Expect(cc.eventQueue.ListKeys()).To(gomega.ConsistOf("default/elapsed"))
+	g.Expect(cc.restartQueue.Len()).To(gomega.Equal(0))
+
+	// deleted AppWrappers are ignored
+	cc.restartQueue.Add("default/deleted")
+	g.Expect(cc.processNextRestart()).To(gomega.BeTrue())
+	g.Expect(cc.eventQueue.ListKeys()).To(gomega.ConsistOf("default/elapsed"))
+}
+
+func TestRecordFailureEvent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	recorder := record.NewFakeRecorder(2)
+	cc := &XController{eventRecorder: recorder}
+	aw := restartingAppWrapper("aw", 0, time.Now())
+
+	cc.recordFailureEvent(aw, true, "One or more generic items reported a failure condition.")
+	g.Expect(<-recorder.Events).To(gomega.Equal("Warning Restarting Restart 1 of 3 after failure: One or more generic items reported a failure condition."))
+	cc.recordFailureEvent(aw, false, "One or more generic items reported a failure condition.")
+	g.Expect(<-recorder.Events).To(gomega.Equal("Warning Failed One or more generic items reported a failure condition."))
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+// schedulingDiagnosticsMinInterval is the minimum time between two changes of the scheduling diagnostics of an AppWrapper
+const schedulingDiagnosticsMinInterval = 10 * time.Second
+
+// resourceToResourceList converts resources to a ResourceList, nil resources are converted to a nil list
+func resourceToResourceList(r *clusterstateapi.Resource) v1.ResourceList {
+	if r == nil {
+		return nil
+	}
+	return v1.ResourceList{
+		v1.ResourceCPU:                  *resource.NewMilliQuantity(int64(r.MilliCPU), resource.DecimalSI),
+		v1.ResourceMemory:               *resource.NewQuantity(int64(r.Memory), resource.BinarySI),
+		clusterstateapi.GPUResourceName: *resource.NewQuantity(r.GPU, resource.DecimalSI),
+	}
+}
+
+// resourceShortfall returns the amount of each requested resource missing from the available resources
+func resourceShortfall(requested *clusterstateapi.Resource, available *clusterstateapi.Resource) v1.ResourceList {
+	if requested == nil || available == nil {
+		return nil
+	}
+	shortfall := v1.ResourceList{}
+	if requested.MilliCPU > available.MilliCPU {
+		shortfall[v1.ResourceCPU] = *resource.NewMilliQuantity(int64(requested.MilliCPU-available.MilliCPU), resource.DecimalSI)
+	}
+	if requested.Memory > available.Memory {
+		shortfall[v1.ResourceMemory] = *resource.NewQuantity(int64(requested.Memory-available.Memory), resource.BinarySI)
+	}
+	if requested.GPU > available.GPU {
+		shortfall[clusterstateapi.GPUResourceName] = *resource.NewQuantity(requested.GPU-available.GPU, resource.DecimalSI)
+	}
+	if len(shortfall) == 0 {
+		return nil
+	}
+	return shortfall
+}
+
+// newSchedulingDiagnostics explains a failed attempt to dispatch an AppWrapper. The available resources include the
+// resources of the proposed preemption victims, they are nil when the resources were not evaluated.
+func newSchedulingDiagnostics(reason string, requested *clusterstateapi.Resource, available *clusterstateapi.Resource,
+	quotaRejections []arbv1.QuotaRejection, proposedPreemptions []*arbv1.AppWrapper, queuedAhead int) *arbv1.SchedulingDiagnostics {
+	diagnostics := &arbv1.SchedulingDiagnostics{
+		Reason:          reason,
+		Requested:       resourceToResourceList(requested),
+		Available:       resourceToResourceList(available),
+		Shortfall:       resourceShortfall(requested, available),
+		QuotaRejections: quotaRejections,
+		QueuedAhead:     int32(queuedAhead),
+	}
+	for _, aw := range proposedPreemptions {
+		diagnostics.ProposedPreemptions = append(diagnostics.ProposedPreemptions, fmt.Sprintf("%s/%s", aw.Namespace, aw.Name))
+	}
+	return diagnostics
+}
+
+// setSchedulingDiagnostics records the diagnostics of the latest attempt to dispatch an AppWrapper. To limit the
+// status updates, the diagnostics are replaced only when they change and the previous ones are older than
+// schedulingDiagnosticsMinInterval. It returns whether the diagnostics were replaced.
+func setSchedulingDiagnostics(aw *arbv1.AppWrapper, diagnostics *arbv1.SchedulingDiagnostics, now time.Time) bool {
+	previous := aw.Status.SchedulingDiagnostics
+	if previous != nil {
+		candidate := diagnostics.DeepCopy()
+		candidate.LastUpdateTime = previous.LastUpdateTime
+		if equality.Semantic.DeepEqual(candidate, previous) {
+			return false
+		}
+		if now.Sub(previous.LastUpdateTime.Time) < schedulingDiagnosticsMinInterval {
+			return false
+		}
+	}
+	aw.Status.SchedulingDiagnostics = diagnostics.DeepCopy()
+	aw.Status.SchedulingDiagnostics.LastUpdateTime = metav1.NewTime(now)
+	return true
+}
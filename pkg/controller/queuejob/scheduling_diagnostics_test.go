@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+func TestResourceShortfall(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	requested := &clusterstateapi.Resource{MilliCPU: 4000, Memory: 1024, GPU: 2}
+
+	shortfall := resourceShortfall(requested, &clusterstateapi.Resource{MilliCPU: 1500, Memory: 2048, GPU: 1})
+	g.Expect(shortfall).To(gomega.HaveLen(2))
+	g.Expect(shortfall.Cpu().MilliValue()).To(gomega.Equal(int64(2500)))
+	gpu := shortfall[clusterstateapi.GPUResourceName]
+	g.Expect(gpu.Value()).To(gomega.Equal(int64(1)))
+
+	g.Expect(resourceShortfall(requested, &clusterstateapi.Resource{MilliCPU: 4000, Memory: 1024, GPU: 2})).To(gomega.BeNil())
+	g.Expect(resourceShortfall(requested, nil)).To(gomega.BeNil())
+}
+
+func TestSetSchedulingDiagnostics(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	aw := &arbv1.AppWrapper{}
+	diagnostics := func(reason string, cpu string) *arbv1.SchedulingDiagnostics {
+		return &arbv1.SchedulingDiagnostics{
+			Reason:    reason,
+			Requested: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+		}
+	}
+
+	g.Expect(setSchedulingDiagnostics(aw, diagnostics("quota", "2"), now)).To(gomega.BeTrue())
+	g.Expect(aw.Status.SchedulingDiagnostics.Reason).To(gomega.Equal("quota"))
+	g.Expect(aw.Status.SchedulingDiagnostics.LastUpdateTime.Time.Equal(now)).To(gomega.BeTrue())
+
+	// unchanged diagnostics are not updated
+	g.Expect(setSchedulingDiagnostics(aw, diagnostics("quota", "2000m"), now.Add(time.Minute))).To(gomega.BeFalse())
+
+	// changes are rate-limited
+	g.Expect(setSchedulingDiagnostics(aw, diagnostics("resources", "2"), now.Add(schedulingDiagnosticsMinInterval/2))).To(gomega.BeFalse())
+	g.Expect(aw.Status.SchedulingDiagnostics.Reason).To(gomega.Equal("quota"))
+
+	g.Expect(setSchedulingDiagnostics(aw, diagnostics("resources", "2"), now.Add(schedulingDiagnosticsMinInterval))).To(gomega.BeTrue())
+	g.Expect(aw.Status.SchedulingDiagnostics.Reason).To(gomega.Equal("resources"))
+}
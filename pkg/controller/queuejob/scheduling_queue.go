@@ -43,6 +43,8 @@ package queuejob
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
@@ -67,6 +69,9 @@ type SchedulingQueue interface {
 	IfExistActiveQ(QJ *qjobv1.AppWrapper) bool
 	IfExistUnschedulableQ(QJ *qjobv1.AppWrapper) bool
 	Length() int
+	UnschedulableLength() int
+	CountAhead(qj *qjobv1.AppWrapper) int
+	Keys() []string
 }
 
 // NewSchedulingQueue initializes a new scheduling queue. If pod priority is
@@ -126,6 +131,70 @@ func (p *PriorityQueue) Length() int {
 	return pqlength
 }
 
+// UnschedulableLength returns the number of AppWrappers in the unschedulable queue
+func (p *PriorityQueue) UnschedulableLength() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.unschedulableQ.pods)
+}
+
+// CountAhead returns the number of other AppWrappers in the queues with a higher priority than the given AppWrapper
+func (p *PriorityQueue) CountAhead(qj *qjobv1.AppWrapper) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	ahead := 0
+	isAhead := func(other *qjobv1.AppWrapper) bool {
+		return (other.Namespace != qj.Namespace || other.Name != qj.Name) && p.activeQ.data.lessFunc(other, qj)
+	}
+	for _, obj := range p.activeQ.List() {
+		if other, ok := obj.(*qjobv1.AppWrapper); ok && isAhead(other) {
+			ahead++
+		}
+	}
+	for _, other := range p.unschedulableQ.pods {
+		if isAhead(other) {
+			ahead++
+		}
+	}
+	return ahead
+}
+
+// Keys returns the namespace/name keys of the AppWrappers of both sub queues. The queued AppWrappers are mutated by the scheduling
+// worker, so readers get their state from the lister rather than from the queue.
+func (p *PriorityQueue) Keys() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	keys := make([]string, 0, p.activeQ.data.Len()+len(p.unschedulableQ.pods))
+	keys = append(keys, p.activeQ.ListKeys()...)
+	for key := range p.unschedulableQ.pods {
+		// The keys of the unschedulable queue are name_namespace, see GetXQJFullName
+		if i := strings.LastIndex(key, "_"); i >= 0 {
+			keys = append(keys, key[i+1:]+"/"+key[:i])
+		}
+	}
+	return keys
+}
+
+// SortBySchedulingOrder sorts AppWrappers in the order of the scheduling queue. AppWrappers with the same priority are
+// ordered by arrival time.
+func SortBySchedulingOrder(qjs []*qjobv1.AppWrapper) {
+	sort.Slice(qjs, func(i, j int) bool {
+		if HigherSystemPriorityQJ(qjs[i], qjs[j]) {
+			return true
+		}
+		if HigherSystemPriorityQJ(qjs[j], qjs[i]) {
+			return false
+		}
+		if !qjs[i].Status.ControllerFirstTimestamp.Equal(&qjs[j].Status.ControllerFirstTimestamp) {
+			return qjs[i].Status.ControllerFirstTimestamp.Before(&qjs[j].Status.ControllerFirstTimestamp)
+		}
+		if qjs[i].Namespace != qjs[j].Namespace {
+			return qjs[i].Namespace < qjs[j].Namespace
+		}
+		return qjs[i].Name < qjs[j].Name
+	})
+}
+
 func (p *PriorityQueue) IfExist(qj *qjobv1.AppWrapper) bool {
 	p.lock.Lock()
 	defer p.lock.Unlock()
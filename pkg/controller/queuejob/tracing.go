@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/tracing"
+)
+
+// recordEnqueueTime records the time an AppWrapper is added to the event queue, unless it is already waiting in it
+func (cc *XController) recordEnqueueTime(aw *arbv1.AppWrapper) {
+	key, err := GetQueueJobKey(aw)
+	if err != nil {
+		return
+	}
+	cc.enqueueTimesMutex.Lock()
+	defer cc.enqueueTimesMutex.Unlock()
+	if cc.enqueueTimes == nil {
+		cc.enqueueTimes = make(map[string]time.Time)
+	}
+	if _, found := cc.enqueueTimes[key]; !found {
+		cc.enqueueTimes[key] = time.Now()
+	}
+}
+
+// takeEnqueueTime returns and forgets the time an AppWrapper was added to the event queue
+func (cc *XController) takeEnqueueTime(aw *arbv1.AppWrapper) (time.Time, bool) {
+	key, err := GetQueueJobKey(aw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	cc.enqueueTimesMutex.Lock()
+	defer cc.enqueueTimesMutex.Unlock()
+	enqueuedAt, found := cc.enqueueTimes[key]
+	delete(cc.enqueueTimes, key)
+	return enqueuedAt, found
+}
+
+// startDispatchAttempt starts the root span of a dispatch attempt of an AppWrapper. When the time the AppWrapper was
+// added to the event queue is known, the span starts at that time and the wait in the queue is recorded as a child span.
+func (cc *XController) startDispatchAttempt(ctx context.Context, aw *arbv1.AppWrapper, enqueuedAt time.Time) (context.Context, trace.Span) {
+	attrs := append(tracing.AppWrapper(aw.Namespace, aw.Name),
+		attribute.String("state", string(aw.Status.State)),
+		attribute.Float64("systemPriority", aw.Status.SystemPriority))
+	opts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithAttributes(attrs...)}
+	if enqueuedAt.IsZero() {
+		return tracing.StartWith(ctx, "AppWrapper dispatch", opts...)
+	}
+	ctx, span := tracing.StartWith(ctx, "AppWrapper dispatch", append(opts, trace.WithTimestamp(enqueuedAt))...)
+	_, queueSpan := tracing.StartWith(ctx, "eventQueue", trace.WithTimestamp(enqueuedAt))
+	queueSpan.End()
+	return ctx, span
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func TestEnqueueTimes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	cc := &XController{}
+	aw := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "aw", Namespace: "default"}}
+
+	_, found := cc.takeEnqueueTime(aw)
+	g.Expect(found).To(gomega.BeFalse())
+
+	cc.recordEnqueueTime(aw)
+	first := cc.enqueueTimes["default/aw"]
+	time.Sleep(time.Millisecond)
+	cc.recordEnqueueTime(aw) // the AppWrapper is still waiting in the queue, the first time is kept
+	enqueuedAt, found := cc.takeEnqueueTime(aw)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(enqueuedAt).To(gomega.Equal(first))
+
+	_, found = cc.takeEnqueueTime(aw)
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestStartDispatchAttempt(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	recorder := tracetest.NewSpanRecorder()
+	defaultProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(defaultProvider)
+
+	cc := &XController{}
+	aw := &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "aw", Namespace: "default"}}
+	enqueuedAt := time.Now().Add(-time.Second)
+	_, span := cc.startDispatchAttempt(context.Background(), aw, enqueuedAt)
+	span.End()
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(gomega.HaveLen(2))
+	queueSpan, rootSpan := spans[0], spans[1]
+	g.Expect(rootSpan.Name()).To(gomega.Equal("AppWrapper dispatch"))
+	g.Expect(rootSpan.Parent().IsValid()).To(gomega.BeFalse())
+	g.Expect(rootSpan.StartTime()).To(gomega.Equal(enqueuedAt))
+	g.Expect(queueSpan.Name()).To(gomega.Equal("eventQueue"))
+	g.Expect(queueSpan.Parent().SpanID()).To(gomega.Equal(rootSpan.SpanContext().SpanID()))
+	g.Expect(queueSpan.StartTime()).To(gomega.Equal(enqueuedAt))
+
+	// without an enqueue time, the dispatch attempt starts now and has no event queue span
+	recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	_, span = cc.startDispatchAttempt(context.Background(), aw, time.Time{})
+	span.End()
+	g.Expect(recorder.Ended()).To(gomega.HaveLen(1))
+}
@@ -17,13 +17,16 @@ limitations under the License.
 package queuejob
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
 	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
 )
 
@@ -82,6 +85,175 @@ func getIndexOfMatchedCondition(aw *arbv1.AppWrapper, condType arbv1.AppWrapperC
 	return index
 }
 
+// canRestart returns true if the restart policy of the AppWrapper allows it to be restarted after a failure
+func canRestart(aw *arbv1.AppWrapper) bool {
+	return aw.Status.NumberOfRestarts < aw.Spec.RestartPolicy.MaxRestarts
+}
+
+// getRestartDelay returns how long a restarting AppWrapper has to wait until its restart backoff has elapsed
+func getRestartDelay(aw *arbv1.AppWrapper, now time.Time) time.Duration {
+	if aw.Status.QueueJobState != arbv1.AppWrapperCondRestarting || aw.Spec.RestartPolicy.BackoffInSeconds <= 0 {
+		return 0
+	}
+	for i := len(aw.Status.Conditions) - 1; i >= 0; i-- {
+		cond := aw.Status.Conditions[i]
+		if cond.Type == arbv1.AppWrapperCondRestarting {
+			restartTime := cond.LastTransitionMicroTime.Add(time.Duration(aw.Spec.RestartPolicy.BackoffInSeconds) * time.Second)
+			if delay := restartTime.Sub(now); delay > 0 {
+				return delay
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// getFirstStage returns the lowest stage of the generic items of the AppWrapper
+func getFirstStage(aw *arbv1.AppWrapper) int {
+	firstStage := 0
+	for i, item := range aw.Spec.AggrResources.GenericItems {
+		if i == 0 || item.Stage < firstStage {
+			firstStage = item.Stage
+		}
+	}
+	return firstStage
+}
+
+// getNextStage returns the lowest stage of the generic items of the AppWrapper that comes after the given stage.
+// Returns false if there is no later stage.
+func getNextStage(aw *arbv1.AppWrapper, stage int) (int, bool) {
+	nextStage := 0
+	found := false
+	for _, item := range aw.Spec.AggrResources.GenericItems {
+		if item.Stage > stage && (!found || item.Stage < nextStage) {
+			nextStage = item.Stage
+			found = true
+		}
+	}
+	return nextStage, found
+}
+
+// getStageDispatchedMessage returns the message of the condition recording the dispatch of the items of a stage
+func getStageDispatchedMessage(stage int) string {
+	return fmt.Sprintf("Dispatched the items of stage %d.", stage)
+}
+
+// getStageDispatchTime returns when the items of a stage of the AppWrapper were last dispatched. The dispatch time
+// of the AppWrapper is returned if the dispatch of the stage was not recorded since the AppWrapper was dispatched.
+func getStageDispatchTime(aw *arbv1.AppWrapper, stage int) time.Time {
+	dispatchTime := aw.Status.ControllerFirstDispatchTimestamp.Time
+	message := getStageDispatchedMessage(stage)
+	for i := len(aw.Status.Conditions) - 1; i >= 0; i-- {
+		cond := aw.Status.Conditions[i]
+		if cond.Type == arbv1.AppWrapperCondDispatched && cond.Reason == "StageDispatched" && cond.Message == message {
+			// the condition may be left from an attempt before a restart
+			if cond.LastTransitionMicroTime.Time.After(dispatchTime) {
+				return cond.LastTransitionMicroTime.Time
+			}
+			break
+		}
+	}
+	return dispatchTime
+}
+
+// setItemStatus records the reference to the object created for a generic item, replacing an earlier reference for the same item
+func setItemStatus(aw *arbv1.AppWrapper, itemStatus arbv1.AppWrapperItemStatus) {
+	for i := range aw.Status.Items {
+		if aw.Status.Items[i].Index == itemStatus.Index {
+			aw.Status.Items[i] = itemStatus
+			aw.Status.BlockingItem = getBlockingItem(aw.Status.Items)
+			return
+		}
+	}
+	aw.Status.Items = append(aw.Status.Items, itemStatus)
+	aw.Status.BlockingItem = getBlockingItem(aw.Status.Items)
+}
+
+// setItemState updates the state of the object created for the generic item with the given index.
+// Returns true if the state changed.
+func setItemState(aw *arbv1.AppWrapper, index int, state arbv1.AppWrapperItemState) bool {
+	for i := range aw.Status.Items {
+		if aw.Status.Items[i].Index == index {
+			if aw.Status.Items[i].State == state {
+				return false
+			}
+			aw.Status.Items[i].State = state
+			aw.Status.BlockingItem = getBlockingItem(aw.Status.Items)
+			return true
+		}
+	}
+	return false
+}
+
+// getBlockingItem returns the kind and name of the item holding the AppWrapper back: the failed item with the lowest
+// index, or else the item with the lowest index that is neither ready nor completed. Returns "" if no item is blocking.
+func getBlockingItem(items []arbv1.AppWrapperItemStatus) string {
+	var failed, pending *arbv1.AppWrapperItemStatus
+	for i := range items {
+		item := &items[i]
+		switch item.State {
+		case arbv1.AppWrapperItemStateFailed:
+			if failed == nil || item.Index < failed.Index {
+				failed = item
+			}
+		case arbv1.AppWrapperItemStateReady, arbv1.AppWrapperItemStateCompleted:
+		default:
+			if pending == nil || item.Index < pending.Index {
+				pending = item
+			}
+		}
+	}
+	blocking := failed
+	if blocking == nil {
+		blocking = pending
+	}
+	if blocking == nil {
+		return ""
+	}
+	return blocking.Kind + "/" + blocking.Name
+}
+
+// isCountedForConcurrency returns true if the AppWrapper counts towards the concurrency limits,
+// i.e., it has been dispatched and has not terminated yet.
+func isCountedForConcurrency(aw *arbv1.AppWrapper) bool {
+	return aw.Status.CanRun && aw.Status.State != arbv1.AppWrapperStateCompleted && aw.Status.State != arbv1.AppWrapperStateFailed
+}
+
+// getConcurrencyLimitMessage returns a message describing the first concurrency limit that dispatching
+// the AppWrapper would exceed, or an empty string if it is within all limits.
+// The counts of the namespace and of the user of the AppWrapper do not include the AppWrapper, which has the given pods.
+func getConcurrencyLimitMessage(limits *config.ConcurrencyLimits, aw *arbv1.AppWrapper, namespaceCount concurrencyCount,
+	userCount concurrencyCount, pods int32) string {
+	if limits == nil {
+		return ""
+	}
+	user := ""
+	if len(limits.UserAnnotation) > 0 {
+		user = aw.Annotations[limits.UserAnnotation]
+	}
+	if exceedsLimit(limits.MaxAppWrappersPerNamespace, namespaceCount.appwrappers+1) {
+		return fmt.Sprintf("Namespace %s has reached its limit of %d dispatched AppWrappers.", aw.Namespace, *limits.MaxAppWrappersPerNamespace)
+	}
+	if exceedsLimit(limits.MaxPodsPerNamespace, namespaceCount.pods+pods) {
+		return fmt.Sprintf("Namespace %s has reached its limit of %d pods of dispatched AppWrappers.", aw.Namespace, *limits.MaxPodsPerNamespace)
+	}
+	if len(user) == 0 {
+		return ""
+	}
+	if exceedsLimit(limits.MaxAppWrappersPerUser, userCount.appwrappers+1) {
+		return fmt.Sprintf("User %s has reached its limit of %d dispatched AppWrappers.", user, *limits.MaxAppWrappersPerUser)
+	}
+	if exceedsLimit(limits.MaxPodsPerUser, userCount.pods+pods) {
+		return fmt.Sprintf("User %s has reached its limit of %d pods of dispatched AppWrappers.", user, *limits.MaxPodsPerUser)
+	}
+	return ""
+}
+
+// exceedsLimit returns true if the limit is set and the value is above it.
+func exceedsLimit(limit *int32, value int32) bool {
+	return limit != nil && *limit > 0 && value > *limit
+}
+
 // PendingPodsFailedSchd checks if pods pending have failed scheduling
 func PendingPodsFailedSchd(pods []v1.Pod) map[string][]v1.PodCondition {
 	var podCondition = make(map[string][]v1.PodCondition)
@@ -2,12 +2,15 @@ package queuejob
 
 import (
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	"k8s.io/utils/pointer"
 )
 
 
@@ -234,3 +237,259 @@ func TestGetIndexOfMatchedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestAddOrUpdateCondition(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cc := &XController{}
+	aw := &arbv1.AppWrapper{}
+	cc.addOrUpdateCondition(aw, arbv1.AppWrapperCondDispatched, corev1.ConditionTrue, "StageDispatched", getStageDispatchedMessage(0))
+	g.Expect(aw.Status.Conditions).To(gomega.HaveLen(1))
+	aw.Status.Conditions[0].LastTransitionMicroTime = metav1.NewMicroTime(time.Now().Add(-time.Hour))
+
+	// a repeated condition is updated in place with the time of its latest occurrence
+	cc.addOrUpdateCondition(aw, arbv1.AppWrapperCondDispatched, corev1.ConditionTrue, "StageDispatched", getStageDispatchedMessage(0))
+	g.Expect(aw.Status.Conditions).To(gomega.HaveLen(1))
+	g.Expect(aw.Status.Conditions[0].LastTransitionMicroTime.Time).To(gomega.BeTemporally("~", time.Now(), time.Second))
+
+	cc.addOrUpdateCondition(aw, arbv1.AppWrapperCondDispatched, corev1.ConditionTrue, "StageDispatched", getStageDispatchedMessage(1))
+	g.Expect(aw.Status.Conditions).To(gomega.HaveLen(2))
+}
+
+func TestCanRestart(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tests := []struct {
+		name        string
+		maxRestarts int
+		restarts    int
+		expected    bool
+	}{
+		{name: "no restart policy", maxRestarts: 0, restarts: 0, expected: false},
+		{name: "restarts left", maxRestarts: 2, restarts: 1, expected: true},
+		{name: "maximum number of restarts reached", maxRestarts: 2, restarts: 2, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aw := &arbv1.AppWrapper{
+				Spec:   arbv1.AppWrapperSpec{RestartPolicy: arbv1.RestartPolicy{MaxRestarts: tt.maxRestarts}},
+				Status: arbv1.AppWrapperStatus{NumberOfRestarts: tt.restarts},
+			}
+			g.Expect(canRestart(aw)).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+func TestGetRestartDelay(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	restartedAt := metav1.NewMicroTime(now.Add(-4 * time.Second))
+	conditions := []arbv1.AppWrapperCondition{
+		{Type: arbv1.AppWrapperCondFailed, LastTransitionMicroTime: restartedAt},
+		{Type: arbv1.AppWrapperCondRestarting, LastTransitionMicroTime: restartedAt},
+	}
+
+	tests := []struct {
+		name          string
+		backoff       int
+		queueJobState arbv1.AppWrapperConditionType
+		conditions    []arbv1.AppWrapperCondition
+		expected      time.Duration
+	}{
+		{name: "backoff not elapsed", backoff: 10, queueJobState: arbv1.AppWrapperCondRestarting, conditions: conditions, expected: 6 * time.Second},
+		{name: "backoff elapsed", backoff: 3, queueJobState: arbv1.AppWrapperCondRestarting, conditions: conditions, expected: 0},
+		{name: "no backoff", backoff: 0, queueJobState: arbv1.AppWrapperCondRestarting, conditions: conditions, expected: 0},
+		{name: "not restarting", backoff: 10, queueJobState: arbv1.AppWrapperCondQueueing, conditions: conditions, expected: 0},
+		{name: "no restarting condition", backoff: 10, queueJobState: arbv1.AppWrapperCondRestarting, conditions: conditions[:1], expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aw := &arbv1.AppWrapper{
+				Spec: arbv1.AppWrapperSpec{RestartPolicy: arbv1.RestartPolicy{BackoffInSeconds: tt.backoff}},
+				Status: arbv1.AppWrapperStatus{
+					QueueJobState: tt.queueJobState,
+					Conditions:    tt.conditions,
+				},
+			}
+			g.Expect(getRestartDelay(aw, now)).To(gomega.BeNumerically("~", tt.expected, time.Millisecond))
+		})
+	}
+}
+
+func TestGetStages(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	awWithStages := func(stages ...int) *arbv1.AppWrapper {
+		aw := &arbv1.AppWrapper{}
+		for _, stage := range stages {
+			aw.Spec.AggrResources.GenericItems = append(aw.Spec.AggrResources.GenericItems, arbv1.AppWrapperGenericResource{Stage: stage})
+		}
+		return aw
+	}
+
+	tests := []struct {
+		name              string
+		aw                *arbv1.AppWrapper
+		currentStage      int
+		expectedFirst     int
+		expectedNext      int
+		expectedNextFound bool
+	}{
+		{name: "no items", aw: awWithStages(), currentStage: 0, expectedFirst: 0, expectedNext: 0, expectedNextFound: false},
+		{name: "no stages", aw: awWithStages(0, 0), currentStage: 0, expectedFirst: 0, expectedNext: 0, expectedNextFound: false},
+		{name: "unordered stages", aw: awWithStages(3, 1, 2, 1), currentStage: 1, expectedFirst: 1, expectedNext: 2, expectedNextFound: true},
+		{name: "gap between stages", aw: awWithStages(0, 5, 10), currentStage: 5, expectedFirst: 0, expectedNext: 10, expectedNextFound: true},
+		{name: "last stage", aw: awWithStages(0, 5, 10), currentStage: 10, expectedFirst: 0, expectedNext: 0, expectedNextFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(getFirstStage(tt.aw)).To(gomega.Equal(tt.expectedFirst))
+			next, found := getNextStage(tt.aw, tt.currentStage)
+			g.Expect(next).To(gomega.Equal(tt.expectedNext))
+			g.Expect(found).To(gomega.Equal(tt.expectedNextFound))
+		})
+	}
+}
+
+func TestGetStageDispatchTime(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	now := time.Now()
+	dispatchedAt := metav1.NewMicroTime(now.Add(-time.Minute))
+	stageCondition := func(stage int, at time.Time) arbv1.AppWrapperCondition {
+		return arbv1.AppWrapperCondition{Type: arbv1.AppWrapperCondDispatched, Reason: "StageDispatched",
+			Message: getStageDispatchedMessage(stage), LastTransitionMicroTime: metav1.NewMicroTime(at)}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []arbv1.AppWrapperCondition
+		stage      int
+		expected   time.Time
+	}{
+		{name: "stage dispatched", conditions: []arbv1.AppWrapperCondition{stageCondition(0, now.Add(-time.Minute)), stageCondition(1, now)}, stage: 1, expected: now},
+		{name: "stage not dispatched", conditions: []arbv1.AppWrapperCondition{stageCondition(0, now.Add(-time.Minute))}, stage: 1, expected: dispatchedAt.Time},
+		{name: "stage dispatched before a restart", conditions: []arbv1.AppWrapperCondition{stageCondition(1, now.Add(-time.Hour))}, stage: 1, expected: dispatchedAt.Time},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aw := &arbv1.AppWrapper{Status: arbv1.AppWrapperStatus{ControllerFirstDispatchTimestamp: dispatchedAt, Conditions: tt.conditions}}
+			g.Expect(getStageDispatchTime(aw, tt.stage)).To(gomega.BeTemporally("~", tt.expected, time.Millisecond))
+		})
+	}
+}
+
+func TestItemStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	aw := &arbv1.AppWrapper{}
+	setItemStatus(aw, arbv1.AppWrapperItemStatus{Index: 0, Kind: "Service", Name: "svc", State: arbv1.AppWrapperItemStateCreated})
+	setItemStatus(aw, arbv1.AppWrapperItemStatus{Index: 1, Kind: "Job", Name: "job", State: arbv1.AppWrapperItemStateCreated})
+	g.Expect(aw.Status.Items).To(gomega.HaveLen(2))
+
+	// an item created again replaces its earlier reference
+	setItemStatus(aw, arbv1.AppWrapperItemStatus{Index: 1, Kind: "Job", Name: "job-2", State: arbv1.AppWrapperItemStateCreated})
+	g.Expect(aw.Status.Items).To(gomega.HaveLen(2))
+	g.Expect(aw.Status.Items[1].Name).To(gomega.Equal("job-2"))
+
+	g.Expect(setItemState(aw, 1, arbv1.AppWrapperItemStateCompleted)).To(gomega.BeTrue())
+	g.Expect(setItemState(aw, 1, arbv1.AppWrapperItemStateCompleted)).To(gomega.BeFalse())
+	g.Expect(setItemState(aw, 2, arbv1.AppWrapperItemStateFailed)).To(gomega.BeFalse())
+	g.Expect(aw.Status.Items[0].State).To(gomega.Equal(arbv1.AppWrapperItemStateCreated))
+	g.Expect(aw.Status.Items[1].State).To(gomega.Equal(arbv1.AppWrapperItemStateCompleted))
+	g.Expect(aw.Status.BlockingItem).To(gomega.Equal("Service/svc"))
+
+	g.Expect(setItemState(aw, 0, arbv1.AppWrapperItemStateReady)).To(gomega.BeTrue())
+	g.Expect(aw.Status.BlockingItem).To(gomega.BeEmpty())
+}
+
+func TestGetBlockingItem(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		items    []arbv1.AppWrapperItemStatus
+		expected string
+	}{
+		{name: "no items", expected: ""},
+		{name: "all ready or completed", items: []arbv1.AppWrapperItemStatus{
+			{Index: 0, Kind: "Service", Name: "svc", State: arbv1.AppWrapperItemStateReady},
+			{Index: 1, Kind: "Job", Name: "job", State: arbv1.AppWrapperItemStateCompleted},
+		}, expected: ""},
+		{name: "first pending item", items: []arbv1.AppWrapperItemStatus{
+			{Index: 2, Kind: "Job", Name: "job", State: arbv1.AppWrapperItemStateCreated},
+			{Index: 0, Kind: "Service", Name: "svc", State: arbv1.AppWrapperItemStateReady},
+			{Index: 1, Kind: "Deployment", Name: "deploy", State: arbv1.AppWrapperItemStateCreated},
+		}, expected: "Deployment/deploy"},
+		{name: "failed item before pending items", items: []arbv1.AppWrapperItemStatus{
+			{Index: 0, Kind: "Deployment", Name: "deploy", State: arbv1.AppWrapperItemStateCreated},
+			{Index: 1, Kind: "Job", Name: "job", State: arbv1.AppWrapperItemStateFailed},
+		}, expected: "Job/job"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(getBlockingItem(tt.items)).To(gomega.Equal(tt.expected))
+		})
+	}
+}
+
+func TestGetConcurrencyLimitMessage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	newAW := func(namespace, name, user string, canRun bool, state arbv1.AppWrapperState) *arbv1.AppWrapper {
+		aw := &arbv1.AppWrapper{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status:     arbv1.AppWrapperStatus{CanRun: canRun, State: state},
+		}
+		if len(user) > 0 {
+			aw.Annotations = map[string]string{"user": user}
+		}
+		return aw
+	}
+	// every AppWrapper has two pods
+	podCount := func(*arbv1.AppWrapper) int32 { return 2 }
+	aw := newAW("ns1", "aw", "alice", false, arbv1.AppWrapperStateEnqueued)
+	usage := newConcurrencyUsage("user")
+	for _, other := range []*arbv1.AppWrapper{
+		aw,
+		newAW("ns1", "running", "bob", true, arbv1.AppWrapperStateActive),
+		newAW("ns1", "completed", "alice", true, arbv1.AppWrapperStateCompleted),
+		newAW("ns1", "queued", "alice", false, arbv1.AppWrapperStateEnqueued),
+		newAW("ns2", "other", "alice", true, arbv1.AppWrapperStateActive),
+	} {
+		usage.update(other, podCount)
+	}
+	namespaceCount, userCount := usage.getCounts(aw)
+
+	tests := []struct {
+		name     string
+		limits   *config.ConcurrencyLimits
+		expected string
+	}{
+		{name: "no limits", limits: nil, expected: ""},
+		{name: "within limits", limits: &config.ConcurrencyLimits{MaxAppWrappersPerNamespace: pointer.Int32(2), MaxPodsPerNamespace: pointer.Int32(4),
+			MaxAppWrappersPerUser: pointer.Int32(2), MaxPodsPerUser: pointer.Int32(4), UserAnnotation: "user"}, expected: ""},
+		{name: "zero limit", limits: &config.ConcurrencyLimits{MaxAppWrappersPerNamespace: pointer.Int32(0)}, expected: ""},
+		{name: "namespace appwrappers", limits: &config.ConcurrencyLimits{MaxAppWrappersPerNamespace: pointer.Int32(1)},
+			expected: "Namespace ns1 has reached its limit of 1 dispatched AppWrappers."},
+		{name: "namespace pods", limits: &config.ConcurrencyLimits{MaxPodsPerNamespace: pointer.Int32(3)},
+			expected: "Namespace ns1 has reached its limit of 3 pods of dispatched AppWrappers."},
+		{name: "user appwrappers", limits: &config.ConcurrencyLimits{MaxAppWrappersPerUser: pointer.Int32(1), UserAnnotation: "user"},
+			expected: "User alice has reached its limit of 1 dispatched AppWrappers."},
+		{name: "user pods", limits: &config.ConcurrencyLimits{MaxPodsPerUser: pointer.Int32(3), UserAnnotation: "user"},
+			expected: "User alice has reached its limit of 3 pods of dispatched AppWrappers."},
+		{name: "user limits without annotation", limits: &config.ConcurrencyLimits{MaxAppWrappersPerUser: pointer.Int32(1)}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(getConcurrencyLimitMessage(tt.limits, aw, namespaceCount, userCount, podCount(aw))).To(gomega.Equal(tt.expected))
+		})
+	}
+}
@@ -19,19 +19,24 @@ package queuejobdispatch
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
 	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"k8s.io/client-go/tools/cache"
 
@@ -44,26 +49,56 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 )
 
+// AgentIdAnnotation records on the AppWrappers passed to the dispatcher the agent cluster they come from
+const AgentIdAnnotation = "workload.codeflare.dev/agent-id"
+
 type JobClusterAgent struct {
-	AgentId         string
-	DeploymentName  string
+	AgentId        string
+	DeploymentName string
+	// Name and labels of the agent cluster, matched against the cluster scheduling constraints of AppWrappers
+	ClusterName   string
+	ClusterLabels map[string]string
+	// Relative preference for the agent cluster when scoring clusters
+	Weight          int32
 	queuejobclients *clientset.Clientset
 	k8sClients      *kubernetes.Clientset // for the update of aggr resouces
-	AggrResources   *clusterstateapi.Resource
 
 	jobInformer arbinformers.AppWrapperInformer
 	jobLister   arblisters.AppWrapperLister
 	jobSynced   func() bool
 
 	agentEventQueue *cache.FIFO
+
+	// consecutive failures of metric queries and informer watches, reset by a successful metric query
+	healthMutex         sync.Mutex
+	consecutiveFailures int
+
+	// resources available in the agent cluster and last capacity report published by the agent cluster,
+	// nil for agents only serving cluster metrics or whose report is stale
+	capacityMutex  sync.RWMutex
+	aggrResources  *clusterstateapi.Resource
+	capacityReport *arbv1.ClusterCapacityStatus
+	// age after which a capacity report is stale, 0 to accept reports of any age
+	capacityReportMaxAge time.Duration
 }
 
-func NewJobClusterAgent(config string, agentEventQueue *cache.FIFO) *JobClusterAgent {
+// NewJobClusterAgent creates an agent from a configuration of the form kubeconfig:deploymentName[:labels],
+// where the optional cluster labels are semicolon-separated key=value pairs.
+func NewJobClusterAgent(config string, agentEventQueue *cache.FIFO, capacityReportMaxAge time.Duration) *JobClusterAgent {
 	configStrings := strings.Split(config, ":")
 	if len(configStrings) < 2 {
 		klog.Errorf("[agentEventQueue] Invalid agent configuration: %s.  Agent cluster will not be instantiated.", config)
 		return nil
 	}
+	clusterLabels := map[string]string{}
+	if len(configStrings) > 2 && len(configStrings[2]) > 0 {
+		var err error
+		clusterLabels, err = labels.ConvertSelectorToLabelsMap(strings.ReplaceAll(configStrings[2], ";", ","))
+		if err != nil {
+			klog.Errorf("[agentEventQueue] Invalid labels in agent configuration: %s, err=%v.  Agent cluster will not be instantiated.", config, err)
+			return nil
+		}
+	}
 	klog.V(2).Infof("[Dispatcher: Agent] Creation: %s\n", "/root/kubernetes/"+configStrings[0])
 
 	agent_config, err := clientcmd.BuildConfigFromFlags("", "/root/kubernetes/"+configStrings[0])
@@ -71,27 +106,44 @@ func NewJobClusterAgent(config string, agentEventQueue *cache.FIFO) *JobClusterA
 		klog.V(2).Infof("[Dispatcher: Agent] Cannot create client\n")
 		return nil
 	}
-	qa := &JobClusterAgent{
-		AgentId:         "/root/kubernetes/" + configStrings[0],
-		DeploymentName:  configStrings[1],
-		queuejobclients: clientset.NewForConfigOrDie(agent_config),
-		k8sClients:      kubernetes.NewForConfigOrDie(agent_config),
-		AggrResources:   clusterstateapi.EmptyResource(),
+	qa, err := NewJobClusterAgentForConfig("/root/kubernetes/"+configStrings[0], configStrings[1], configStrings[0], clusterLabels, 1, agent_config, agentEventQueue, capacityReportMaxAge)
+	if err != nil {
+		klog.Fatalf("Could not instantiate k8s client, err=%v", err)
 	}
-	qa.agentEventQueue = agentEventQueue
+	return qa
+}
 
-	if qa.queuejobclients == nil {
-		klog.V(2).Infof("[Dispatcher: Agent] Cannot Create Client\n")
-	} else {
-		klog.V(2).Infof("[Dispatcher: Agent] %s: Create Clients Suceessfully\n", qa.AgentId)
+// NewJobClusterAgentForConfig creates an agent connecting to the agent cluster with the given rest config.
+// Capacity reports older than capacityReportMaxAge count as failures to reach the agent cluster.
+func NewJobClusterAgentForConfig(agentId string, deploymentName string, clusterName string, clusterLabels map[string]string,
+	weight int32, agent_config *rest.Config, agentEventQueue *cache.FIFO, capacityReportMaxAge time.Duration) (*JobClusterAgent, error) {
+	queuejobclients, err := clientset.NewForConfig(agent_config)
+	if err != nil {
+		return nil, err
 	}
-
-	appWrapperClient, err := clientset.NewForConfig(agent_config)
+	k8sClients, err := kubernetes.NewForConfig(agent_config)
 	if err != nil {
-		klog.Fatalf("Could not instantiate k8s client, err=%v", err)
+		return nil, err
 	}
+	if weight < 1 {
+		weight = 1
+	}
+	qa := &JobClusterAgent{
+		AgentId:         agentId,
+		DeploymentName:  deploymentName,
+		ClusterName:     clusterName,
+		ClusterLabels:   clusterLabels,
+		Weight:          weight,
+		queuejobclients: queuejobclients,
+		k8sClients:      k8sClients,
+		aggrResources:   clusterstateapi.EmptyResource(),
+
+		capacityReportMaxAge: capacityReportMaxAge,
+	}
+	qa.agentEventQueue = agentEventQueue
+	klog.V(2).Infof("[Dispatcher: Agent] %s: Create Clients Suceessfully\n", qa.AgentId)
 
-	qa.jobInformer = informerFactory.NewFilteredSharedInformerFactory(appWrapperClient, 0, v1.NamespaceAll,
+	qa.jobInformer = informerFactory.NewFilteredSharedInformerFactory(queuejobclients, 0, v1.NamespaceAll,
 		func(opt *metav1.ListOptions) {
 			opt.LabelSelector = "IsDispatched=true"
 		},
@@ -114,13 +166,20 @@ func NewJobClusterAgent(config string, agentEventQueue *cache.FIFO) *JobClusterA
 			},
 		})
 
+	if err := qa.jobInformer.Informer().SetWatchErrorHandler(func(r *cache.Reflector, err error) {
+		qa.recordFailure(err)
+		cache.DefaultWatchErrorHandler(r, err)
+	}); err != nil {
+		return nil, err
+	}
+
 	qa.jobLister = qa.jobInformer.Lister()
 
 	qa.jobSynced = qa.jobInformer.Informer().HasSynced
 
 	qa.UpdateAggrResources(context.Background())
 
-	return qa
+	return qa, nil
 }
 
 func (cc *JobClusterAgent) addQueueJob(obj interface{}) {
@@ -130,7 +189,7 @@ func (cc *JobClusterAgent) addQueueJob(obj interface{}) {
 		return
 	}
 	klog.V(10).Infof("[TTime]: %s Adding New Job: %s/%s to EventQ\n", time.Now().String(), qj.Namespace, qj.Name)
-	cc.agentEventQueue.Add(qj)
+	cc.agentEventQueue.Add(cc.withAgentId(qj))
 }
 
 func (cc *JobClusterAgent) updateQueueJob(oldObj, newObj interface{}) {
@@ -140,7 +199,7 @@ func (cc *JobClusterAgent) updateQueueJob(oldObj, newObj interface{}) {
 		return
 	}
 	klog.V(10).Infof("[TTime]: %s Adding Update Job: %s/%s to EventQ\n", time.Now().String(), newQJ.Namespace, newQJ.Name)
-	cc.agentEventQueue.Add(newQJ)
+	cc.agentEventQueue.Add(cc.withAgentId(newQJ))
 }
 
 func (cc *JobClusterAgent) deleteQueueJob(obj interface{}) {
@@ -150,7 +209,17 @@ func (cc *JobClusterAgent) deleteQueueJob(obj interface{}) {
 		return
 	}
 	klog.V(10).Infof("[TTime]: %s Adding Delete Job: %s/%s to EventQ\n", time.Now().String(), qj.Namespace, qj.Name)
-	cc.agentEventQueue.Add(qj)
+	cc.agentEventQueue.Add(cc.withAgentId(qj))
+}
+
+// withAgentId returns a copy of the AppWrapper annotated with the agent id, the informer cache must not be modified
+func (cc *JobClusterAgent) withAgentId(qj *arbv1.AppWrapper) *arbv1.AppWrapper {
+	annotated := qj.DeepCopy()
+	if annotated.Annotations == nil {
+		annotated.Annotations = map[string]string{}
+	}
+	annotated.Annotations[AgentIdAnnotation] = cc.AgentId
+	return annotated
 }
 
 func (qa *JobClusterAgent) Run(stopCh <-chan struct{}) {
@@ -158,6 +227,46 @@ func (qa *JobClusterAgent) Run(stopCh <-chan struct{}) {
 	cache.WaitForCacheSync(stopCh, qa.jobSynced)
 }
 
+// recordFailure counts a failure to reach the agent cluster
+func (qa *JobClusterAgent) recordFailure(err error) {
+	qa.healthMutex.Lock()
+	defer qa.healthMutex.Unlock()
+	qa.consecutiveFailures++
+	klog.V(4).Infof("[Dispatcher: Agent] Failure %d to reach Agent ID: %s, Error: %v\n", qa.consecutiveFailures, qa.AgentId, err)
+}
+
+// recordSuccess resets the count of consecutive failures
+func (qa *JobClusterAgent) recordSuccess() {
+	qa.healthMutex.Lock()
+	defer qa.healthMutex.Unlock()
+	qa.consecutiveFailures = 0
+}
+
+// ConsecutiveFailures returns the number of consecutive failures to reach the agent cluster
+func (qa *JobClusterAgent) ConsecutiveFailures() int {
+	qa.healthMutex.Lock()
+	defer qa.healthMutex.Unlock()
+	return qa.consecutiveFailures
+}
+
+// ListJobs returns the AppWrappers dispatched to the agent cluster, and whether the informer has synced
+func (qa *JobClusterAgent) ListJobs() ([]*arbv1.AppWrapper, bool) {
+	if !qa.jobSynced() {
+		return nil, false
+	}
+	appwrappers, err := qa.jobLister.List(labels.Everything())
+	if err != nil {
+		return nil, false
+	}
+	return appwrappers, true
+}
+
+// CheckHealth checks that the API server of the agent cluster is reachable
+func (qa *JobClusterAgent) CheckHealth() error {
+	_, err := qa.k8sClients.Discovery().ServerVersion()
+	return err
+}
+
 func (qa *JobClusterAgent) DeleteJob(ctx context.Context, cqj *arbv1.AppWrapper) {
 	qj_temp := cqj.DeepCopy()
 	klog.V(2).Infof("[Dispatcher: Agent] Request deletion of XQJ %s/%s to Agent %s\n", qj_temp.Namespace, qj_temp.Name, qa.AgentId)
@@ -200,21 +309,96 @@ type ClusterMetricsList struct {
 	} `json:"items"`
 }
 
+// CapacityReport returns a copy of the last capacity report published by the agent cluster, nil if none
+// or if it is stale
+func (qa *JobClusterAgent) CapacityReport() *arbv1.ClusterCapacityStatus {
+	qa.capacityMutex.RLock()
+	defer qa.capacityMutex.RUnlock()
+	return qa.capacityReport.DeepCopy()
+}
+
+// AggrResources returns a copy of the resources available in the agent cluster, nil if never set
+func (qa *JobClusterAgent) AggrResources() *clusterstateapi.Resource {
+	qa.capacityMutex.RLock()
+	defer qa.capacityMutex.RUnlock()
+	if qa.aggrResources == nil {
+		return nil
+	}
+	return qa.aggrResources.Clone()
+}
+
+// SetAggrResources sets the resources available in the agent cluster
+func (qa *JobClusterAgent) SetAggrResources(resources *clusterstateapi.Resource) {
+	qa.capacityMutex.Lock()
+	defer qa.capacityMutex.Unlock()
+	qa.aggrResources = resources.Clone()
+}
+
+// isCapacityReportStale returns whether a capacity report was last updated more than maxAge ago, a zero
+// maxAge accepts reports of any age
+func isCapacityReportStale(report *arbv1.ClusterCapacityStatus, now time.Time, maxAge time.Duration) bool {
+	return maxAge > 0 && now.Sub(report.LastUpdateTime.Time) > maxAge
+}
+
+// UpdateAggrResources refreshes the resources available in the agent cluster from the ClusterCapacity it
+// publishes, or from its cluster metrics when it publishes none
 func (qa *JobClusterAgent) UpdateAggrResources(ctx context.Context) error {
 	klog.V(6).Infof("[Dispatcher: UpdateAggrResources] Getting aggregated resources for Agent ID: %s with Agent Name: %s\n", qa.AgentId, qa.DeploymentName)
 
-	// Read the Agent XQJ Deployment object
-	if qa.k8sClients == nil {
+	if qa.queuejobclients == nil || qa.k8sClients == nil {
 		return nil
+	}
 
+	capacity, err := qa.queuejobclients.WorkloadV1beta1().ClusterCapacities().Get(ctx, arbv1.ClusterCapacityName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.V(6).Infof("[Dispatcher: UpdateAggrResources] No capacity report from Agent ID: %s, reading cluster metrics\n", qa.AgentId)
+		qa.capacityMutex.Lock()
+		qa.capacityReport = nil
+		qa.capacityMutex.Unlock()
+		return qa.updateAggrResourcesFromMetrics(ctx)
 	}
+	if err != nil {
+		klog.V(2).Infof("[Dispatcher: UpdateAggrResources] Failed to get capacity report from Agent ID: %s with Agent Name: %s, Error: %v\n", qa.AgentId, qa.DeploymentName, err)
+		qa.recordFailure(err)
+		return err
+	}
+	// the API server of the agent cluster is reachable but its controller no longer reports its capacity
+	if isCapacityReportStale(&capacity.Status, time.Now(), qa.capacityReportMaxAge) {
+		err := fmt.Errorf("capacity report last updated at %v is older than %v", capacity.Status.LastUpdateTime, qa.capacityReportMaxAge)
+		klog.V(2).Infof("[Dispatcher: UpdateAggrResources] Stale capacity report from Agent ID: %s with Agent Name: %s, Error: %v\n", qa.AgentId, qa.DeploymentName, err)
+		qa.capacityMutex.Lock()
+		qa.capacityReport = nil
+		qa.capacityMutex.Unlock()
+		qa.recordFailure(err)
+		return err
+	}
+	qa.recordSuccess()
+	aggrResources := clusterstateapi.NewResource(capacity.Status.Allocatable)
+	qa.capacityMutex.Lock()
+	qa.capacityReport = capacity.Status.DeepCopy()
+	qa.aggrResources = aggrResources
+	qa.capacityMutex.Unlock()
+
+	klog.V(4).Infof("[Dispatcher: UpdateAggrResources] Updated Aggr Resources of %s from capacity report of %v: %v, queue length %d\n",
+		qa.AgentId, capacity.Status.LastUpdateTime, aggrResources, capacity.Status.QueueLength)
+	return nil
+}
 
+// updateAggrResourcesFromMetrics refreshes the resources available in the agent cluster from the cluster
+// metrics served by its metrics adapter
+func (qa *JobClusterAgent) updateAggrResourcesFromMetrics(ctx context.Context) error {
 	data, err := qa.k8sClients.RESTClient().Get().AbsPath("apis/external.metrics.k8s.io/v1beta1/namespaces/default/cluster-external-metric").DoRaw(ctx)
 
 	if err != nil {
 		klog.V(2).Infof("[Dispatcher: UpdateAggrResources] Failed to get metrics from deployment Agent ID: %s with Agent Name: %s, Error: %v\n", qa.AgentId, qa.DeploymentName, err)
-
+		qa.recordFailure(err)
+		return err
 	} else {
+		qa.recordSuccess()
+		aggrResources := qa.AggrResources()
+		if aggrResources == nil {
+			aggrResources = clusterstateapi.EmptyResource()
+		}
 		res := &ClusterMetricsList{}
 		unmarshalerr := json.Unmarshal(data, res)
 		if unmarshalerr != nil {
@@ -241,13 +425,13 @@ func (qa *JobClusterAgent) UpdateAggrResources(ctx context.Context) error {
 							f_zero := math.Float64bits(0.0)
 							if f_num > f_zero {
 								if strings.Compare(clusterMetricType, "cpu") == 0 {
-									qa.AggrResources.MilliCPU = num
+									aggrResources.MilliCPU = num
 									klog.V(10).Infof("[Dispatcher: UpdateAggrResources] Updated %s from %f to %f for metrics: %v from deployment Agent ID: %s with Agent Name: %s\n",
-										clusterMetricType, qa.AggrResources.MilliCPU, num, res, qa.AgentId, qa.DeploymentName)
+										clusterMetricType, aggrResources.MilliCPU, num, res, qa.AgentId, qa.DeploymentName)
 								} else {
-									qa.AggrResources.Memory = num
+									aggrResources.Memory = num
 									klog.V(10).Infof("[Dispatcher: UpdateAggrResources] Updated %s from %f to %f for metrics: %v from deployment Agent ID: %s with Agent Name: %s\n",
-										clusterMetricType, qa.AggrResources.Memory, num, res, qa.AgentId, qa.DeploymentName)
+										clusterMetricType, aggrResources.Memory, num, res, qa.AgentId, qa.DeploymentName)
 								}
 							} else {
 								klog.Warningf("[Dispatcher: UpdateAggrResources] Possible issue converting %s string value of %s to float type.  Conversion result: %f\n",
@@ -260,7 +444,7 @@ func (qa *JobClusterAgent) UpdateAggrResources(ctx context.Context) error {
 							klog.Warningf("[Dispatcher: UpdateAggrResources] Possible issue converting %s string value of %s due to int64 type, error: %v\n",
 								clusterMetricType, res.Items[i].Value, err)
 						} else {
-							qa.AggrResources.GPU = num
+							aggrResources.GPU = num
 						}
 					} else {
 						klog.V(9).Infof("[Dispatcher: UpdateAggrResources] Unknown label value: %s for metrics: %v from deployment Agent ID: %s with Agent Name: %s\n",
@@ -272,9 +456,10 @@ func (qa *JobClusterAgent) UpdateAggrResources(ctx context.Context) error {
 				klog.V(2).Infof("[Dispatcher: UpdateAggrResources] Failed to obtain values for metrics: %v from deployment Agent ID: %s with Agent Name: %s, Error: %v\n", res, qa.AgentId, qa.DeploymentName, unmarshalerr)
 			}
 		}
+		qa.SetAggrResources(aggrResources)
 	}
 
-	klog.V(4).Infof("[Dispatcher: UpdateAggrResources] Updated Aggr Resources of %s: %v\n", qa.AgentId, qa.AggrResources)
+	klog.V(4).Infof("[Dispatcher: UpdateAggrResources] Updated Aggr Resources of %s: %v\n", qa.AgentId, qa.AggrResources())
 	return nil
 }
 
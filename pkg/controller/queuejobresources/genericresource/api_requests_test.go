@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+// fakeAPIServer serves the discovery information of batch/v1 jobs and, once installed, of the example.com/v1
+// widgets CRD. It accepts any object creation and counts the requests it receives.
+type fakeAPIServer struct {
+	mutex        sync.Mutex
+	crdInstalled bool
+	requests     int
+	discovery    int
+}
+
+func (s *fakeAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests++
+	crdInstalled := s.crdInstalled
+	isDiscovery := r.Method == http.MethodGet && (r.URL.Path == "/api" || r.URL.Path == "/apis" || strings.Count(r.URL.Path, "/") <= 3 && r.URL.Path != "/apis/batch/v1/jobs")
+	if isDiscovery {
+		s.discovery++
+	}
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/api":
+		fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+	case r.URL.Path == "/api/v1":
+		fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"v1","resources":[{"name":"configmaps","namespaced":true,"kind":"ConfigMap","verbs":["create","delete","get","list","watch"]}]}`)
+	case r.URL.Path == "/apis":
+		groups := `{"name":"batch","versions":[{"groupVersion":"batch/v1","version":"v1"}],"preferredVersion":{"groupVersion":"batch/v1","version":"v1"}}`
+		if crdInstalled {
+			groups += `,{"name":"example.com","versions":[{"groupVersion":"example.com/v1","version":"v1"}],"preferredVersion":{"groupVersion":"example.com/v1","version":"v1"}}`
+		}
+		fmt.Fprintf(w, `{"kind":"APIGroupList","apiVersion":"v1","groups":[%s]}`, groups)
+	case r.URL.Path == "/apis/batch/v1":
+		fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"batch/v1","resources":[{"name":"jobs","namespaced":true,"kind":"Job","verbs":["create","delete","get","list","watch"]}]}`)
+	case r.URL.Path == "/apis/example.com/v1" && crdInstalled:
+		fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"example.com/v1","resources":[{"name":"widgets","namespaced":true,"kind":"Widget","verbs":["create","delete","get","list","watch"]}]}`)
+	case r.Method == http.MethodGet && (strings.HasSuffix(r.URL.Path, "/jobs") || strings.HasSuffix(r.URL.Path, "/widgets")):
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"List","metadata":{},"items":[]}`)
+	case r.Method == http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+	}
+}
+
+func (s *fakeAPIServer) counts() (requests int, discovery int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests, s.discovery
+}
+
+func newGenericItem(apiVersion string, kind string, name string) *arbv1.AppWrapperGenericResource {
+	return &arbv1.AppWrapperGenericResource{
+		GenericTemplate: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+			`{"apiVersion":"%s","kind":"%s","metadata":{"name":"%s","namespace":"default"}}`, apiVersion, kind, name))},
+	}
+}
+
+func newFakeGenericResources(server *httptest.Server) *GenericResources {
+	return NewAppWrapperGenericResource(&rest.Config{Host: server.URL, QPS: -1})
+}
+
+var fakeAppWrapper = &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: "aw", Namespace: "default", UID: "uid"}}
+
+// TestSyncQueueJobDiscovery validates that the discovery information is fetched once for all the generic items
+// and refreshed when the kind of an item is not found
+func TestSyncQueueJobDiscovery(t *testing.T) {
+	fake := &fakeAPIServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	gr := newFakeGenericResources(server)
+
+	for i := 0; i < 3; i++ {
+		itemStatus, err := gr.SyncQueueJob(fakeAppWrapper, newGenericItem("batch/v1", "Job", fmt.Sprintf("job-%d", i)))
+		assert.NoError(t, err)
+		assert.Equal(t, "jobs", itemStatus.Resource)
+	}
+	requests, discovery := fake.counts()
+	assert.Equal(t, 6, requests-discovery, "one list and one create per item")
+	assert.Equal(t, 4, discovery, "discovery of the core group, the groups and their versions only once")
+
+	// a kind that is not installed refreshes the discovery information
+	_, err := gr.SyncQueueJob(fakeAppWrapper, newGenericItem("example.com/v1", "Widget", "widget"))
+	assert.Error(t, err)
+	_, discoveryAfterMiss := fake.counts()
+	assert.Greater(t, discoveryAfterMiss, discovery)
+
+	// the kind is found once its CRD is installed
+	fake.mutex.Lock()
+	fake.crdInstalled = true
+	fake.mutex.Unlock()
+	itemStatus, err := gr.SyncQueueJob(fakeAppWrapper, newGenericItem("example.com/v1", "Widget", "widget"))
+	assert.NoError(t, err)
+	assert.Equal(t, "widgets", itemStatus.Resource)
+}
+
+// TestSyncQueueJobConcurrently validates that the items can be created concurrently, run with -race
+func TestSyncQueueJobConcurrently(t *testing.T) {
+	fake := &fakeAPIServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	gr := newFakeGenericResources(server)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := gr.SyncQueueJob(fakeAppWrapper, newGenericItem("batch/v1", "Job", fmt.Sprintf("job-%d", i)))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// BenchmarkSyncQueueJob measures the latency of the creation of a generic item and the number of API requests it
+// sends, discovery included
+func BenchmarkSyncQueueJob(b *testing.B) {
+	fake := &fakeAPIServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	gr := newFakeGenericResources(server)
+	item := newGenericItem("batch/v1", "Job", "job")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gr.SyncQueueJob(fakeAppWrapper, item); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	requests, discovery := fake.counts()
+	b.ReportMetric(float64(requests)/float64(b.N), "requests/op")
+	b.ReportMetric(float64(discovery)/float64(b.N), "discovery-requests/op")
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// PodSet is a group of identical pods created for a generic item
+type PodSet struct {
+	// Count is the number of pods in the set
+	Count int
+	// Spec is the unstructured spec of the pods of the set
+	Spec map[string]interface{}
+}
+
+// ResourceExtractor returns the pods a generic item of a given kind creates, so that its demand can be
+// estimated without custompodresources.
+type ResourceExtractor interface {
+	// Extract returns the pod sets of the unstructured content of a generic item
+	Extract(object map[string]interface{}) ([]PodSet, error)
+}
+
+// ResourceExtractorFunc adapts an ordinary function to the ResourceExtractor interface
+type ResourceExtractorFunc func(object map[string]interface{}) ([]PodSet, error)
+
+// Extract calls f(object)
+func (f ResourceExtractorFunc) Extract(object map[string]interface{}) ([]PodSet, error) {
+	return f(object)
+}
+
+var resourceExtractors = struct {
+	sync.RWMutex
+	byKind map[schema.GroupVersionKind]ResourceExtractor
+}{byKind: map[schema.GroupVersionKind]ResourceExtractor{}}
+
+// RegisterResourceExtractor registers the extractor of a kind, replacing any previous registration.
+// An empty version matches the versions of the kind that have no extractor of their own.
+func RegisterResourceExtractor(gvk schema.GroupVersionKind, extractor ResourceExtractor) {
+	resourceExtractors.Lock()
+	defer resourceExtractors.Unlock()
+	resourceExtractors.byKind[gvk] = extractor
+}
+
+func getResourceExtractor(gvk schema.GroupVersionKind) (ResourceExtractor, bool) {
+	resourceExtractors.RLock()
+	defer resourceExtractors.RUnlock()
+	if extractor, found := resourceExtractors.byKind[gvk]; found {
+		return extractor, true
+	}
+	gvk.Version = ""
+	extractor, found := resourceExtractors.byKind[gvk]
+	return extractor, found
+}
+
+func init() {
+	RegisterResourceExtractor(schema.GroupVersionKind{Kind: "Pod"}, ResourceExtractorFunc(extractPod))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "batch", Kind: "Job"}, ResourceExtractorFunc(extractJob))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "kubeflow.org", Kind: "PyTorchJob"}, replicaSpecsExtractor("pytorchReplicaSpecs"))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "kubeflow.org", Kind: "MPIJob"}, replicaSpecsExtractor("mpiReplicaSpecs"))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "kubeflow.org", Kind: "TFJob"}, replicaSpecsExtractor("tfReplicaSpecs"))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "ray.io", Kind: "RayCluster"}, ResourceExtractorFunc(extractRayCluster))
+	RegisterResourceExtractor(schema.GroupVersionKind{Group: "jobset.x-k8s.io", Kind: "JobSet"}, ResourceExtractorFunc(extractJobSet))
+}
+
+// extractPodSets returns the pod sets of the unstructured content of a generic item computed by the extractor
+// registered for its kind, or false when there is no such extractor.
+func extractPodSets(object map[string]interface{}) ([]PodSet, bool, error) {
+	gvk := (&unstructured.Unstructured{Object: object}).GroupVersionKind()
+	extractor, found := getResourceExtractor(gvk)
+	if !found {
+		return nil, false, nil
+	}
+	podSets, err := extractor.Extract(object)
+	if err != nil {
+		return nil, true, fmt.Errorf("extracting pod sets of %s: %w", gvk, err)
+	}
+	return podSets, true, nil
+}
+
+// podSetAt returns a pod set of count pods whose spec is found at the given fields of object
+func podSetAt(object map[string]interface{}, count int64, fields ...string) (PodSet, error) {
+	podSpec, found, err := unstructured.NestedMap(object, fields...)
+	if err != nil {
+		return PodSet{}, err
+	}
+	if !found {
+		return PodSet{}, fmt.Errorf("no pod spec found at %s", strings.Join(fields, "."))
+	}
+	return PodSet{Count: int(count), Spec: podSpec}, nil
+}
+
+// nestedCount returns the integer found at the given fields of object, or defaultValue when it is missing.
+// Numbers decoded by encoding/json are float64 while those of typed objects are int64, so both are accepted.
+func nestedCount(object map[string]interface{}, defaultValue int64, fields ...string) int64 {
+	value, found, err := unstructured.NestedFieldNoCopy(object, fields...)
+	if err != nil || !found {
+		return defaultValue
+	}
+	return toCount(value, defaultValue)
+}
+
+func toCount(value interface{}, defaultValue int64) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return defaultValue
+}
+
+func extractPod(object map[string]interface{}) ([]PodSet, error) {
+	podSet, err := podSetAt(object, 1, "spec")
+	if err != nil {
+		return nil, err
+	}
+	return []PodSet{podSet}, nil
+}
+
+// extractJob counts the pods a Job runs at once: its parallelism, bounded by its completions
+func extractJob(object map[string]interface{}) ([]PodSet, error) {
+	count := nestedCount(object, 1, "spec", "parallelism")
+	if completions := nestedCount(object, count, "spec", "completions"); completions < count {
+		count = completions
+	}
+	podSet, err := podSetAt(object, count, "spec", "template", "spec")
+	if err != nil {
+		return nil, err
+	}
+	return []PodSet{podSet}, nil
+}
+
+// replicaSpecsExtractor returns the extractor of the Kubeflow training jobs, which hold a map of replica types
+// to replica specs in the given field of their spec.
+func replicaSpecsExtractor(field string) ResourceExtractor {
+	return ResourceExtractorFunc(func(object map[string]interface{}) ([]PodSet, error) {
+		replicaSpecs, found, err := unstructured.NestedMap(object, "spec", field)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("no replica specs found at spec.%s", field)
+		}
+		replicaTypes := make([]string, 0, len(replicaSpecs))
+		for replicaType := range replicaSpecs {
+			replicaTypes = append(replicaTypes, replicaType)
+		}
+		sort.Strings(replicaTypes)
+
+		var podSets []PodSet
+		for _, replicaType := range replicaTypes {
+			replicaSpec, ok := replicaSpecs[replicaType].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("replica spec %s is not an object", replicaType)
+			}
+			podSet, err := podSetAt(replicaSpec, nestedCount(replicaSpec, 1, "replicas"), "template", "spec")
+			if err != nil {
+				return nil, fmt.Errorf("replica spec %s: %w", replicaType, err)
+			}
+			podSets = append(podSets, podSet)
+		}
+		return podSets, nil
+	})
+}
+
+// extractRayCluster counts one head pod and, for each worker group, replicas (or else minReplicas) pods per host
+func extractRayCluster(object map[string]interface{}) ([]PodSet, error) {
+	head, err := podSetAt(object, 1, "spec", "headGroupSpec", "template", "spec")
+	if err != nil {
+		return nil, fmt.Errorf("head group: %w", err)
+	}
+	podSets := []PodSet{head}
+
+	workerGroups, _, err := unstructured.NestedSlice(object, "spec", "workerGroupSpecs")
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range workerGroups {
+		workerGroup, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("worker group %d is not an object", i)
+		}
+		replicas := nestedCount(workerGroup, nestedCount(workerGroup, 0, "minReplicas"), "replicas")
+		podSet, err := podSetAt(workerGroup, replicas*nestedCount(workerGroup, 1, "numOfHosts"), "template", "spec")
+		if err != nil {
+			return nil, fmt.Errorf("worker group %d: %w", i, err)
+		}
+		podSets = append(podSets, podSet)
+	}
+	return podSets, nil
+}
+
+// extractJobSet counts, for each replicated job, replicas times the pods of its Job template
+func extractJobSet(object map[string]interface{}) ([]PodSet, error) {
+	replicatedJobs, found, err := unstructured.NestedSlice(object, "spec", "replicatedJobs")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no replicated jobs found at spec.replicatedJobs")
+	}
+	var podSets []PodSet
+	for i, item := range replicatedJobs {
+		replicatedJob, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("replicated job %d is not an object", i)
+		}
+		template, found, err := unstructured.NestedMap(replicatedJob, "template")
+		if err != nil || !found {
+			return nil, fmt.Errorf("replicated job %d has no job template", i)
+		}
+		jobPodSets, err := extractJob(template)
+		if err != nil {
+			return nil, fmt.Errorf("replicated job %d: %w", i, err)
+		}
+		replicas := nestedCount(replicatedJob, 1, "replicas")
+		for _, podSet := range jobPodSets {
+			podSet.Count *= int(replicas)
+			podSets = append(podSets, podSet)
+		}
+	}
+	return podSets, nil
+}
+
+// JSONPathPodSet locates a pod set of a custom kind with JSONPath expressions such as `{.spec.roles[*]}`
+type JSONPathPodSet struct {
+	// Path selects the objects describing pod sets. The whole item is selected when it is empty.
+	Path string `json:"path,omitempty"`
+	// Replicas is the number of pods of a selected object. There is one pod when it is empty or not found.
+	Replicas string `json:"replicas,omitempty"`
+	// PodSpec is the pod spec of a selected object
+	PodSpec string `json:"podSpec"`
+}
+
+// JSONPathExtractor is a ResourceExtractor configured with JSONPath expressions, for in-house kinds
+// that have no built-in extractor.
+type JSONPathExtractor struct {
+	PodSets []JSONPathPodSet `json:"podSets"`
+}
+
+// Validate checks that all the expressions of the extractor parse
+func (e *JSONPathExtractor) Validate() error {
+	if len(e.PodSets) == 0 {
+		return fmt.Errorf("no pod sets")
+	}
+	for i, podSet := range e.PodSets {
+		if podSet.PodSpec == "" {
+			return fmt.Errorf("pod set %d has no podSpec", i)
+		}
+		for _, expression := range []string{podSet.Path, podSet.Replicas, podSet.PodSpec} {
+			if expression == "" {
+				continue
+			}
+			if err := jsonpath.New("extractor").Parse(expression); err != nil {
+				return fmt.Errorf("pod set %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Extract returns a pod set for each object selected by the path of each configured pod set
+func (e *JSONPathExtractor) Extract(object map[string]interface{}) ([]PodSet, error) {
+	var podSets []PodSet
+	for _, podSet := range e.PodSets {
+		selected := []interface{}{object}
+		if podSet.Path != "" {
+			var err error
+			if selected, err = findJSONPath(object, podSet.Path); err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range selected {
+			count := int64(1)
+			if podSet.Replicas != "" {
+				values, err := findJSONPath(item, podSet.Replicas)
+				if err != nil {
+					return nil, err
+				}
+				if len(values) > 0 {
+					count = toCount(values[0], 1)
+				}
+			}
+			values, err := findJSONPath(item, podSet.PodSpec)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != 1 {
+				return nil, fmt.Errorf("%s selects %d pod specs, expected one", podSet.PodSpec, len(values))
+			}
+			podSpec, ok := values[0].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s does not select an object", podSet.PodSpec)
+			}
+			podSets = append(podSets, PodSet{Count: int(count), Spec: podSpec})
+		}
+	}
+	return podSets, nil
+}
+
+// findJSONPath returns the values selected by a JSONPath expression, ignoring missing keys
+func findJSONPath(data interface{}, expression string) ([]interface{}, error) {
+	parser := jsonpath.New("extractor").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, err
+	}
+	results, err := parser.FindResults(data)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	return values, nil
+}
+
+// ResourceExtractorConfig associates a JSONPath extractor to a kind in a resource extractors configuration file
+type ResourceExtractorConfig struct {
+	Group string `json:"group,omitempty"`
+	// Version is optional, all the versions of the kind match when it is empty
+	Version string           `json:"version,omitempty"`
+	Kind    string           `json:"kind"`
+	PodSets []JSONPathPodSet `json:"podSets"`
+}
+
+// LoadResourceExtractors registers the JSONPath extractors listed in a YAML or JSON configuration file.
+// Configured extractors take precedence over the built-in ones of the same kind.
+func LoadResourceExtractors(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var configs []ResourceExtractorConfig
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return fmt.Errorf("parsing resource extractors %s: %w", path, err)
+	}
+	extractors := make(map[schema.GroupVersionKind]ResourceExtractor, len(configs))
+	for _, config := range configs {
+		gvk := schema.GroupVersionKind{Group: config.Group, Version: config.Version, Kind: config.Kind}
+		if config.Kind == "" {
+			return fmt.Errorf("resource extractor with no kind in %s", path)
+		}
+		extractor := &JSONPathExtractor{PodSets: config.PodSets}
+		if err := extractor.Validate(); err != nil {
+			return fmt.Errorf("resource extractor of %s: %w", gvk, err)
+		}
+		extractors[gvk] = extractor
+	}
+	for gvk, extractor := range extractors {
+		RegisterResourceExtractor(gvk, extractor)
+		klog.V(2).Infof("[LoadResourceExtractors] Registered JSONPath resource extractor of %s.", gvk)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+const podTemplate = `{"spec": {"containers": [{"name": "c", "resources": {"requests": {"cpu": "1", "memory": "1Gi", "nvidia.com/gpu": 1}}}]}}`
+
+func genericItem(raw string) *arbv1.AppWrapperGenericResource {
+	return &arbv1.AppWrapperGenericResource{GenericTemplate: runtime.RawExtension{Raw: []byte(raw)}}
+}
+
+// TestBuiltInResourceExtractors validates the pod counts and total demand estimated for the built-in kinds
+func TestBuiltInResourceExtractors(t *testing.T) {
+	var tests = []struct {
+		name string
+		raw  string
+		pods int
+	}{
+		{"pod", `{"apiVersion": "v1", "kind": "Pod", "spec": {"containers": [{"name": "c", "resources": {"requests": {"cpu": "1", "memory": "1Gi", "nvidia.com/gpu": 1}}}]}}`, 1},
+		{"job with parallelism", `{"apiVersion": "batch/v1", "kind": "Job", "spec": {"parallelism": 4, "template": ` + podTemplate + `}}`, 4},
+		{"job bounded by completions", `{"apiVersion": "batch/v1", "kind": "Job", "spec": {"parallelism": 4, "completions": 2, "template": ` + podTemplate + `}}`, 2},
+		{"job without parallelism", `{"apiVersion": "batch/v1", "kind": "Job", "spec": {"template": ` + podTemplate + `}}`, 1},
+		{"pytorchjob", `{"apiVersion": "kubeflow.org/v1", "kind": "PyTorchJob", "spec": {"pytorchReplicaSpecs": {
+			"Master": {"replicas": 1, "template": ` + podTemplate + `}, "Worker": {"replicas": 3, "template": ` + podTemplate + `}}}}`, 4},
+		{"mpijob", `{"apiVersion": "kubeflow.org/v2beta1", "kind": "MPIJob", "spec": {"mpiReplicaSpecs": {
+			"Launcher": {"template": ` + podTemplate + `}, "Worker": {"replicas": 2, "template": ` + podTemplate + `}}}}`, 3},
+		{"raycluster", `{"apiVersion": "ray.io/v1alpha1", "kind": "RayCluster", "spec": {"headGroupSpec": {"template": ` + podTemplate + `},
+			"workerGroupSpecs": [{"replicas": 2, "template": ` + podTemplate + `}, {"minReplicas": 1, "numOfHosts": 2, "template": ` + podTemplate + `}]}}`, 5},
+		{"jobset", `{"apiVersion": "jobset.x-k8s.io/v1alpha2", "kind": "JobSet", "spec": {"replicatedJobs": [
+			{"replicas": 2, "template": {"spec": {"parallelism": 3, "template": ` + podTemplate + `}}}, {"template": {"spec": {"template": ` + podTemplate + `}}}]}}`, 7},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			item := genericItem(test.raw)
+			pods, err := GetListOfPodResourcesFromOneGenericItem(item, "default")
+			assert.NoError(t, err)
+			assert.Len(t, pods, test.pods)
+			for _, pod := range pods {
+				assert.Equal(t, float64(1000), pod.MilliCPU)
+				assert.Equal(t, float64(1<<30), pod.Memory)
+				assert.Equal(t, int64(1), pod.GPU)
+			}
+
+			total, err := GetResources(item, "default")
+			assert.NoError(t, err)
+			assert.Equal(t, float64(1000*test.pods), total.MilliCPU)
+			assert.Equal(t, float64(test.pods)*float64(1<<30), total.Memory)
+			assert.Equal(t, int64(test.pods), total.GPU)
+		})
+	}
+}
+
+// TestResourceExtractorPrecedence validates that custompodresources take precedence over extractors
+// and that kinds without an extractor keep the replicas and template estimation
+func TestResourceExtractorPrecedence(t *testing.T) {
+	item := genericItem(`{"apiVersion": "batch/v1", "kind": "Job", "spec": {"parallelism": 4, "template": ` + podTemplate + `}}`)
+	item.CustomPodResources = []arbv1.CustomPodResourceTemplate{{Replicas: 1, Requests: v1.ResourceList{}}}
+	total, err := GetResources(item, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), total.MilliCPU)
+
+	total, err = GetResources(genericItem(`{"apiVersion": "example.com/v1", "kind": "Widget", "spec": {"replicas": 2, "template": `+podTemplate+`}}`), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2000), total.MilliCPU)
+
+	_, err = GetResources(genericItem(`{"apiVersion": "batch/v1", "kind": "Job", "spec": {}}`), "default")
+	assert.Error(t, err)
+}
+
+// TestJSONPathExtractor validates the configurable extractor of in-house kinds
+func TestJSONPathExtractor(t *testing.T) {
+	config := `
+- group: example.com
+  kind: Trainer
+  podSets:
+  - podSpec: "{.spec.coordinator.spec}"
+  - path: "{.spec.roles[*]}"
+    replicas: "{.count}"
+    podSpec: "{.template.spec}"
+`
+	path := filepath.Join(t.TempDir(), "extractors.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	assert.NoError(t, LoadResourceExtractors(path))
+	defer func() {
+		resourceExtractors.Lock()
+		delete(resourceExtractors.byKind, schema.GroupVersionKind{Group: "example.com", Kind: "Trainer"})
+		resourceExtractors.Unlock()
+	}()
+
+	pods, err := GetListOfPodResourcesFromOneGenericItem(genericItem(`{"apiVersion": "example.com/v1", "kind": "Trainer", "spec": {
+		"coordinator": `+podTemplate+`,
+		"roles": [{"count": 3, "template": `+podTemplate+`}, {"template": `+podTemplate+`}]}}`), "default")
+	assert.NoError(t, err)
+	assert.Len(t, pods, 5)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	assert.NoError(t, os.WriteFile(invalid, []byte("- kind: Trainer\n  podSets:\n  - path: \"{.spec.roles[\"\n    podSpec: \"{.spec}\"\n"), 0o600))
+	assert.Error(t, LoadResourceExtractors(invalid))
+}
@@ -28,6 +28,7 @@ import (
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
@@ -36,7 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -48,19 +49,50 @@ var resourceName = "resourceName"
 var appWrapperKind = arbv1.SchemeGroupVersion.WithKind("AppWrapper")
 
 type GenericResources struct {
-	clients          *kubernetes.Clientset
-	kubeClientConfig *rest.Config
-	arbclients       *clientset.Clientset
+	clients    *kubernetes.Clientset
+	arbclients *clientset.Clientset
+	// mapper maps the kinds of the generic items to resources, the discovery information is cached and refreshed
+	// when a kind is not found, e.g. when a CRD is installed after the controller is started
+	mapper  *restmapper.DeferredDiscoveryRESTMapper
+	dclient dynamic.Interface
+	items   *itemInformers
 }
 
 func NewAppWrapperGenericResource(config *rest.Config) *GenericResources {
+	clients := kubernetes.NewForConfigOrDie(config)
+	dclient := dynamic.NewForConfigOrDie(config)
 	return &GenericResources{
-		clients:          kubernetes.NewForConfigOrDie(config),
-		kubeClientConfig: config,
-		arbclients:       clientset.NewForConfigOrDie(config),
+		clients:    clients,
+		arbclients: clientset.NewForConfigOrDie(config),
+		mapper:     restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clients.Discovery())),
+		dclient:    dclient,
+		items:      newItemInformers(dclient),
 	}
 }
 
+// restMapping returns the resource of a kind, refreshing the cached discovery information if the kind is not found
+func (gr *GenericResources) restMapping(gvk *schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := gr.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		klog.V(4).Infof("[restMapping] Kind %s not found, refreshing the discovery information", gvk)
+		gr.mapper.Reset()
+		mapping, err = gr.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return mapping, err
+}
+
+// AddItemEventHandler registers a handler called when a generic item created for an AppWrapper is added, updated
+// or deleted. Handlers must be registered before the informers are started.
+func (gr *GenericResources) AddItemEventHandler(handler ItemEventHandler) {
+	gr.items.addEventHandler(handler)
+}
+
+// StartItemInformers enables the informers of the generic items until stopCh is closed. The informers are started
+// on first use of a resource, until they are synced the items are read from the API server.
+func (gr *GenericResources) StartItemInformers(stopCh <-chan struct{}) {
+	gr.items.start(stopCh)
+}
+
 func join(strs ...string) string {
 	var result string
 	if strs[0] == "" {
@@ -85,60 +117,20 @@ func (gr *GenericResources) Cleanup(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperG
 	// Default generic resource name
 	name := ""
 
-	namespaced := true
-	// todo:DELETEME	dd := common.KubeClient.Discovery()
-	dd := gr.clients.Discovery()
-	apigroups, err := restmapper.GetAPIGroupResources(dd)
-	if err != nil {
-		klog.Errorf("[Cleanup] Error getting API resources, err=%#v", err)
-		return name, default_gvk, err
-	}
 	ext := awr.GenericTemplate
-	restmapper := restmapper.NewDiscoveryRESTMapper(apigroups)
 	_, gvk, err := unstructured.UnstructuredJSONScheme.Decode(ext.Raw, default_gvk, nil)
 	if err != nil {
 		klog.Errorf("Decoding error, please check your CR! Aborting handling the resource creation, err:  `%v`", err)
 		return name, gvk, err
 	}
 
-	mapping, err := restmapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	mapping, err := gr.restMapping(gvk)
 	if err != nil {
 		klog.Errorf("mapping error from raw object: `%v`", err)
 		return name, gvk, err
 	}
-
-	// todo:DELETEME		restconfig := common.KubeConfig
-	restconfig := gr.kubeClientConfig
-	restconfig.GroupVersion = &schema.GroupVersion{
-		Group:   mapping.GroupVersionKind.Group,
-		Version: mapping.GroupVersionKind.Version,
-	}
-	dclient, err := dynamic.NewForConfig(restconfig)
-	if err != nil {
-		klog.Errorf("[Cleanup] Error creating new dynamic client, err=%#v.", err)
-		return name, gvk, err
-	}
-
-	_, apiresourcelist, err := dd.ServerGroupsAndResources()
-	if err != nil {
-		if derr, ok := err.(*discovery.ErrGroupDiscoveryFailed); ok {
-			klog.Warning("Discovery failed for some groups, %d failing: %v", len(derr.Groups), err)
-		} else {
-			klog.Errorf("Error getting supported groups and resources, err=%#v", err)
-			return name, gvk, err
-		}
-	}
 	rsrc := mapping.Resource
-	for _, apiresourcegroup := range apiresourcelist {
-		if apiresourcegroup.GroupVersion == join(mapping.GroupVersionKind.Group, "/", mapping.GroupVersionKind.Version) {
-			for _, apiresource := range apiresourcegroup.APIResources {
-				if apiresource.Name == mapping.Resource.Resource && apiresource.Kind == mapping.GroupVersionKind.Kind {
-					rsrc = mapping.Resource
-					namespaced = apiresource.Namespaced
-				}
-			}
-		}
-	}
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
 
 	// Unmarshal generic item raw object
 	var unstruct unstructured.Unstructured
@@ -167,7 +159,7 @@ func (gr *GenericResources) Cleanup(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperG
 
 	// Get the resource to see if it exists in the AppWrapper namespace
 	labelSelector := fmt.Sprintf("%s=%s, %s=%s", appwrapperJobName, aw.Name, resourceName, unstruct.GetName())
-	inEtcd, err := dclient.Resource(rsrc).Namespace(aw.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	inEtcd, err := gr.dclient.Resource(rsrc).Namespace(aw.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		return name, gvk, err
 	}
@@ -179,7 +171,7 @@ func (gr *GenericResources) Cleanup(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperG
 			newName = newName[:63]
 		}
 
-		err = deleteObject(namespaced, namespace, newName, rsrc, dclient)
+		err = deleteObject(namespaced, namespace, newName, rsrc, gr.dclient)
 		if err != nil {
 			if !errors.IsNotFound(err) {
 				klog.Errorf("[Cleanup] Error deleting the object `%v`, the error is `%v`.", newName, errors.ReasonForError(err))
@@ -194,9 +186,9 @@ func (gr *GenericResources) Cleanup(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperG
 }
 
 //SyncQueueJob uses dynamic clients to unwrap (spawn) items inside genericItems block, it is used to create resources inside etcd and return errors when
-//unwrapping fails.
+//unwrapping fails. It returns a reference to the object created for the generic item.
 //More context here: https://github.com/project-codeflare/multi-cluster-app-dispatcher/issues/598
-func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperGenericResource) (podList []*v1.Pod, err error) {
+func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWrapperGenericResource) (itemStatus *arbv1.AppWrapperItemStatus, err error) {
 	startTime := time.Now()
 	defer func() {
 		if pErr := recover(); pErr != nil {
@@ -206,38 +198,18 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 	}()
 
 	namespaced := true
-	// todo:DELETEME	dd := common.KubeClient.Discovery()
-	dd := gr.clients.Discovery()
-	apigroups, err := restmapper.GetAPIGroupResources(dd)
-	if err != nil {
-		klog.Errorf("Error getting API resources, err=%#v", err)
-		return []*v1.Pod{}, err
-	}
 	ext := awr.GenericTemplate
-	restmapper := restmapper.NewDiscoveryRESTMapper(apigroups)
 	// versions := &unstructured.Unstructured{}
 	// _, gvk, err := unstructured.UnstructuredJSONScheme.Decode(ext.Raw, nil, versions)
 	_, gvk, err := unstructured.UnstructuredJSONScheme.Decode(ext.Raw, nil, nil)
 	if err != nil {
 		klog.Errorf("Decoding error, please check your CR! Aborting handling the resource creation, err:  `%v`", err)
-		return []*v1.Pod{}, err
+		return nil, err
 	}
-	mapping, err := restmapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	mapping, err := gr.restMapping(gvk)
 	if err != nil {
 		klog.Errorf("mapping error from raw object: `%v`", err)
-		return []*v1.Pod{}, err
-	}
-
-	// todo:DELETEME		restconfig := common.KubeConfig
-	restconfig := gr.kubeClientConfig
-	restconfig.GroupVersion = &schema.GroupVersion{
-		Group:   mapping.GroupVersionKind.Group,
-		Version: mapping.GroupVersionKind.Version,
-	}
-	dclient, err := dynamic.NewForConfig(restconfig)
-	if err != nil {
-		klog.Errorf("Error creating new dynamic client, err=%#v", err)
-		return []*v1.Pod{}, err
+		return nil, err
 	}
 
 	//TODO: Simplified apiresourcelist discovery, the assumption is we will always deploy namespaced objects
@@ -250,11 +222,13 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 	// 		klog.Warning("Discovery failed for some groups, %d failing: %v", len(derr.Groups), err)
 	// 	} else {
 	// 		klog.Errorf("Error getting supported groups and resources, err=%#v", err)
-	// 		return []*v1.Pod{}, err
+	// 		return nil, err
 	// 	}
 	// }
 
 	rsrc := mapping.Resource
+	// watch the items of this resource so that the AppWrapper is evaluated when they change
+	gr.items.watch(rsrc)
 
 	// for _, apiresourcegroup := range apiresourcelist {
 	// 	if apiresourcegroup.GroupVersion == join(mapping.GroupVersionKind.Group, "/", mapping.GroupVersionKind.Version) {
@@ -271,7 +245,7 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 	var blob interface{}
 	if err = json.Unmarshal(ext.Raw, &blob); err != nil {
 		klog.Errorf("Error unmarshalling, err=%#v", err)
-		return []*v1.Pod{}, err
+		return nil, err
 	}
 	ownerRef := metav1.NewControllerRef(aw, appWrapperKind)
 	unstruct.Object = blob.(map[string]interface{}) // set object to the content of the blob after Unmarshalling
@@ -287,7 +261,7 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 		if objectns, ok := metadata["namespace"]; ok {
 			if objectns.(string) != namespace {
 				err := fmt.Errorf("[SyncQueueJob] resource namespace \"%s\" is different from AppWrapper namespace \"%s\"", objectns.(string), namespace)
-				return []*v1.Pod{}, err
+				return nil, err
 			}
 		}
 	}
@@ -309,9 +283,9 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 
 	// Get the resource  to see if it exists
 	labelSelector := fmt.Sprintf("%s=%s, %s=%s", appwrapperJobName, aw.Name, resourceName, unstruct.GetName())
-	inEtcd, err := dclient.Resource(rsrc).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	inEtcd, err := gr.dclient.Resource(rsrc).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
-		return []*v1.Pod{}, err
+		return nil, err
 	}
 
 	// Check to see if object already exists in etcd, if not, create the object.
@@ -324,15 +298,21 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 		//Asumption object is always namespaced
 		//Refer to comment on line 238
 		namespaced = true
-		err = createObject(namespaced, namespace, newName, rsrc, unstruct, dclient)
+		created, err := createObject(namespaced, namespace, newName, rsrc, unstruct, gr.dclient)
 		if err != nil {
 			if errors.IsAlreadyExists(err) {
 				klog.V(4).Infof("%v\n", err.Error())
 			} else {
 				klog.Errorf("Error creating the object `%v`, the error is `%v`", newName, errors.ReasonForError(err))
-				return []*v1.Pod{}, err
+				return nil, err
 			}
 		}
+		if created == nil {
+			// the object already exists, reference it as it is
+			created = &unstruct
+			created.SetNamespace(namespace)
+		}
+		return newItemStatus(created, rsrc), nil
 	}
 
 	// Get the related resources of created object
@@ -345,7 +325,7 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 	// }
 	// if err1 != nil {
 	// 	klog.Errorf("Could not get created resource with error %v", err1)
-	// 	return []*v1.Pod{}, err1
+	// 	return nil, err1
 	// }
 	// thisOwnerRef := metav1.NewControllerRef(thisObj, thisObj.GroupVersionKind())
 
@@ -359,7 +339,28 @@ func (gr *GenericResources) SyncQueueJob(aw *arbv1.AppWrapper, awr *arbv1.AppWra
 	// 	klog.V(10).Infof("[SyncQueueJob] pod %s created from a Generic Item\n", pod.Name)
 	// }
 	// return pods, nil
-	return []*v1.Pod{}, nil
+	return newItemStatus(&inEtcd.Items[0], rsrc), nil
+}
+
+// newItemStatus returns a reference to an object created for a generic item
+func newItemStatus(obj *unstructured.Unstructured, rsrc schema.GroupVersionResource) *arbv1.AppWrapperItemStatus {
+	gvk := obj.GroupVersionKind()
+	return &arbv1.AppWrapperItemStatus{
+		Group:        gvk.Group,
+		Version:      gvk.Version,
+		Kind:         gvk.Kind,
+		Resource:     rsrc.Resource,
+		Name:         obj.GetName(),
+		Namespace:    obj.GetNamespace(),
+		CreationTime: obj.GetCreationTimestamp(),
+		State:        arbv1.AppWrapperItemStateCreated,
+	}
+}
+
+// CleanupItem deletes an object created for a generic item using the reference recorded in the AppWrapper status
+func (gr *GenericResources) CleanupItem(item *arbv1.AppWrapperItemStatus) error {
+	rsrc := schema.GroupVersionResource{Group: item.Group, Version: item.Version, Resource: item.Resource}
+	return deleteObject(len(item.Namespace) > 0, item.Namespace, item.Name, rsrc, gr.dclient)
 }
 
 // checks if object has pod template spec and add new labels
@@ -404,19 +405,11 @@ func addLabelsToPodTemplateField(unstruct *unstructured.Unstructured, labels map
 	return isFound
 }
 
-// checks if object has replicas and containers field
-func hasFields(obj runtime.RawExtension) (hasFields bool, replica float64, containers []v1.Container) {
-	var unstruct unstructured.Unstructured
-	unstruct.Object = make(map[string]interface{})
-	var blob interface{}
-	if err := json.Unmarshal(obj.Raw, &blob); err != nil {
-		klog.Errorf("Error unmarshalling, err=%#v", err)
-		return false, 0, nil
-	}
-	unstruct.Object = blob.(map[string]interface{})
-	spec, isFound, _ := unstructured.NestedMap(unstruct.UnstructuredContent(), "spec")
+// checks if object has replicas and containers field, and returns the pod spec holding the containers
+func hasFields(object map[string]interface{}) (hasFields bool, replica float64, podSpec map[string]interface{}) {
+	spec, isFound, _ := unstructured.NestedMap(object, "spec")
 	if !isFound {
-		klog.Warningf("[hasFields] No spec field found in raw object: %#v", unstruct.UnstructuredContent())
+		klog.Warningf("[hasFields] No spec field found in raw object: %#v", object)
 	}
 
 	replicas, isFound, _ := unstructured.NestedFloat64(spec, "replicas")
@@ -435,52 +428,58 @@ func hasFields(obj runtime.RawExtension) (hasFields bool, replica float64, conta
 		subspec, isFound, _ = unstructured.NestedMap(template, "spec")
 	}
 
-	containerList, isFound, _ := unstructured.NestedSlice(subspec, "containers")
+	_, isFound, _ = unstructured.NestedSlice(subspec, "containers")
 	if !isFound {
 		klog.Warningf("[hasFields] No containers field found in raw object: %#v", subspec)
 		return false, 0, nil
 	}
-	objContainers := make([]v1.Container, len(containerList))
-	for _, container := range containerList {
-		marshal, _ := json.Marshal(container)
-		unmarshal := v1.Container{}
-		_ = json.Unmarshal(marshal, &unmarshal)
-		objContainers = append(objContainers, unmarshal)
+	return isFound, replicas, subspec
+}
+
+// itemObject returns the unstructured content of a generic item and the namespace of its pods: the namespace
+// of the item if set, else the given namespace of its AppWrapper
+func itemObject(awr *arbv1.AppWrapperGenericResource, namespace string) (map[string]interface{}, string, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal(awr.GenericTemplate.Raw, &object); err != nil {
+		return nil, namespace, err
+	}
+	if itemNamespace, _, _ := unstructured.NestedString(object, "metadata", "namespace"); itemNamespace != "" {
+		namespace = itemNamespace
 	}
-	return isFound, replicas, objContainers
+	return object, namespace, nil
 }
 
-func createObject(namespaced bool, namespace string, name string, rsrc schema.GroupVersionResource, unstruct unstructured.Unstructured, dclient dynamic.Interface) (erro error) {
+func createObject(namespaced bool, namespace string, name string, rsrc schema.GroupVersionResource, unstruct unstructured.Unstructured, dclient dynamic.Interface) (created *unstructured.Unstructured, erro error) {
 	var err error
 	if !namespaced {
 		res := dclient.Resource(rsrc)
-		_, err = res.Create(context.Background(), &unstruct, metav1.CreateOptions{})
+		created, err = res.Create(context.Background(), &unstruct, metav1.CreateOptions{})
 		if err != nil {
 			if errors.IsAlreadyExists(err) {
 				klog.Errorf("%v\n", err.Error())
-				return nil
+				return nil, nil
 			} else {
 				klog.Errorf("Error creating the object `%v`, the error is `%v`", name, errors.ReasonForError(err))
-				return err
+				return nil, err
 			}
 		} else {
 			klog.V(4).Infof("Resource `%v` created\n", name)
-			return nil
+			return created, nil
 		}
 	} else {
 		res := dclient.Resource(rsrc).Namespace(namespace)
-		_, err = res.Create(context.Background(), &unstruct, metav1.CreateOptions{})
+		created, err = res.Create(context.Background(), &unstruct, metav1.CreateOptions{})
 		if err != nil {
 			if errors.IsAlreadyExists(err) {
 				klog.Errorf("%v\n", err.Error())
-				return nil
+				return nil, nil
 			} else {
 				klog.Errorf("Error creating the object `%v`, the error is `%v`", name, errors.ReasonForError(err))
-				return err
+				return nil, err
 			}
 		} else {
 			klog.V(4).Infof("Resource `%v` created\n", name)
-			return nil
+			return created, nil
 
 		}
 	}
@@ -507,19 +506,44 @@ func deleteObject(namespaced bool, namespace string, name string, rsrc schema.Gr
 	}
 }
 
-func GetListOfPodResourcesFromOneGenericItem(awr *arbv1.AppWrapperGenericResource) (resource []*clusterstateapi.Resource, er error) {
+// GetListOfPodResourcesFromOneGenericItem returns the requests of each pod of a generic item of an AppWrapper
+// of the given namespace, whose LimitRange defaults apply to the containers that request nothing.
+func GetListOfPodResourcesFromOneGenericItem(awr *arbv1.AppWrapperGenericResource, namespace string) (resource []*clusterstateapi.Resource, er error) {
 	var podResourcesList []*clusterstateapi.Resource
 
 	podTotalresource := clusterstateapi.EmptyResource()
 	var err error
 	err = nil
 	if awr.GenericTemplate.Raw != nil {
-		hasContainer, replicas, containers := hasFields(awr.GenericTemplate)
+		object, namespace, err := itemObject(awr, namespace)
+		if err != nil {
+			return podResourcesList, err
+		}
+		if len(awr.CustomPodResources) == 0 {
+			podSets, found, err := extractPodSets(object)
+			if found && err == nil {
+				for _, podSet := range podSets {
+					requests, err := podRequests(podSet.Spec, namespace)
+					if err != nil {
+						return nil, err
+					}
+					for i := 0; i < podSet.Count; i++ {
+						podResourcesList = append(podResourcesList, requests)
+					}
+				}
+				klog.V(8).Infof("[GetListOfPodResourcesFromOneGenericItem] Requested %d pods from resource extractor.\n", len(podResourcesList))
+				return podResourcesList, nil
+			}
+			if err != nil {
+				klog.Warningf("[GetListOfPodResourcesFromOneGenericItem] Falling back to containers, err=%v", err)
+			}
+		}
+		hasContainer, replicas, podSpec := hasFields(object)
 		if hasContainer {
 			// Add up all the containers in a pod
-			for _, container := range containers {
-				res := getContainerResources(container, 1)
-				podTotalresource = podTotalresource.Add(res)
+			podTotalresource, err = podRequests(podSpec, namespace)
+			if err != nil {
+				return podResourcesList, err
 			}
 			klog.V(8).Infof("[GetListOfPodResourcesFromOneGenericItem] Requested total pod allocation resource from containers `%v`.\n", podTotalresource)
 		} else {
@@ -541,7 +565,9 @@ func GetListOfPodResourcesFromOneGenericItem(awr *arbv1.AppWrapperGenericResourc
 	return podResourcesList, err
 }
 
-func GetResources(awr *arbv1.AppWrapperGenericResource) (resource *clusterstateapi.Resource, er error) {
+// GetResources returns the total requests of the pods of a generic item of an AppWrapper of the given namespace,
+// whose LimitRange defaults apply to the containers that request nothing.
+func GetResources(awr *arbv1.AppWrapperGenericResource, namespace string) (resource *clusterstateapi.Resource, er error) {
 
 	totalresource := clusterstateapi.EmptyResource()
 	var err error
@@ -556,14 +582,35 @@ func GetResources(awr *arbv1.AppWrapperGenericResource) (resource *clusterstatea
 			klog.V(4).Infof("[GetResources] Requested total allocation resource from custompodresources `%v`.\n", totalresource)
 			return totalresource, err
 		}
-		hasContainer, replicas, containers := hasFields(awr.GenericTemplate)
+		object, namespace, err := itemObject(awr, namespace)
+		if err != nil {
+			return totalresource, err
+		}
+		podSets, found, err := extractPodSets(object)
+		if found {
+			if err != nil {
+				klog.Warningf("[GetResources] Failure extracting resources, err=%v", err)
+				return totalresource, err
+			}
+			for _, podSet := range podSets {
+				requests, err := podRequests(podSet.Spec, namespace)
+				if err != nil {
+					return totalresource, err
+				}
+				totalresource = totalresource.Add(multiplyResource(requests, float64(podSet.Count)))
+			}
+			klog.V(4).Infof("[GetResources] Requested total allocation resource from resource extractor `%v`.\n", totalresource)
+			return totalresource, nil
+		}
+		hasContainer, replicas, podSpec := hasFields(object)
 		if hasContainer {
-			for _, item := range containers {
-				res := getContainerResources(item, replicas)
-				totalresource = totalresource.Add(res)
+			requests, err := podRequests(podSpec, namespace)
+			if err != nil {
+				return totalresource, err
 			}
+			totalresource = multiplyResource(requests, replicas)
 			klog.V(4).Infof("[GetResources] Requested total allocation resource from containers `%v`.\n", totalresource)
-			return totalresource, err
+			return totalresource, nil
 		}
 
 	} else {
@@ -622,92 +669,171 @@ func getContainerResources(container v1.Container, replicas float64) *clustersta
 	return req
 }
 
-// returns status of an item present in etcd
+// IsItemCompleted returns true when the item present in etcd reports one of the condition types
+// listed in its completionstatus field with a status of True.
 func (gr *GenericResources) IsItemCompleted(awgr *arbv1.AppWrapperGenericResource, namespace string, appwrapperName string, genericItemName string) (completed bool) {
-	dd := gr.clients.Discovery()
-	apigroups, err := restmapper.GetAPIGroupResources(dd)
-	if err != nil {
-		klog.Errorf("[IsItemCompleted] Error getting API resources, err=%#v", err)
+	return gr.isItemInConditionState(awgr, awgr.CompletionStatus, namespace, appwrapperName, genericItemName, "[IsItemCompleted]")
+}
+
+// IsItemFailed returns true when the item present in etcd reports one of the condition types
+// listed in its failurestatus field with a status of True.
+func (gr *GenericResources) IsItemFailed(awgr *arbv1.AppWrapperGenericResource, namespace string, appwrapperName string, genericItemName string) (failed bool) {
+	return gr.isItemInConditionState(awgr, awgr.FailureStatus, namespace, appwrapperName, genericItemName, "[IsItemFailed]")
+}
+
+// returns true if the item present in etcd has a True condition whose type is listed in conditionTypes
+func (gr *GenericResources) isItemInConditionState(awgr *arbv1.AppWrapperGenericResource, conditionTypes string, namespace string, appwrapperName string, genericItemName string, caller string) bool {
+	if len(conditionTypes) == 0 {
 		return false
 	}
-	restmapper := restmapper.NewDiscoveryRESTMapper(apigroups)
-	_, gvk, err := unstructured.UnstructuredJSONScheme.Decode(awgr.GenericTemplate.Raw, nil, nil)
-	if err != nil {
-		klog.Errorf("[IsItemCompleted] Decoding error, please check your CR! Aborting handling the resource creation, err:  `%v`", err)
+	job := gr.getItemFromEtcd(awgr, namespace, appwrapperName, genericItemName, caller)
+	if job == nil {
 		return false
 	}
-
-	mapping, err := restmapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	inState, err := isInConditionState(job.Object, conditionTypes)
 	if err != nil {
-		klog.Errorf("[IsItemCompleted] mapping error from raw object: `%v`", err)
-		return false
+		klog.Errorf("%s Error processing the status conditions of unstructured object %v in namespace %v with labels %v, err=%v", caller, job.GetName(), job.GetNamespace(), job.GetLabels(), err)
+	}
+	return inState
+}
+
+// isInConditionState returns true if the unstructured item has a True status condition whose type is listed in
+// conditionTypes. An item without status or conditions, as right after its creation, is not in any condition state.
+func isInConditionState(item map[string]interface{}, conditionTypes string) (bool, error) {
+	conditions, found, err := unstructured.NestedSlice(item, "status", "conditions")
+	if err != nil || !found {
+		return false, err
 	}
-	restconfig := gr.kubeClientConfig
-	restconfig.GroupVersion = &schema.GroupVersion{
-		Group:   mapping.GroupVersionKind.Group,
-		Version: mapping.GroupVersionKind.Version,
+	return hasTrueCondition(conditions, conditionTypes), nil
+}
+
+// IsItemReady returns true if the item present in etcd satisfies the readiness predicate of the generic item.
+// It also returns the creation time of the item, which is zero if the item is not found.
+func (gr *GenericResources) IsItemReady(awgr *arbv1.AppWrapperGenericResource, namespace string, appwrapperName string, genericItemName string) (ready bool, creationTime time.Time) {
+	item := gr.getItemFromEtcd(awgr, namespace, appwrapperName, genericItemName, "[IsItemReady]")
+	if item == nil {
+		return false, time.Time{}
 	}
-	rsrc := mapping.Resource
-	dclient, err := dynamic.NewForConfig(restconfig)
+	return isReady(item.Object, awgr.Readiness), item.GetCreationTimestamp().Time
+}
+
+// isReady evaluates the readiness predicate against an unstructured item, a nil predicate is always satisfied
+func isReady(item map[string]interface{}, readiness *arbv1.ItemReadiness) bool {
+	if readiness == nil {
+		return true
+	}
+	if readiness.ReadyReplicas != nil {
+		readyReplicas, found, err := unstructured.NestedFieldNoCopy(item, "status", "readyReplicas")
+		if err != nil || !found {
+			readyReplicas = int64(0)
+		}
+		var replicas int64
+		switch value := readyReplicas.(type) {
+		case int64:
+			replicas = value
+		case float64:
+			replicas = int64(value)
+		}
+		if replicas < int64(*readiness.ReadyReplicas) {
+			return false
+		}
+	}
+	if len(readiness.ConditionTypes) > 0 {
+		conditions, found, err := unstructured.NestedSlice(item, "status", "conditions")
+		if err != nil || !found || !hasTrueCondition(conditions, readiness.ConditionTypes) {
+			return false
+		}
+	}
+	return true
+}
+
+// getItemFromEtcd returns the item created for the generic item, nil if the item is not found. The item is read
+// from the informer cache once it is synced and must not be modified.
+func (gr *GenericResources) getItemFromEtcd(awgr *arbv1.AppWrapperGenericResource, namespace string, appwrapperName string, genericItemName string, caller string) *unstructured.Unstructured {
+	_, gvk, err := unstructured.UnstructuredJSONScheme.Decode(awgr.GenericTemplate.Raw, nil, nil)
 	if err != nil {
-		klog.Errorf("[IsItemCompleted] Error creating new dynamic client, err %v", err)
-		return false
+		klog.Errorf("%s Decoding error, please check your CR! Aborting handling the resource creation, err:  `%v`", caller, err)
+		return nil
 	}
 
+	mapping, err := gr.restMapping(gvk)
+	if err != nil {
+		klog.Errorf("%s mapping error from raw object: `%v`", caller, err)
+		return nil
+	}
+	rsrc := mapping.Resource
+	if lister := gr.items.lister(rsrc); lister != nil {
+		var obj runtime.Object
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			obj, err = lister.ByNamespace(namespace).Get(genericItemName)
+		} else {
+			obj, err = lister.Get(genericItemName)
+		}
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				klog.Errorf("%s Error getting object from the informer cache: %v", caller, err)
+			}
+			return nil
+		}
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok || !isOwnedItem(item, appwrapperName, genericItemName, caller) {
+			return nil
+		}
+		return item
+	}
 	labelSelector := fmt.Sprintf("%s=%s", appwrapperJobName, appwrapperName)
-	inEtcd, err := dclient.Resource(rsrc).Namespace(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	inEtcd, err := gr.dclient.Resource(rsrc).Namespace(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
-		klog.Errorf("[IsItemCompleted] Error listing object: %v", err)
-		return false
+		klog.Errorf("%s Error listing object: %v", caller, err)
+		return nil
 	}
 
-	for _, job := range inEtcd.Items {
-		// job.UnstructuredContent() has status information
-		unstructuredObjectName := job.GetName()
-		if unstructuredObjectName != genericItemName {
-			continue
+	for i := range inEtcd.Items {
+		if isOwnedItem(&inEtcd.Items[i], appwrapperName, genericItemName, caller) {
+			return &inEtcd.Items[i]
 		}
-		jobOwnerRef := job.GetOwnerReferences()
-		validAwOwnerRef := false
-		for _, val := range jobOwnerRef {
-			if val.Name == appwrapperName {
-				validAwOwnerRef = true
-			}
+	}
+	return nil
+}
+
+// isOwnedItem returns true if the object is the generic item with the specified name owned by the AppWrapper
+func isOwnedItem(job *unstructured.Unstructured, appwrapperName string, genericItemName string, caller string) bool {
+	unstructuredObjectName := job.GetName()
+	if unstructuredObjectName != genericItemName {
+		return false
+	}
+	jobOwnerRef := job.GetOwnerReferences()
+	validAwOwnerRef := false
+	for _, val := range jobOwnerRef {
+		if val.Name == appwrapperName {
+			validAwOwnerRef = true
 		}
-		if !validAwOwnerRef {
-			klog.Warningf("[IsItemCompleted] Item owner name %v does match appwrappper name %v in namespace %v", unstructuredObjectName, appwrapperName, namespace)
+	}
+	if !validAwOwnerRef {
+		klog.Warningf("%s Item owner name %v does match appwrappper name %v in namespace %v", caller, unstructuredObjectName, appwrapperName, job.GetNamespace())
+		return false
+	}
+	return job.UnstructuredContent() != nil
+}
+
+// hasTrueCondition returns true if one of the unstructured conditions has a type listed in the
+// comma-separated conditionTypes and a status of True. Types are compared ignoring case.
+func hasTrueCondition(conditions []interface{}, conditionTypes string) bool {
+	userSpecifiedConditions := strings.Split(conditionTypes, ",")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
 			continue
 		}
-
-		// check with a false status field
-		// check also conditions object
-		jobMap := job.UnstructuredContent()
-		if jobMap == nil {
+		if fmt.Sprint(condition["status"]) != string(v1.ConditionTrue) {
 			continue
 		}
-
-		if job.Object["status"] != nil {
-			status := job.Object["status"].(map[string]interface{})
-			if status["conditions"] != nil {
-				conditions, ok := job.Object["status"].(map[string]interface{})["conditions"].([]interface{})
-				// if condition not found skip for this interation
-				if !ok {
-					klog.Errorf("[IsItemCompleted] Error processing of unstructured object %v in namespace %v with labels %v, err: %v", job.GetName(), job.GetNamespace(), job.GetLabels(), err)
-					continue
-				}
-				for _, item := range conditions {
-					completionType := fmt.Sprint(item.(map[string]interface{})["type"])
-					// Move this to utils package?
-					userSpecfiedCompletionConditions := strings.Split(awgr.CompletionStatus, ",")
-					for _, condition := range userSpecfiedCompletionConditions {
-						if strings.Contains(strings.ToLower(completionType), strings.ToLower(condition)) {
-							return true
-						}
-					}
-				}
+		conditionType := fmt.Sprint(condition["type"])
+		for _, userCondition := range userSpecifiedConditions {
+			userCondition = strings.TrimSpace(userCondition)
+			if len(userCondition) > 0 && strings.EqualFold(conditionType, userCondition) {
+				return true
 			}
-		} else {
-			klog.Errorf("[IsItemCompleted] Found item with name %v that has status nil in namespace %v with labels %v", job.GetName(), job.GetNamespace(), job.GetLabels())
 		}
 	}
 	return false
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+)
+
+func condition(condType string, status string) interface{} {
+	return map[string]interface{}{"type": condType, "status": status}
+}
+
+// TestHasTrueCondition validates that only conditions with an exact type match and a True status are considered
+func TestHasTrueCondition(t *testing.T) {
+	var tests = []struct {
+		name           string
+		conditions     []interface{}
+		conditionTypes string
+		expectedValue  bool
+	}{
+		{"no conditions", nil, "Complete", false},
+		{"matching type with status True", []interface{}{condition("Complete", "True")}, "Complete", true},
+		{"matching type with status False", []interface{}{condition("Failed", "False")}, "Failed", false},
+		{"matching type with status Unknown", []interface{}{condition("Failed", "Unknown")}, "Failed", false},
+		{"substring of type is not a match", []interface{}{condition("FailedScheduling", "True")}, "Failed", false},
+		{"type is matched ignoring case", []interface{}{condition("Complete", "True")}, "complete", true},
+		{"one of several types", []interface{}{condition("Succeeded", "True")}, "Complete, Succeeded", true},
+		{"first condition false second true", []interface{}{condition("Failed", "False"), condition("Complete", "True")}, "Complete,Failed", true},
+		{"empty condition types", []interface{}{condition("Complete", "True")}, "", false},
+		{"malformed condition", []interface{}{"Complete"}, "Complete", false},
+	}
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedValue, hasTrueCondition(tc.conditions, tc.conditionTypes))
+		})
+	}
+}
+
+// TestIsInConditionState validates that items without a status or with a malformed status are not in any condition state
+func TestIsInConditionState(t *testing.T) {
+	var tests = []struct {
+		name          string
+		item          map[string]interface{}
+		expectedValue bool
+		expectedError bool
+	}{
+		{"no status", map[string]interface{}{"metadata": map[string]interface{}{"name": "job"}}, false, false},
+		{"status without conditions", map[string]interface{}{"status": map[string]interface{}{"active": int64(1)}}, false, false},
+		{"status is not a map", map[string]interface{}{"status": "Running"}, false, true},
+		{"conditions are not a list", map[string]interface{}{"status": map[string]interface{}{"conditions": "Complete"}}, false, true},
+		{"condition not true", map[string]interface{}{"status": map[string]interface{}{"conditions": []interface{}{condition("Complete", "False")}}}, false, false},
+		{"condition true", map[string]interface{}{"status": map[string]interface{}{"conditions": []interface{}{condition("Complete", "True")}}}, true, false},
+	}
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			inState, err := isInConditionState(tc.item, "Complete")
+			assert.Equal(t, tc.expectedValue, inState)
+			assert.Equal(t, tc.expectedError, err != nil)
+		})
+	}
+}
+
+// TestIsReady validates the evaluation of the readiness predicate of generic items
+func TestIsReady(t *testing.T) {
+	two := int32(2)
+	item := func(status map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"status": status}
+	}
+	var tests = []struct {
+		name          string
+		item          map[string]interface{}
+		readiness     *arbv1.ItemReadiness
+		expectedValue bool
+	}{
+		{"no readiness predicate", item(nil), nil, true},
+		{"enough ready replicas", item(map[string]interface{}{"readyReplicas": int64(2)}), &arbv1.ItemReadiness{ReadyReplicas: &two}, true},
+		{"ready replicas as float", item(map[string]interface{}{"readyReplicas": float64(3)}), &arbv1.ItemReadiness{ReadyReplicas: &two}, true},
+		{"too few ready replicas", item(map[string]interface{}{"readyReplicas": int64(1)}), &arbv1.ItemReadiness{ReadyReplicas: &two}, false},
+		{"no ready replicas reported", item(map[string]interface{}{}), &arbv1.ItemReadiness{ReadyReplicas: &two}, false},
+		{"ready condition", item(map[string]interface{}{"conditions": []interface{}{condition("Available", "True")}}), &arbv1.ItemReadiness{ConditionTypes: "Available"}, true},
+		{"ready condition not true", item(map[string]interface{}{"conditions": []interface{}{condition("Available", "False")}}), &arbv1.ItemReadiness{ConditionTypes: "Available"}, false},
+		{"no conditions reported", item(map[string]interface{}{}), &arbv1.ItemReadiness{ConditionTypes: "Available"}, false},
+		{"both predicates satisfied", item(map[string]interface{}{"readyReplicas": int64(2), "conditions": []interface{}{condition("Available", "True")}}),
+			&arbv1.ItemReadiness{ReadyReplicas: &two, ConditionTypes: "Available"}, true},
+		{"only one predicate satisfied", item(map[string]interface{}{"readyReplicas": int64(2)}), &arbv1.ItemReadiness{ReadyReplicas: &two, ConditionTypes: "Available"}, false},
+	}
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedValue, isReady(tc.item, tc.readiness))
+		})
+	}
+}
+
+// TestNewItemStatus validates the reference recorded for an object created for a generic item
+func TestNewItemStatus(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"})
+	obj.SetName("job-1")
+	obj.SetNamespace("default")
+	creationTime := metav1.Unix(1700000000, 0)
+	obj.SetCreationTimestamp(creationTime)
+
+	itemStatus := newItemStatus(obj, schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"})
+	assert.Equal(t, &arbv1.AppWrapperItemStatus{
+		Group:        "batch",
+		Version:      "v1",
+		Kind:         "Job",
+		Resource:     "jobs",
+		Name:         "job-1",
+		Namespace:    "default",
+		CreationTime: creationTime,
+		State:        arbv1.AppWrapperItemStateCreated,
+	}, itemStatus)
+}
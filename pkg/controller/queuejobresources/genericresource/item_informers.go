@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// itemInformerResyncPeriod is the period at which the informers of the generic items replay the items they cache,
+// so that the AppWrappers are evaluated again even when their items do not change
+const itemInformerResyncPeriod = 5 * time.Minute
+
+// ItemEventHandler is called with the namespace and the name of the AppWrapper owning a generic item when the
+// item is added, updated or deleted
+type ItemEventHandler func(namespace string, appwrapperName string)
+
+// itemInformers lazily starts a dynamic informer for every resource used by generic items. The informers only
+// cache the objects labeled with the name of their AppWrapper.
+type itemInformers struct {
+	factory dynamicinformer.DynamicSharedInformerFactory
+
+	mutex     sync.Mutex
+	stopCh    <-chan struct{}
+	informers map[schema.GroupVersionResource]informers.GenericInformer
+	handlers  []ItemEventHandler
+}
+
+func newItemInformers(client dynamic.Interface) *itemInformers {
+	return &itemInformers{
+		factory: dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, itemInformerResyncPeriod, metav1.NamespaceAll,
+			func(options *metav1.ListOptions) {
+				options.LabelSelector = appwrapperJobName
+			}),
+		informers: make(map[schema.GroupVersionResource]informers.GenericInformer),
+	}
+}
+
+// addEventHandler registers a handler of the events of the generic items, it must be called before start
+func (ii *itemInformers) addEventHandler(handler ItemEventHandler) {
+	if ii == nil {
+		return
+	}
+	ii.mutex.Lock()
+	defer ii.mutex.Unlock()
+	ii.handlers = append(ii.handlers, handler)
+}
+
+// start enables the informers, which are created on first use until stopCh is closed
+func (ii *itemInformers) start(stopCh <-chan struct{}) {
+	if ii == nil {
+		return
+	}
+	ii.mutex.Lock()
+	defer ii.mutex.Unlock()
+	ii.stopCh = stopCh
+	ii.factory.Start(stopCh)
+}
+
+// watch returns the informer of a resource, starting it if needed. It returns nil when the informers are not started.
+func (ii *itemInformers) watch(gvr schema.GroupVersionResource) informers.GenericInformer {
+	if ii == nil {
+		return nil
+	}
+	ii.mutex.Lock()
+	defer ii.mutex.Unlock()
+	if ii.stopCh == nil {
+		return nil
+	}
+	informer, found := ii.informers[gvr]
+	if !found {
+		informer = ii.factory.ForResource(gvr)
+		informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    ii.onEvent,
+			UpdateFunc: func(_, obj interface{}) { ii.onEvent(obj) },
+			DeleteFunc: ii.onEvent,
+		})
+		ii.informers[gvr] = informer
+		ii.factory.Start(ii.stopCh)
+		klog.V(2).Infof("[itemInformers] Started the informer of the generic items of resource %s", gvr)
+	}
+	return informer
+}
+
+// lister returns the lister of a resource once its informer is synced, nil otherwise
+func (ii *itemInformers) lister(gvr schema.GroupVersionResource) cache.GenericLister {
+	informer := ii.watch(gvr)
+	if informer == nil || !informer.Informer().HasSynced() {
+		return nil
+	}
+	return informer.Lister()
+}
+
+func (ii *itemInformers) onEvent(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	item, err := meta.Accessor(obj)
+	if err != nil {
+		klog.Errorf("[itemInformers] Unexpected object in the event of a generic item: %v", err)
+		return
+	}
+	appwrapperName, found := item.GetLabels()[appwrapperJobName]
+	if !found {
+		return
+	}
+	ii.mutex.Lock()
+	handlers := ii.handlers
+	ii.mutex.Unlock()
+	for _, handler := range handlers {
+		handler(item.GetNamespace(), appwrapperName)
+	}
+}
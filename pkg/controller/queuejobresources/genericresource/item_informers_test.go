@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var jobsResource = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func newJob(name string, labels map[string]string) *unstructured.Unstructured {
+	job := &unstructured.Unstructured{}
+	job.SetAPIVersion("batch/v1")
+	job.SetKind("Job")
+	job.SetNamespace("default")
+	job.SetName(name)
+	job.SetLabels(labels)
+	return job
+}
+
+// TestItemInformers validates that the informers are started on first use, that their cache is used once synced
+// and that the handlers are called with the AppWrapper of the items
+func TestItemInformers(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{jobsResource: "JobList"},
+		newJob("job-1", map[string]string{appwrapperJobName: "aw-1"}))
+	ii := newItemInformers(client)
+	events := make(chan string, 10)
+	ii.addEventHandler(func(namespace string, appwrapperName string) {
+		events <- namespace + "/" + appwrapperName
+	})
+
+	assert.Nil(t, ii.watch(jobsResource), "informers are not created before start")
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	ii.start(stopCh)
+	assert.Eventually(t, func() bool { return ii.lister(jobsResource) != nil }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "default/aw-1", <-events)
+
+	obj, err := ii.lister(jobsResource).ByNamespace("default").Get("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", obj.(*unstructured.Unstructured).GetName())
+
+	// items without the label of an AppWrapper do not trigger the handlers
+	_, err = client.Resource(jobsResource).Namespace("default").Create(context.Background(), newJob("job-2", nil), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = client.Resource(jobsResource).Namespace("default").Create(context.Background(),
+		newJob("job-3", map[string]string{appwrapperJobName: "aw-3"}), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "default/aw-3", <-events)
+	assert.Empty(t, events)
+}
+
+// TestNilItemInformers validates that generic resources without informers read the items from the API server
+func TestNilItemInformers(t *testing.T) {
+	var ii *itemInformers
+	ii.addEventHandler(func(string, string) {})
+	ii.start(make(chan struct{}))
+	assert.Nil(t, ii.watch(jobsResource))
+	assert.Nil(t, ii.lister(jobsResource))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+var limitRanges struct {
+	sync.RWMutex
+	lister corelisters.LimitRangeLister
+}
+
+// SetLimitRangeLister makes demand estimation apply the container defaults of the LimitRanges of the namespace
+// of the generic items, as the LimitRanger admission plugin does when their pods are created.
+func SetLimitRangeLister(lister corelisters.LimitRangeLister) {
+	limitRanges.Lock()
+	defer limitRanges.Unlock()
+	limitRanges.lister = lister
+}
+
+// containerDefaults are the resources set by LimitRanges on the containers that do not specify them
+type containerDefaults struct {
+	requests v1.ResourceList
+	limits   v1.ResourceList
+}
+
+func limitRangeDefaults(namespace string) *containerDefaults {
+	limitRanges.RLock()
+	lister := limitRanges.lister
+	limitRanges.RUnlock()
+	if lister == nil || namespace == "" {
+		return nil
+	}
+	items, err := lister.LimitRanges(namespace).List(labels.Everything())
+	if err != nil {
+		klog.Warningf("[limitRangeDefaults] Failure listing the LimitRanges of namespace %s, err=%v", namespace, err)
+		return nil
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	// The LimitRanger applies the first default found for a resource, in the order the LimitRanges are listed
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	defaults := &containerDefaults{requests: v1.ResourceList{}, limits: v1.ResourceList{}}
+	for _, limitRange := range items {
+		for _, limit := range limitRange.Spec.Limits {
+			if limit.Type != v1.LimitTypeContainer {
+				continue
+			}
+			// The API server defaults the default requests of a LimitRange to its default limits
+			defaults.requests = withDefaults(defaults.requests, withDefaults(limit.DefaultRequest, limit.Default))
+			defaults.limits = withDefaults(defaults.limits, limit.Default)
+		}
+	}
+	return defaults
+}
+
+// withDefaults returns the resources completed with the defaults of the resources they do not list
+func withDefaults(resources v1.ResourceList, defaults v1.ResourceList) v1.ResourceList {
+	merged := make(v1.ResourceList, len(resources)+len(defaults))
+	for name, quantity := range defaults {
+		merged[name] = quantity
+	}
+	for name, quantity := range resources {
+		merged[name] = quantity
+	}
+	return merged
+}
+
+// podRequests returns the effective requests of a pod with the given unstructured spec, computed as the
+// kube-scheduler does: the largest of the sum of the containers and sidecar init containers and of each
+// init container with the sidecars started before it, plus the pod overhead.
+func podRequests(podSpec map[string]interface{}, namespace string) (*clusterstateapi.Resource, error) {
+	var spec v1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpec, &spec); err != nil {
+		return nil, err
+	}
+	// The restartPolicy of sidecar init containers is newer than the vendored API, so it is read from the raw spec
+	initContainers, _, _ := unstructured.NestedSlice(podSpec, "initContainers")
+	defaults := limitRangeDefaults(namespace)
+
+	requests := clusterstateapi.EmptyResource()
+	for _, container := range spec.Containers {
+		requests.Add(containerRequests(container, defaults))
+	}
+	sidecars := clusterstateapi.EmptyResource()
+	initRequests := clusterstateapi.EmptyResource()
+	for i, container := range spec.InitContainers {
+		res := containerRequests(container, defaults)
+		if i < len(initContainers) && isSidecar(initContainers[i]) {
+			requests.Add(res)
+			sidecars.Add(res)
+			maxResource(initRequests, sidecars)
+		} else {
+			maxResource(initRequests, res.Add(sidecars))
+		}
+	}
+	maxResource(requests, initRequests)
+	return requests.Add(clusterstateapi.NewResource(spec.Overhead)), nil
+}
+
+// containerRequests returns the requests of a container once defaulted: missing requests are set to the limits
+// by the API server, then to the LimitRange defaults by the LimitRanger admission plugin.
+func containerRequests(container v1.Container, defaults *containerDefaults) *clusterstateapi.Resource {
+	container.Resources.Requests = withDefaults(container.Resources.Requests, container.Resources.Limits)
+	if defaults != nil {
+		container.Resources.Requests = withDefaults(container.Resources.Requests, defaults.requests)
+		container.Resources.Limits = withDefaults(container.Resources.Limits, defaults.limits)
+	}
+	return getContainerResources(container, 1)
+}
+
+func isSidecar(initContainer interface{}) bool {
+	container, ok := initContainer.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	restartPolicy, _, _ := unstructured.NestedString(container, "restartPolicy")
+	return restartPolicy == "Always"
+}
+
+// maxResource sets each resource of r to the largest of r and rr
+func maxResource(r *clusterstateapi.Resource, rr *clusterstateapi.Resource) {
+	if rr.MilliCPU > r.MilliCPU {
+		r.MilliCPU = rr.MilliCPU
+	}
+	if rr.Memory > r.Memory {
+		r.Memory = rr.Memory
+	}
+	if rr.GPU > r.GPU {
+		r.GPU = rr.GPU
+	}
+}
+
+// multiplyResource returns the resources of count identical pods
+func multiplyResource(r *clusterstateapi.Resource, count float64) *clusterstateapi.Resource {
+	return &clusterstateapi.Resource{
+		MilliCPU: r.MilliCPU * count,
+		Memory:   r.Memory * count,
+		GPU:      r.GPU * int64(count),
+	}
+}
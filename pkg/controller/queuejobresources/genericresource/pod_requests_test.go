@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericresource
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+func container(name string, cpu string, memory string) map[string]interface{} {
+	requests := map[string]interface{}{}
+	if cpu != "" {
+		requests["cpu"] = cpu
+	}
+	if memory != "" {
+		requests["memory"] = memory
+	}
+	return map[string]interface{}{"name": name, "resources": map[string]interface{}{"requests": requests}}
+}
+
+func sidecar(name string, cpu string, memory string) map[string]interface{} {
+	c := container(name, cpu, memory)
+	c["restartPolicy"] = "Always"
+	return c
+}
+
+// TestPodRequests validates the effective pod requests against the kube-scheduler formula
+func TestPodRequests(t *testing.T) {
+	var tests = []struct {
+		name     string
+		spec     map[string]interface{}
+		expected *clusterstateapi.Resource
+	}{
+		{"containers are summed", map[string]interface{}{
+			"containers": []interface{}{container("a", "1", "1Gi"), container("b", "500m", "1Gi")},
+		}, &clusterstateapi.Resource{MilliCPU: 1500, Memory: 2 << 30}},
+		{"init container larger than containers", map[string]interface{}{
+			"initContainers": []interface{}{container("init", "4", "512Mi")},
+			"containers":     []interface{}{container("a", "1", "1Gi"), container("b", "1", "1Gi")},
+		}, &clusterstateapi.Resource{MilliCPU: 4000, Memory: 2 << 30}},
+		{"sidecars add to containers and later init containers", map[string]interface{}{
+			"initContainers": []interface{}{sidecar("proxy", "1", "1Gi"), container("init", "3", "1Gi")},
+			"containers":     []interface{}{container("a", "1", "1Gi")},
+		}, &clusterstateapi.Resource{MilliCPU: 4000, Memory: 2 << 30}},
+		{"sidecar started after init container", map[string]interface{}{
+			"initContainers": []interface{}{container("init", "3", "1Gi"), sidecar("proxy", "1", "1Gi")},
+			"containers":     []interface{}{container("a", "1", "1Gi")},
+		}, &clusterstateapi.Resource{MilliCPU: 3000, Memory: 2 << 30}},
+		{"overhead", map[string]interface{}{
+			"containers": []interface{}{container("a", "1", "1Gi")},
+			"overhead":   map[string]interface{}{"cpu": "250m", "memory": "120Mi"},
+		}, &clusterstateapi.Resource{MilliCPU: 1250, Memory: 1<<30 + 120<<20}},
+		{"limits without requests", map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "a", "resources": map[string]interface{}{
+				"limits": map[string]interface{}{"cpu": "2", "nvidia.com/gpu": "1"}}}},
+		}, &clusterstateapi.Resource{MilliCPU: 2000, GPU: 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests, err := podRequests(test.spec, "default")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, requests)
+		})
+	}
+}
+
+// TestPodRequestsLimitRangeDefaults validates that LimitRange defaults apply to the resources containers do not specify
+func TestPodRequestsLimitRangeDefaults(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NoError(t, indexer.Add(&v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "team-a"},
+		Spec: v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{
+			{Type: v1.LimitTypeContainer,
+				Default:        v1.ResourceList{v1.ResourceCPU: resource.MustParse("2"), v1.ResourceMemory: resource.MustParse("2Gi")},
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}},
+			{Type: v1.LimitTypePod, Max: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100")}},
+		}},
+	}))
+	SetLimitRangeLister(corelisters.NewLimitRangeLister(indexer))
+	defer SetLimitRangeLister(nil)
+
+	spec := map[string]interface{}{"containers": []interface{}{
+		container("a", "", ""),
+		container("b", "1", ""),
+		map[string]interface{}{"name": "c", "resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "3"}}},
+	}}
+	requests, err := podRequests(spec, "team-a")
+	assert.NoError(t, err)
+	assert.Equal(t, &clusterstateapi.Resource{MilliCPU: 500 + 1000 + 3000, Memory: 3 * 2 << 30}, requests)
+
+	requests, err = podRequests(spec, "team-b")
+	assert.NoError(t, err)
+	assert.Equal(t, &clusterstateapi.Resource{MilliCPU: 4000}, requests)
+
+	// The namespace of the item takes precedence over the namespace of its AppWrapper
+	raw, _ := json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "Pod",
+		"metadata": map[string]interface{}{"namespace": "team-a"}, "spec": spec})
+	total, err := GetResources(genericItem(string(raw)), "team-b")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4500), total.MilliCPU)
+}
+
+// TestHasFields validates that the pod spec holding the containers is found
+func TestHasFields(t *testing.T) {
+	object := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3), "template": map[string]interface{}{
+		"spec": map[string]interface{}{"containers": []interface{}{container("a", "1", "1Gi")}}}}}
+	found, replicas, podSpec := hasFields(object)
+	assert.True(t, found)
+	assert.Equal(t, float64(3), replicas)
+	assert.Len(t, podSpec["containers"], 1)
+
+	found, _, _ = hasFields(map[string]interface{}{"spec": map[string]interface{}{}})
+	assert.False(t, found)
+}
@@ -22,6 +22,23 @@ import (
 
 type QuotaManagerInterface interface {
 	Fits(aw *arbv1.AppWrapper, requestedResources *clusterstateapi.Resource, clusterResources *clusterstateapi.Resource, proposedPremptions []*arbv1.AppWrapper) (bool, []*arbv1.AppWrapper, string)
+	FitsInCluster(aw *arbv1.AppWrapper, requestedResources *clusterstateapi.Resource, cluster string, proposedPremptions []*arbv1.AppWrapper) (bool, []*arbv1.AppWrapper, string)
 	Release(aw *arbv1.AppWrapper) bool
+	UndoAllocate(aw *arbv1.AppWrapper) bool
 	GetValidQuotaLabels() []string
+	GetQuotaHeadroom() map[string]map[string]int64
+	GetQuotaNodeUsage() []QuotaNodeUsage
+	GetQuotaRejections(aw *arbv1.AppWrapper, requestedResources *clusterstateapi.Resource) []arbv1.QuotaRejection
+}
+
+// QuotaNodeUsage is the quota and the allocated amount of a node of a quota tree, by resource, with the consumers
+// allocated on the node
+type QuotaNodeUsage struct {
+	Tree      string           `json:"tree"`
+	Node      string           `json:"node"`
+	Parent    string           `json:"parent,omitempty"`
+	Hard      bool             `json:"hard"`
+	Quota     map[string]int64 `json:"quota"`
+	Allocated map[string]int64 `json:"allocated"`
+	Consumers []string         `json:"consumers,omitempty"`
 }
@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-multierror"
 	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	qstv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/quotaplugins/quotasubtree/v1alpha1"
 	listersv1beta1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
 	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
@@ -61,6 +64,22 @@ type QuotaManager struct {
 	quotaManagerBackend *qmbackend.Manager
 	quotaSubtreeManager *qstmanager.QuotaSubtreeManager
 	initializationDone  bool
+
+	// Quota allocated to the AppWrappers dispatched to agent clusters, by AppWrapper id
+	clusterMutex       sync.Mutex
+	clusterAllocations map[string]*clusterAllocation
+	// Serializes FitsInCluster, so that no other allocation is checked against the cluster limits between the
+	// check of an allocation and its record. clusterMutex cannot be held instead, Fits may rebuild the allocations.
+	clusterFitMutex sync.Mutex
+}
+
+// clusterAllocation is the quota allocated to an AppWrapper dispatched to an agent cluster
+type clusterAllocation struct {
+	cluster string
+	// quota nodes charged in each tree, from the leaf to the root
+	nodes map[string][]string
+	// demands in each tree by resource name
+	demands map[string]map[string]int
 }
 
 type QuotaGroup struct {
@@ -123,6 +142,7 @@ func NewQuotaManager(dispatchedAWDemands map[string]*clusterstateapi.Resource, d
 		preemptionEnabled:   mcadConfig.HasPreemption(),
 		quotaManagerBackend: qmbackend.NewManager(),
 		initializationDone:  false,
+		clusterAllocations:  make(map[string]*clusterAllocation),
 	}
 
 	// Set the name of the forest in the backend
@@ -136,18 +156,54 @@ func NewQuotaManager(dispatchedAWDemands map[string]*clusterstateapi.Resource, d
 		return nil, err
 	}
 
+	err = qm.initialize(dispatchedAWDemands, dispatchedAWs)
+	if err != nil {
+		return nil, err
+	}
+	return qm, nil
+}
+
+// NewOfflineQuotaManager returns a quota manager for a fixed set of QuotaSubtrees, without connecting to a cluster.
+// It is used to evaluate quota decisions offline, e.g., by the scheduling simulator.
+func NewOfflineQuotaManager(quotaSubtrees []*qstv1.QuotaSubtree, awJobLister listersv1beta1.AppWrapperLister,
+	mcadConfig *config.MCADConfiguration) (*QuotaManager, error) {
+
+	if !mcadConfig.IsQuotaEnabled() {
+		klog.Infof("[NewOfflineQuotaManager] Quota management is not enabled.")
+		return nil, nil
+	}
+
+	qm := &QuotaManager{
+		appwrapperLister:    awJobLister,
+		preemptionEnabled:   mcadConfig.HasPreemption(),
+		quotaManagerBackend: qmbackend.NewManager(),
+		initializationDone:  false,
+		clusterAllocations:  make(map[string]*clusterAllocation),
+	}
+	qm.quotaManagerBackend.AddForest(QuotaManagerForestName)
+	qm.quotaSubtreeManager = qstmanager.NewStaticQuotaSubtreeManager(quotaSubtrees, qm.quotaManagerBackend)
+
+	err := qm.initialize(map[string]*clusterstateapi.Resource{}, map[string]*arbv1.AppWrapper{})
+	if err != nil {
+		return nil, err
+	}
+	return qm, nil
+}
+
+// initialize realizes the quota trees loaded in the backend cache and adds the dispatched AppWrappers to them
+func (qm *QuotaManager) initialize(dispatchedAWDemands map[string]*clusterstateapi.Resource, dispatchedAWs map[string]*arbv1.AppWrapper) error {
 	// Initialize Forest/Trees if new resource plan manager added to the cache
-	err = qm.updateForestFromCache()
+	err := qm.updateForestFromCache()
 	if err != nil {
 		klog.Errorf("[NewQuotaManager] Failure during Quota Manager Backend Cache refresh, err=%#v", err)
-		return nil, err
+		return err
 	}
 
 	// Add AppWrappers that have been evaluated as runnable to QuotaManager
 	err = qm.loadDispatchedAWs(dispatchedAWDemands, dispatchedAWs)
 	if err != nil {
 		klog.Errorf("[dispatchedAWDemands] Failure during Quota Manager Backend Cache refresh, err=%#v", err)
-		return nil, err
+		return err
 	}
 	// Set mode of quota manager
 	qm.quotaManagerBackend.SetMode(qmbackend.Normal)
@@ -159,7 +215,7 @@ func NewQuotaManager(dispatchedAWDemands map[string]*clusterstateapi.Resource, d
 	}
 
 	qm.initializationDone = true
-	return qm, nil
+	return nil
 }
 
 func (qm *QuotaManager) loadDispatchedAWs(dispatchedAWDemands map[string]*clusterstateapi.Resource,
@@ -213,6 +269,10 @@ func (qm *QuotaManager) loadDispatchedAWs(dispatchedAWDemands map[string]*cluste
 				qm.Release(aw)
 			}
 
+			if doesFit && len(aw.Status.DispatchedAgent) > 0 {
+				qm.recordClusterAllocation(aw, v, aw.Status.DispatchedAgent)
+			}
+
 			if len(preemptionIds) > 0 {
 				klog.Errorf("[loadDispatchedAWs] Loading of AppWrapper %s/%s caused invalid preemptions: %v.  Quota Manager is in inconsistent state, reason:",
 					aw.Namespace, aw.Name, preemptionIds, errorMessage)
@@ -598,7 +658,7 @@ func (qm *QuotaManager) getAggregatedResources(appWrapper *arbv1.AppWrapper) *cl
 	allocated := clusterstateapi.EmptyResource()
 
 	for _, genericItem := range appWrapper.Spec.AggrResources.GenericItems {
-		resources, err := genericresource.GetResources(&genericItem)
+		resources, err := genericresource.GetResources(&genericItem, appWrapper.Namespace)
 		if err != nil {
 			klog.V(8).Infof("[GetAggregatedResources] Failure aggregating resources for %s/%s, err=%#v, genericItem=%#v",
 				appWrapper.Namespace, appWrapper.Name, err, genericItem)
@@ -636,6 +696,35 @@ func (qm *QuotaManager) getAppWrappers(preemptIds []string) []*arbv1.AppWrapper
 	}
 	return aws
 }
+
+// UndoAllocate reverts the quota allocation of the last Fits of an AppWrapper that is not dispatched after all.
+// Unlike Release, the AppWrappers preempted by the allocation get their quota back.
+func (qm *QuotaManager) UndoAllocate(aw *arbv1.AppWrapper) bool {
+	if qm.quotaManagerBackend == nil {
+		klog.Errorf("[UndoAllocate] No quota manager backend exists, Quota undo %s/%s fails by default.", aw.Namespace, aw.Name)
+		return false
+	}
+
+	awId := util.CreateId(aw.Namespace, aw.Name)
+	if len(awId) <= 0 {
+		klog.Errorf("[UndoAllocate] Request failed due to invalid AppWrapper due to empty namespace: %s or name:%s.", aw.Namespace, aw.Name)
+		return false
+	}
+
+	if err := qm.quotaManagerBackend.UndoAllocateForest(QuotaManagerForestName, awId); err != nil {
+		klog.Errorf("[UndoAllocate] Quota undo for %s/%s failed, err=%v.", aw.Namespace, aw.Name, err)
+		return false
+	}
+
+	qm.clusterMutex.Lock()
+	delete(qm.clusterAllocations, awId)
+	qm.clusterMutex.Unlock()
+
+	qm.removeConsumer(awId)
+	klog.V(4).Infof("[UndoAllocate] Quota undo for %s/%s successful.", aw.Namespace, aw.Name)
+	return true
+}
+
 func (qm *QuotaManager) Release(aw *arbv1.AppWrapper) bool {
 
 	// Handle uninitialized quota manager
@@ -653,6 +742,10 @@ func (qm *QuotaManager) Release(aw *arbv1.AppWrapper) bool {
 
 	released := qm.quotaManagerBackend.DeAllocateForest(QuotaManagerForestName, awId)
 
+	qm.clusterMutex.Lock()
+	delete(qm.clusterAllocations, awId)
+	qm.clusterMutex.Unlock()
+
 	if !released {
 		klog.Errorf("[Release] Quota release for %s/%s failed.", aw.Namespace, aw.Name)
 	} else {
@@ -691,3 +784,237 @@ func (qm *QuotaManager) removeConsumer(consumerID string) {
 func (qm *QuotaManager) GetValidQuotaLabels() []string {
 	return qm.quotaManagerBackend.GetTreeNames()
 }
+
+// GetQuotaHeadroom returns for each quota tree the quota of its root left after the allocations, by resource
+// name, in the units of the quota tree.
+func (qm *QuotaManager) GetQuotaHeadroom() map[string]map[string]int64 {
+	if qm.quotaManagerBackend == nil {
+		return nil
+	}
+	headroom := map[string]map[string]int64{}
+	for treeName, tree := range qm.quotaManagerBackend.GetTreeUsage() {
+		root := tree.Nodes[tree.Root]
+		if root == nil {
+			continue
+		}
+		resources := map[string]int64{}
+		for i, resourceName := range tree.ResourceNames {
+			if i < len(root.Quota) && i < len(root.Allocated) {
+				resources[resourceName] = int64(root.Quota[i] - root.Allocated[i])
+			}
+		}
+		headroom[treeName] = resources
+	}
+	return headroom
+}
+
+// GetQuotaNodeUsage returns the quota and the allocated amount of every node of the quota trees, sorted by tree and node
+func (qm *QuotaManager) GetQuotaNodeUsage() []quota.QuotaNodeUsage {
+	if qm.quotaManagerBackend == nil {
+		return nil
+	}
+	usage := []quota.QuotaNodeUsage{}
+	for treeName, tree := range qm.quotaManagerBackend.GetTreeUsage() {
+		for nodeName, node := range tree.Nodes {
+			nodeUsage := quota.QuotaNodeUsage{
+				Tree:      treeName,
+				Node:      nodeName,
+				Parent:    node.Parent,
+				Hard:      node.Hard,
+				Quota:     map[string]int64{},
+				Allocated: map[string]int64{},
+				Consumers: node.Consumers,
+			}
+			for i, resourceName := range tree.ResourceNames {
+				if i < len(node.Quota) && i < len(node.Allocated) {
+					nodeUsage.Quota[resourceName] = int64(node.Quota[i])
+					nodeUsage.Allocated[resourceName] = int64(node.Allocated[i])
+				}
+			}
+			usage = append(usage, nodeUsage)
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Tree != usage[j].Tree {
+			return usage[i].Tree < usage[j].Tree
+		}
+		return usage[i].Node < usage[j].Node
+	})
+	return usage
+}
+
+// GetQuotaRejections returns the quota tree nodes without enough quota left for the demands of an AppWrapper,
+// with the missing amount by resource. Only the hard nodes and the roots on the paths from the leaf nodes of the
+// AppWrapper are reported, soft nodes borrow the quota they miss from their parent.
+func (qm *QuotaManager) GetQuotaRejections(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource) []arbv1.QuotaRejection {
+	if qm.quotaManagerBackend == nil {
+		return nil
+	}
+	quotaTreeDesignations, treeNameToResourceTypes, err := qm.getQuotaDesignation(aw)
+	if err != nil {
+		klog.Errorf("[GetQuotaRejections] Failure getting quota designation of AppWrapper %s/%s, err=%#v", aw.Namespace, aw.Name, err)
+		return nil
+	}
+	trees := qm.quotaManagerBackend.GetTreeUsage()
+	var rejections []arbv1.QuotaRejection
+	for _, quotaTreeDesignation := range quotaTreeDesignations {
+		treeName := quotaTreeDesignation.GroupContext
+		tree := trees[treeName]
+		if tree == nil {
+			continue
+		}
+		if leafNode := tree.Nodes[quotaTreeDesignation.GroupId]; leafNode == nil || !leafNode.Leaf {
+			continue
+		}
+		demands, _ := qm.getQuotaTreeResourceTypesDemands(awResDemands, treeNameToResourceTypes[treeName])
+		for _, node := range tree.GetPathToRoot(quotaTreeDesignation.GroupId) {
+			if !node.Hard && node.ID != tree.Root {
+				continue
+			}
+			shortfall := quotaShortfall(tree.ResourceNames, node.Quota, node.Allocated, demands)
+			if len(shortfall) > 0 {
+				rejections = append(rejections, arbv1.QuotaRejection{Tree: treeName, Node: node.ID, Shortfall: shortfall})
+			}
+		}
+	}
+	return rejections
+}
+
+// quotaShortfall returns by resource the amount of the demands above the quota left in a quota node
+func quotaShortfall(resourceNames []string, quota []int, allocated []int, demands map[string]int) map[string]int64 {
+	shortfall := map[string]int64{}
+	for i, resourceName := range resourceNames {
+		if i >= len(quota) || i >= len(allocated) {
+			continue
+		}
+		if missing := demands[resourceName] - (quota[i] - allocated[i]); missing > 0 {
+			shortfall[resourceName] = int64(missing)
+		}
+	}
+	return shortfall
+}
+
+// FitsInCluster allocates the quota of an AppWrapper dispatched to an agent cluster like Fits, and ties the
+// allocation to the cluster. The allocation is refused if it exceeds the limit of one of the quota nodes
+// charged in the cluster. The limits are checked before allocating, so that a refusal leaves the forest and
+// the AppWrappers Fits would have preempted untouched.
+func (qm *QuotaManager) FitsInCluster(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource, cluster string,
+	proposedPreemptions []*arbv1.AppWrapper) (bool, []*arbv1.AppWrapper, string) {
+	qm.clusterFitMutex.Lock()
+	defer qm.clusterFitMutex.Unlock()
+	awId := util.CreateId(aw.Namespace, aw.Name)
+	alloc := qm.buildClusterAllocation(aw, awResDemands, cluster)
+	if alloc != nil {
+		qm.clusterMutex.Lock()
+		exceeded := exceededClusterLimit(alloc, awId, qm.clusterAllocations, qm.quotaSubtreeManager.GetClusterLimits())
+		qm.clusterMutex.Unlock()
+		if len(exceeded) > 0 {
+			klog.V(4).Infof("[FitsInCluster] AppWrapper %s/%s does not fit in cluster %s: %s", aw.Namespace, aw.Name, cluster, exceeded)
+			return false, nil, exceeded
+		}
+	}
+
+	doesFit, preemptIds, message := qm.Fits(aw, awResDemands, nil, proposedPreemptions)
+	if !doesFit || alloc == nil {
+		return doesFit, preemptIds, message
+	}
+	qm.clusterMutex.Lock()
+	// a previous allocation of the AppWrapper is replaced by this one
+	qm.clusterAllocations[awId] = alloc
+	qm.clusterMutex.Unlock()
+	return doesFit, preemptIds, message
+}
+
+// recordClusterAllocation ties the quota allocated to an AppWrapper to the agent cluster it is dispatched to
+func (qm *QuotaManager) recordClusterAllocation(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource, cluster string) {
+	alloc := qm.buildClusterAllocation(aw, awResDemands, cluster)
+	if alloc == nil {
+		return
+	}
+	qm.clusterMutex.Lock()
+	defer qm.clusterMutex.Unlock()
+	qm.clusterAllocations[util.CreateId(aw.Namespace, aw.Name)] = alloc
+}
+
+// buildClusterAllocation returns the quota nodes charged by an AppWrapper and its demands in each quota tree
+func (qm *QuotaManager) buildClusterAllocation(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource, cluster string) *clusterAllocation {
+	quotaTreeDesignations, treeNameToResourceTypes, err := qm.getQuotaDesignation(aw)
+	if err != nil {
+		klog.Errorf("[buildClusterAllocation] Failure getting quota designation of AppWrapper %s/%s, err=%#v", aw.Namespace, aw.Name, err)
+		return nil
+	}
+	alloc := &clusterAllocation{
+		cluster: cluster,
+		nodes:   make(map[string][]string),
+		demands: make(map[string]map[string]int),
+	}
+	for _, quotaTreeDesignation := range quotaTreeDesignations {
+		treeName := quotaTreeDesignation.GroupContext
+		demands, _ := qm.getQuotaTreeResourceTypesDemands(awResDemands, treeNameToResourceTypes[treeName])
+		alloc.demands[treeName] = demands
+		controller := qm.quotaManagerBackend.GetTreeController(treeName)
+		if controller == nil || controller.GetTree() == nil {
+			continue
+		}
+		leafNode := controller.GetTree().GetLeafNode(quotaTreeDesignation.GroupId)
+		if leafNode == nil {
+			continue
+		}
+		for _, node := range leafNode.GetPathToRoot() {
+			alloc.nodes[treeName] = append(alloc.nodes[treeName], node.GetID())
+		}
+	}
+	return alloc
+}
+
+// exceededClusterLimit checks the allocation of an AppWrapper against the limits of the quota nodes in its cluster,
+// given the allocations of the other AppWrappers. A previous allocation of the AppWrapper is ignored. It returns a
+// message describing the first limit exceeded, empty if none.
+func exceededClusterLimit(alloc *clusterAllocation, awId string, allocations map[string]*clusterAllocation,
+	limits map[qstmanager.ClusterLimitKey]map[string]int64) string {
+	if len(limits) == 0 {
+		return ""
+	}
+	treeNames := make([]string, 0, len(alloc.nodes))
+	for treeName := range alloc.nodes {
+		treeNames = append(treeNames, treeName)
+	}
+	sort.Strings(treeNames)
+
+	for _, treeName := range treeNames {
+		for _, nodeName := range alloc.nodes[treeName] {
+			limit, found := limits[qstmanager.ClusterLimitKey{Tree: treeName, Node: nodeName, Cluster: alloc.cluster}]
+			if !found {
+				continue
+			}
+			resourceNames := make([]string, 0, len(limit))
+			for resourceName := range limit {
+				resourceNames = append(resourceNames, resourceName)
+			}
+			sort.Strings(resourceNames)
+			for _, resourceName := range resourceNames {
+				used := int64(alloc.demands[treeName][resourceName])
+				for otherId, other := range allocations {
+					if otherId == awId || other.cluster != alloc.cluster || !containsNode(other.nodes[treeName], nodeName) {
+						continue
+					}
+					used += int64(other.demands[treeName][resourceName])
+				}
+				if used > limit[resourceName] {
+					return fmt.Sprintf("quota node %s of tree %s would use %d %s in cluster %s, above its limit of %d",
+						nodeName, treeName, used, resourceName, alloc.cluster, limit[resourceName])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func containsNode(nodes []string, nodeName string) bool {
+	for _, node := range nodes {
+		if node == nodeName {
+			return true
+		}
+	}
+	return false
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota/quotaforestmanager/qm_lib_backend_with_quotasubt_mgr/quotasubtmgr/util"
 	qmlib "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/quotaplugins/quota-forest/quota-manager/quota"
 	qmlibutils "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/quotaplugins/quota-forest/quota-manager/quota/utils"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 
@@ -45,6 +46,25 @@ func NewQuotaSubtreeManager(config *rest.Config, quotaManagerBackend *qmlib.Mana
 	return newQuotaSubtreeManager(config, quotaManagerBackend)
 }
 
+// NewStaticQuotaSubtreeManager returns a manager for a fixed set of QuotaSubtrees, without an informer.
+// It is used to evaluate quota outside of a cluster, e.g., by the scheduling simulator.
+func NewStaticQuotaSubtreeManager(quotaSubtrees []*qstv1.QuotaSubtree, quotaManagerBackend *qmlib.Manager) *QuotaSubtreeManager {
+	qstm := &QuotaSubtreeManager{
+		quotaManagerBackend: quotaManagerBackend,
+		qstMap:              make(map[string]*qstv1.QuotaSubtree),
+		qstChanged:          true,
+		qstSynced:           func() bool { return true },
+	}
+	for _, qst := range quotaSubtrees {
+		qstm.qstMap[qst.Namespace+"/"+qst.Name] = qst
+	}
+
+	// Initialize Quota Trees
+	qstm.initializeQuotaTreeBackend()
+	klog.V(4).Infof("[NewStaticQuotaSubtreeManager] QuotaSubtree Manager initialization complete.")
+	return qstm
+}
+
 type QuotaSubtreeManager struct {
 	quotaManagerBackend *qmlib.Manager
 
@@ -134,6 +154,54 @@ func (qstm *QuotaSubtreeManager) IsQuotasubtreeChanged() bool {
 	return qstm.qstChanged
 }
 
+// quotaAmount converts a quantity to the units of the quota trees: millicores for cpu, bytes for memory
+// and units for the other resources
+func quotaAmount(resourceName string, quantity resource.Quantity) (int64, bool) {
+	switch resourceName {
+	case "cpu":
+		return quantity.MilliValue(), true
+	case "memory":
+		return quantity.Value(), true
+	default:
+		return quantity.AsInt64()
+	}
+}
+
+// ClusterLimitKey identifies the limit of a quota node in an agent cluster
+type ClusterLimitKey struct {
+	Tree    string
+	Node    string
+	Cluster string
+}
+
+// GetClusterLimits returns the limits of the quota nodes in the agent clusters, by resource name, in the
+// units of the quota trees
+func (qstm *QuotaSubtreeManager) GetClusterLimits() map[ClusterLimitKey]map[string]int64 {
+	qstm.qstMutex.RLock()
+	defer qstm.qstMutex.RUnlock()
+
+	limits := make(map[ClusterLimitKey]map[string]int64)
+	for _, qst := range qstm.qstMap {
+		qstTreeName := qst.Labels[util.URMTreeLabel]
+		for _, qstChild := range qst.Spec.Children {
+			for cluster, resources := range qstChild.Quotas.ClusterLimits {
+				limit := make(map[string]int64)
+				for k, v := range resources {
+					amount, success := quotaAmount(string(k), v)
+					if !success {
+						klog.Errorf("[GetClusterLimits] Failure converting QuotaSubtree cluster limit to int64, QuotaSubtree %s limit: %v in cluster %s will be ignored.",
+							qst.Name, v, cluster)
+						continue
+					}
+					limit[string(k)] = amount
+				}
+				limits[ClusterLimitKey{Tree: qstTreeName, Node: qstChild.Name, Cluster: cluster}] = limit
+			}
+		}
+	}
+	return limits
+}
+
 func (qstm *QuotaSubtreeManager) createTreeNodesFromQST(qst *qstv1.QuotaSubtree) (map[string]*qmlibutils.JNodeSpec, []string) {
 	nodeSpecs := make(map[string]*qmlibutils.JNodeSpec)
 	var resourceTypes []string
@@ -153,20 +221,11 @@ func (qstm *QuotaSubtreeManager) createTreeNodesFromQST(qst *qstv1.QuotaSubtree)
 				continue
 			}
 			resourceTypes = appendIfNotPresent(resourceName, resourceTypes)
-			var amount int64
-			var success bool
-			switch resourceName {
-			case "cpu":
-				amount = v.MilliValue()
-			case "memory":
-				amount = v.Value()
-			default:
-				amount, success = v.AsInt64()
-				if !success {
-					klog.Errorf("[createTreeNodesFromQST] Failure converting QuotaSubtree request demand quota to int64, QuotaSubtree %s request quota: %v will be ignored.",
-						qst.Name, v)
-					continue
-				}
+			amount, success := quotaAmount(resourceName, v)
+			if !success {
+				klog.Errorf("[createTreeNodesFromQST] Failure converting QuotaSubtree request demand quota to int64, QuotaSubtree %s request quota: %v will be ignored.",
+					qst.Name, v)
+				continue
 			}
 
 			// Add new quota demand
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotaforestmanager
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	qstv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/quotaplugins/quotasubtree/v1alpha1"
+	listersv1beta1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	qstmanager "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota/quotaforestmanager/qm_lib_backend_with_quotasubt_mgr/quotasubtmgr"
+)
+
+func TestExceededClusterLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	allocation := func(cluster string, gpus int) *clusterAllocation {
+		return &clusterAllocation{
+			cluster: cluster,
+			nodes:   map[string][]string{"context-root": {"team-a", "context-root"}},
+			demands: map[string]map[string]int{"context-root": {"nvidia.com/gpu": gpus}},
+		}
+	}
+	limits := map[qstmanager.ClusterLimitKey]map[string]int64{
+		{Tree: "context-root", Node: "team-a", Cluster: "cluster-east"}: {"nvidia.com/gpu": 64},
+	}
+	allocations := map[string]*clusterAllocation{
+		"default_aw-1": allocation("cluster-east", 40),
+		"default_aw-2": allocation("cluster-west", 40),
+	}
+
+	tests := []struct {
+		name     string
+		alloc    *clusterAllocation
+		limits   map[qstmanager.ClusterLimitKey]map[string]int64
+		exceeded bool
+	}{
+		{name: "no limits", alloc: allocation("cluster-east", 64), limits: nil, exceeded: false},
+		{name: "within limit", alloc: allocation("cluster-east", 24), limits: limits, exceeded: false},
+		{name: "above limit", alloc: allocation("cluster-east", 25), limits: limits, exceeded: true},
+		{name: "cluster without limit", alloc: allocation("cluster-west", 64), limits: limits, exceeded: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.Expect(exceededClusterLimit(tt.alloc, "default_aw-3", allocations, tt.limits) != "").To(gomega.Equal(tt.exceeded))
+		})
+	}
+	// the previous allocation of the AppWrapper is replaced, not added to
+	g.Expect(exceededClusterLimit(allocation("cluster-east", 64), "default_aw-1", allocations, limits)).To(gomega.BeEmpty())
+}
+
+// newBorrowingQuotaManager returns a quota manager with two teams sharing the quota of the root, where team-a
+// is limited in cluster-east, and the borrower of team-b holding the quota of both teams
+func newBorrowingQuotaManager(g *gomega.WithT) (*QuotaManager, *arbv1.AppWrapper) {
+	treeLabels := map[string]string{"tree": "quota_context"}
+	quotaSubtrees := []*qstv1.QuotaSubtree{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root", Namespace: "kube-system", Labels: treeLabels},
+			Spec: qstv1.QuotaSubtreeSpec{Children: []qstv1.Child{{Name: "context-root", Quotas: qstv1.Quota{
+				Requests: qstv1.ResourceList{"cpu": resource.MustParse("4")}}}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root-children", Namespace: "kube-system", Labels: treeLabels},
+			Spec: qstv1.QuotaSubtreeSpec{Parent: "context-root", Children: []qstv1.Child{
+				{Name: "team-a", Quotas: qstv1.Quota{Requests: qstv1.ResourceList{"cpu": resource.MustParse("2")},
+					ClusterLimits: map[string]qstv1.ResourceList{"cluster-east": {"cpu": resource.MustParse("1")}}}},
+				{Name: "team-b", Quotas: qstv1.Quota{Requests: qstv1.ResourceList{"cpu": resource.MustParse("2")}}},
+			}},
+		},
+	}
+	borrower := teamAppWrapper("borrower", "team-b")
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	g.Expect(indexer.Add(borrower)).To(gomega.Succeed())
+	qm, err := NewOfflineQuotaManager(quotaSubtrees, listersv1beta1.NewAppWrapperLister(indexer),
+		&config.MCADConfiguration{QuotaEnabled: pointer.Bool(true), Preemption: pointer.Bool(true)})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// team-b borrows the unused quota of team-a
+	fits, _, _ := qm.FitsInCluster(borrower, &clusterstateapi.Resource{MilliCPU: 4000}, "cluster-west", nil)
+	g.Expect(fits).To(gomega.BeTrue())
+	return qm, borrower
+}
+
+func teamAppWrapper(name string, team string) *arbv1.AppWrapper {
+	return &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"quota_context": team}}}
+}
+
+// TestFitsInClusterKeepsPreemptionVictims validates that an AppWrapper refused by a cluster limit does not
+// preempt anything, and that the AppWrappers it would have preempted keep their quota
+func TestFitsInClusterKeepsPreemptionVictims(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	qm, _ := newBorrowingQuotaManager(g)
+
+	demand := &clusterstateapi.Resource{MilliCPU: 2000}
+	fits, preempted, message := qm.FitsInCluster(teamAppWrapper("aw-a", "team-a"), demand, "cluster-east", nil)
+	g.Expect(fits).To(gomega.BeFalse())
+	g.Expect(preempted).To(gomega.BeEmpty())
+	g.Expect(message).To(gomega.ContainSubstring("cluster-east"))
+
+	// the borrower still holds its quota, so it is preempted again in the next cluster
+	fits, preempted, _ = qm.FitsInCluster(teamAppWrapper("aw-a", "team-a"), demand, "cluster-west", nil)
+	g.Expect(fits).To(gomega.BeTrue())
+	g.Expect(preempted).To(gomega.HaveLen(1))
+	g.Expect(preempted[0].Name).To(gomega.Equal("borrower"))
+}
+
+// slowAppWrapperLister delays the lookups of AppWrappers, which Fits does between the check of the cluster limits
+// and the record of the allocation when it preempts AppWrappers
+type slowAppWrapperLister struct {
+	listersv1beta1.AppWrapperLister
+}
+
+func (l slowAppWrapperLister) AppWrappers(namespace string) listersv1beta1.AppWrapperNamespaceLister {
+	time.Sleep(100 * time.Millisecond)
+	return l.AppWrapperLister.AppWrappers(namespace)
+}
+
+// TestFitsInClusterConcurrently validates that concurrent allocations in a cluster cannot exceed its limits together
+func TestFitsInClusterConcurrently(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	qm, _ := newBorrowingQuotaManager(g)
+	qm.appwrapperLister = slowAppWrapperLister{qm.appwrapperLister}
+
+	// team-a is limited to 1 cpu in cluster-east, so only one of these AppWrappers fits there, although the
+	// quota of team-a is 2 cpus
+	var fitting int32
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if fits, _, _ := qm.FitsInCluster(teamAppWrapper(name, "team-a"), &clusterstateapi.Resource{MilliCPU: 1000}, "cluster-east", nil); fits {
+				atomic.AddInt32(&fitting, 1)
+			}
+		}(fmt.Sprintf("aw-%d", i))
+	}
+	wg.Wait()
+	g.Expect(fitting).To(gomega.Equal(int32(1)))
+}
+
+// TestUndoAllocateKeepsPreemptionVictims validates that undoing the allocation of an AppWrapper gives their
+// quota back to the AppWrappers it preempted
+func TestUndoAllocateKeepsPreemptionVictims(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	qm, _ := newBorrowingQuotaManager(g)
+
+	awA := teamAppWrapper("aw-a", "team-a")
+	demand := &clusterstateapi.Resource{MilliCPU: 2000}
+	fits, preempted, _ := qm.Fits(awA, demand, nil, nil)
+	g.Expect(fits).To(gomega.BeTrue())
+	g.Expect(preempted).To(gomega.HaveLen(1))
+
+	g.Expect(qm.UndoAllocate(awA)).To(gomega.BeTrue())
+	// the borrower got its quota back, so it is preempted again
+	fits, preempted, _ = qm.Fits(awA, demand, nil, nil)
+	g.Expect(fits).To(gomega.BeTrue())
+	g.Expect(preempted).To(gomega.HaveLen(1))
+	g.Expect(preempted[0].Name).To(gomega.Equal("borrower"))
+}
+
+func TestQuotaShortfall(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	resourceNames := []string{"cpu", "memory", "nvidia.com/gpu"}
+	quota := []int{8000, 4096, 4}
+	allocated := []int{6000, 1024, 4}
+
+	g.Expect(quotaShortfall(resourceNames, quota, allocated, map[string]int{"cpu": 3000, "memory": 1024, "nvidia.com/gpu": 1})).
+		To(gomega.Equal(map[string]int64{"cpu": 1000, "nvidia.com/gpu": 1}))
+	g.Expect(quotaShortfall(resourceNames, quota, allocated, map[string]int{"cpu": 2000, "memory": 3072})).To(gomega.BeEmpty())
+	// resources without quota values are ignored
+	g.Expect(quotaShortfall(resourceNames, quota[:1], allocated[:1], map[string]int{"memory": 8192})).To(gomega.BeEmpty())
+}
+
+func TestNewOfflineQuotaManager(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	treeLabels := map[string]string{"tree": "quota_context"}
+	quotaSubtrees := []*qstv1.QuotaSubtree{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root", Namespace: "kube-system", Labels: treeLabels},
+			Spec: qstv1.QuotaSubtreeSpec{Children: []qstv1.Child{{Name: "context-root", Quotas: qstv1.Quota{
+				Requests: qstv1.ResourceList{"cpu": resource.MustParse("4")}}}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root-children", Namespace: "kube-system", Labels: treeLabels},
+			Spec: qstv1.QuotaSubtreeSpec{Parent: "context-root", Children: []qstv1.Child{{Name: "team-a", Quotas: qstv1.Quota{
+				HardLimit: true, Requests: qstv1.ResourceList{"cpu": resource.MustParse("2")}}}}},
+		},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := listersv1beta1.NewAppWrapperLister(indexer)
+
+	qm, err := NewOfflineQuotaManager(quotaSubtrees, lister, &config.MCADConfiguration{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(qm).To(gomega.BeNil())
+
+	qm, err = NewOfflineQuotaManager(quotaSubtrees, lister, &config.MCADConfiguration{QuotaEnabled: pointer.Bool(true)})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(qm.GetValidQuotaLabels()).To(gomega.Equal([]string{"quota_context"}))
+
+	appWrapper := func(name string) *arbv1.AppWrapper {
+		return &arbv1.AppWrapper{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"quota_context": "team-a"}}}
+	}
+	demand := &clusterstateapi.Resource{MilliCPU: 2000}
+	fits, _, _ := qm.Fits(appWrapper("aw-1"), demand, nil, nil)
+	g.Expect(fits).To(gomega.BeTrue())
+	fits, _, _ = qm.Fits(appWrapper("aw-2"), demand, nil, nil)
+	g.Expect(fits).To(gomega.BeFalse())
+	g.Expect(qm.Release(appWrapper("aw-1"))).To(gomega.BeTrue())
+	fits, _, _ = qm.Fits(appWrapper("aw-2"), demand, nil, nil)
+	g.Expect(fits).To(gomega.BeTrue())
+}
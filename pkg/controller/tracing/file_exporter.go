@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// fileSpan is the JSON representation of a span written by the file exporter
+type fileSpan struct {
+	TraceID     string                 `json:"traceId"`
+	SpanID      string                 `json:"spanId"`
+	ParentID    string                 `json:"parentSpanId,omitempty"`
+	Name        string                 `json:"name"`
+	Start       time.Time              `json:"start"`
+	End         time.Time              `json:"end"`
+	DurationMs  float64                `json:"durationMs"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	Events      []fileEvent            `json:"events,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Description string                 `json:"description,omitempty"`
+}
+
+type fileEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// FileExporter writes the spans to a file, one JSON object per line.  It is meant for tests and for
+// troubleshooting without a collector.
+type FileExporter struct {
+	mutex   sync.Mutex
+	out     io.Writer
+	encoder *json.Encoder
+}
+
+var _ sdktrace.SpanExporter = &FileExporter{}
+
+// NewFileExporter creates an exporter appending to the file at the specified path
+func NewFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterExporter(f), nil
+}
+
+// NewWriterExporter creates an exporter writing to the specified writer
+func NewWriterExporter(out io.Writer) *FileExporter {
+	return &FileExporter{out: out, encoder: json.NewEncoder(out)}
+}
+
+// ExportSpans writes the spans
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, span := range spans {
+		s := fileSpan{
+			TraceID:    span.SpanContext().TraceID().String(),
+			SpanID:     span.SpanContext().SpanID().String(),
+			Name:       span.Name(),
+			Start:      span.StartTime(),
+			End:        span.EndTime(),
+			DurationMs: float64(span.EndTime().Sub(span.StartTime()).Microseconds()) / 1000,
+			Attributes: make(map[string]interface{}),
+		}
+		if span.Parent().IsValid() {
+			s.ParentID = span.Parent().SpanID().String()
+		}
+		for _, kv := range span.Attributes() {
+			s.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		for _, event := range span.Events() {
+			fe := fileEvent{Name: event.Name, Time: event.Time}
+			if len(event.Attributes) > 0 {
+				fe.Attributes = make(map[string]interface{})
+				for _, kv := range event.Attributes {
+					fe.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+				}
+			}
+			s.Events = append(s.Events, fe)
+		}
+		if span.Status().Code != 0 {
+			s.Status = span.Status().Code.String()
+			s.Description = span.Status().Description
+		}
+		if err := e.encoder.Encode(&s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown closes the file of the exporter
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if closer, ok := e.out.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
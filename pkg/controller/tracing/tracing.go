@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing configures the OpenTelemetry tracing of the AppWrapper lifecycle.  Tracing is disabled by
+// default, the spans are then recorded by the no-op tracer of OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP exports the spans to an OTLP collector over gRPC
+	ExporterOTLP = "otlp"
+	// ExporterFile writes the spans to a file, one JSON object per line
+	ExporterFile = "file"
+
+	tracerName  = "github.com/project-codeflare/multi-cluster-app-dispatcher"
+	serviceName = "mcad-controller"
+
+	// Attribute keys of the AppWrapper spans
+	AppWrapperNamespaceKey = attribute.Key("appwrapper.namespace")
+	AppWrapperNameKey      = attribute.Key("appwrapper.name")
+	AttemptKey             = attribute.Key("attempt")
+	CallerKey              = attribute.Key("caller")
+)
+
+// Config is the configuration of the tracing exporter
+type Config struct {
+	// Exporter is otlp, file, or empty to disable tracing
+	Exporter string
+	// Endpoint is the address of the OTLP collector.  The OpenTelemetry environment variables and
+	// localhost:4317 are used when it is empty.
+	Endpoint string
+	// Insecure disables the transport security of the connection to the OTLP collector
+	Insecure bool
+	// File is the path of the file of the file exporter
+	File string
+	// SampleRatio is the fraction of the dispatch attempts that are traced
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider for the configured exporter.  It returns a function to flush
+// and stop the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracegrpc.Option
+		if len(cfg.Endpoint) > 0 {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterFile:
+		exporter, err = NewFileExporter(cfg.File)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, expected %s or %s", cfg.Exporter, ExporterOTLP, ExporterFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the %s tracing exporter: %v", cfg.Exporter, err)
+	}
+
+	provider := NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithSampler(
+		sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider returns a tracer provider with the resource of the controller
+func NewTracerProvider(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)...)
+}
+
+// Start starts a span of the controller
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartWith starts a span of the controller with the specified options
+func StartWith(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End records the error of the operation of a span, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// AppWrapper returns the attributes identifying an AppWrapper
+func AppWrapper(namespace, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{AppWrapperNamespaceKey.String(namespace), AppWrapperNameKey.String(name)}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func decodeSpans(g *gomega.WithT, data []byte) []fileSpan {
+	var spans []fileSpan
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var span fileSpan
+		g.Expect(json.Unmarshal([]byte(line), &span)).To(gomega.Succeed())
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func TestWriterExporter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var buf bytes.Buffer
+	provider := NewTracerProvider(sdktrace.WithSyncer(NewWriterExporter(&buf)))
+	tracer := provider.Tracer(tracerName)
+
+	ctx, root := tracer.Start(context.Background(), "AppWrapper dispatch")
+	root.SetAttributes(AppWrapper("default", "aw")...)
+	_, child := tracer.Start(ctx, "updateStatusInEtcd")
+	child.SetAttributes(CallerKey.String("ScheduleNext - setHOL"), AttemptKey.Int(2))
+	End(child, errors.New("conflict"))
+	End(root, nil)
+	g.Expect(provider.Shutdown(context.Background())).To(gomega.Succeed())
+
+	spans := decodeSpans(g, buf.Bytes())
+	g.Expect(spans).To(gomega.HaveLen(2))
+	g.Expect(spans[0].Name).To(gomega.Equal("updateStatusInEtcd"))
+	g.Expect(spans[1].Name).To(gomega.Equal("AppWrapper dispatch"))
+	g.Expect(spans[0].TraceID).To(gomega.Equal(spans[1].TraceID))
+	g.Expect(spans[0].ParentID).To(gomega.Equal(spans[1].SpanID))
+	g.Expect(spans[1].ParentID).To(gomega.BeEmpty())
+	g.Expect(spans[0].Attributes).To(gomega.HaveKeyWithValue("caller", "ScheduleNext - setHOL"))
+	g.Expect(spans[0].Attributes).To(gomega.HaveKeyWithValue("attempt", float64(2)))
+	g.Expect(spans[0].Status).To(gomega.Equal("Error"))
+	g.Expect(spans[0].Description).To(gomega.Equal("conflict"))
+	g.Expect(spans[0].Events).To(gomega.HaveLen(1))
+	g.Expect(spans[1].Attributes).To(gomega.HaveKeyWithValue("appwrapper.namespace", "default"))
+	g.Expect(spans[1].Attributes).To(gomega.HaveKeyWithValue("appwrapper.name", "aw"))
+	g.Expect(spans[1].Status).To(gomega.BeEmpty())
+}
+
+func TestSetup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defaultProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(defaultProvider)
+
+	shutdown, err := Setup(context.Background(), Config{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(shutdown(context.Background())).To(gomega.Succeed())
+	g.Expect(otel.GetTracerProvider()).To(gomega.BeIdenticalTo(defaultProvider))
+
+	_, err = Setup(context.Background(), Config{Exporter: "zipkin"})
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err = Setup(context.Background(), Config{Exporter: ExporterFile, File: path, SampleRatio: 1})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	_, span := Start(context.Background(), "ScheduleNext", AttemptKey.Int(1))
+	span.End()
+	g.Expect(shutdown(context.Background())).To(gomega.Succeed())
+
+	data, err := os.ReadFile(path)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	spans := decodeSpans(g, data)
+	g.Expect(spans).To(gomega.HaveLen(1))
+	g.Expect(spans[0].Name).To(gomega.Equal("ScheduleNext"))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"sort"
+)
+
+// TreeUsage : a copy of the state of the nodes of a quota tree
+type TreeUsage struct {
+	// name of the tree
+	Name string
+	// ID of the root node
+	Root string
+	// names of the resources, in the order of the quota and allocated values
+	ResourceNames []string
+	// all nodes of the tree: nodeID -> nodeUsage
+	Nodes map[string]*NodeUsage
+}
+
+// NodeUsage : a copy of the state of a node of a quota tree
+type NodeUsage struct {
+	// ID of the node
+	ID string
+	// ID of the parent node; empty for the root
+	Parent string
+	// the node does not borrow quota from its parent
+	Hard bool
+	// the node has no children, consumers are charged to leaf nodes
+	Leaf bool
+	// quota of the node, by resource index
+	Quota []int
+	// amount allocated on the node, by resource index
+	Allocated []int
+	// IDs of the consumers allocated on the node, sorted
+	Consumers []string
+}
+
+// GetPathToRoot : the nodes from a node to the root of the tree, starting with the node; empty if not found
+func (tu *TreeUsage) GetPathToRoot(nodeID string) []*NodeUsage {
+	var path []*NodeUsage
+	for node := tu.Nodes[nodeID]; node != nil && len(path) <= len(tu.Nodes); node = tu.Nodes[node.Parent] {
+		path = append(path, node)
+		if node.ID == tu.Root {
+			break
+		}
+	}
+	return path
+}
+
+// GetTreeUsage : get a copy of the state of the nodes of all quota trees;
+// the copy is taken under the lock of the manager, so it is consistent with concurrent allocations
+func (m *Manager) GetTreeUsage() map[string]*TreeUsage {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	usage := make(map[string]*TreeUsage)
+	for treeName, agent := range m.agents {
+		if agent.controller == nil || agent.controller.GetTree() == nil {
+			continue
+		}
+		tree := agent.controller.GetTree()
+		treeUsage := &TreeUsage{
+			Name:          treeName,
+			ResourceNames: append([]string(nil), tree.GetResourceNames()...),
+			Nodes:         make(map[string]*NodeUsage),
+		}
+		if root := tree.GetRoot(); root != nil {
+			treeUsage.Root = root.GetID()
+		}
+		for nodeID, node := range tree.GetNodes() {
+			if node == nil || node.GetQuota() == nil || node.GetAllocated() == nil {
+				continue
+			}
+			nodeUsage := &NodeUsage{
+				ID:        nodeID,
+				Hard:      node.IsHard(),
+				Leaf:      node.IsLeaf(),
+				Quota:     append([]int(nil), node.GetQuota().GetValue()...),
+				Allocated: append([]int(nil), node.GetAllocated().GetValue()...),
+			}
+			if parent := node.GetParent(); parent != nil {
+				nodeUsage.Parent = parent.GetID()
+			}
+			for _, consumer := range node.GetConsumers() {
+				nodeUsage.Consumers = append(nodeUsage.Consumers, consumer.GetID())
+			}
+			sort.Strings(nodeUsage.Consumers)
+			treeUsage.Nodes[nodeID] = nodeUsage
+		}
+		usage[treeName] = treeUsage
+	}
+	return usage
+}
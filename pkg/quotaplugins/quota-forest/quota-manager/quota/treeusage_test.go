@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota_test
+
+import (
+	"testing"
+
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/quotaplugins/quota-forest/quota-manager/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetTreeUsage : test that the usage is a copy of the state of the nodes of the trees
+func TestGetTreeUsage(t *testing.T) {
+	qm := quota.NewManager()
+	treeName := initializeQuotaManager(t, qm, testTreeSpec, testConsumersSpecMap, []string{"J-1", "J-2"})
+
+	usage := qm.GetTreeUsage()
+	assert.Len(t, usage, 1)
+	tree := usage[treeName]
+	assert.NotNil(t, tree)
+	assert.Equal(t, "A", tree.Root)
+	assert.Equal(t, []string{"cpu", "memory"}, tree.ResourceNames)
+	assert.Len(t, tree.Nodes, 3)
+
+	b := tree.Nodes["B"]
+	assert.Equal(t, "A", b.Parent)
+	assert.True(t, b.Leaf)
+	assert.Equal(t, []int{4, 64}, b.Quota)
+	assert.Equal(t, []int{4, 32}, b.Allocated)
+	assert.Equal(t, []string{"J-1"}, b.Consumers)
+	assert.Equal(t, []int{8, 64}, tree.Nodes["A"].Allocated)
+	assert.False(t, tree.Nodes["A"].Leaf)
+
+	path := tree.GetPathToRoot("C")
+	assert.Len(t, path, 2)
+	assert.Equal(t, "C", path[0].ID)
+	assert.Equal(t, "A", path[1].ID)
+	assert.Empty(t, tree.GetPathToRoot("D"))
+
+	// later allocations do not change the copy
+	assert.True(t, qm.DeAllocate(treeName, "J-1"))
+	assert.Equal(t, []int{4, 32}, b.Allocated)
+	assert.Equal(t, []int{0, 0}, qm.GetTreeUsage()[treeName].Nodes["B"].Allocated)
+}
@@ -417,3 +417,30 @@ func (qm *QuotaManager) Release(aw *arbv1.AppWrapper) bool {
 func (qm *QuotaManager) GetValidQuotaLabels() []string {
 	return qm.getQuotaTreeIDs()
 }
+
+// FitsInCluster evaluates the quota of an AppWrapper dispatched to an agent cluster, the REST quota manager
+// does not support limits per cluster
+func (qm *QuotaManager) FitsInCluster(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource, cluster string,
+	proposedPreemptions []*arbv1.AppWrapper) (bool, []*arbv1.AppWrapper, string) {
+	return qm.Fits(aw, awResDemands, nil, proposedPreemptions)
+}
+
+// UndoAllocate releases the quota of an AppWrapper, the REST quota manager does not preempt AppWrappers
+func (qm *QuotaManager) UndoAllocate(aw *arbv1.AppWrapper) bool {
+	return qm.Release(aw)
+}
+
+// GetQuotaHeadroom is not supported by the REST quota manager
+func (qm *QuotaManager) GetQuotaHeadroom() map[string]map[string]int64 {
+	return nil
+}
+
+// GetQuotaNodeUsage is not supported by the REST quota manager
+func (qm *QuotaManager) GetQuotaNodeUsage() []quota.QuotaNodeUsage {
+	return nil
+}
+
+// GetQuotaRejections is not supported by the REST quota manager
+func (qm *QuotaManager) GetQuotaRejections(aw *arbv1.AppWrapper, awResDemands *clusterstateapi.Resource) []arbv1.QuotaRejection {
+	return nil
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+// Report is the outcome of a simulation.  Times and durations are in seconds.
+type Report struct {
+	Summary     Summary             `json:"summary"`
+	Groups      []GroupStats        `json:"groups"`
+	Jobs        []JobResult         `json:"jobs"`
+	Utilisation []UtilisationSample `json:"utilisation"`
+}
+
+// Summary aggregates the statistics of all the jobs
+type Summary struct {
+	Jobs        int     `json:"jobs"`
+	Completed   int     `json:"completed"`
+	Unfinished  int     `json:"unfinished"`
+	Makespan    float64 `json:"makespan"`
+	MeanWait    float64 `json:"meanWait"`
+	MedianWait  float64 `json:"medianWait"`
+	P95Wait     float64 `json:"p95Wait"`
+	MaxWait     float64 `json:"maxWait"`
+	Preemptions int     `json:"preemptions"`
+	Backoffs    int     `json:"backoffs"`
+
+	// Mean utilisation of the cluster over the simulation, between 0 and 1
+	CPUUtilisation    float64 `json:"cpuUtilisation"`
+	MemoryUtilisation float64 `json:"memoryUtilisation"`
+	GPUUtilisation    float64 `json:"gpuUtilisation"`
+
+	// WaitFairness is Jain's fairness index of the mean slowdown of the groups, between 1/groups and 1
+	WaitFairness float64 `json:"waitFairness"`
+}
+
+// GroupStats are the statistics of the jobs of a group, by namespace or by fairness label
+type GroupStats struct {
+	Group        string  `json:"group"`
+	Jobs         int     `json:"jobs"`
+	Completed    int     `json:"completed"`
+	MeanWait     float64 `json:"meanWait"`
+	MeanSlowdown float64 `json:"meanSlowdown"`
+	Preemptions  int     `json:"preemptions"`
+	// GPUSeconds and CPUSeconds are the resources used by the jobs of the group
+	CPUSeconds float64 `json:"cpuSeconds"`
+	GPUSeconds float64 `json:"gpuSeconds"`
+}
+
+// JobResult is the outcome of a job of the trace
+type JobResult struct {
+	Name          string   `json:"name"`
+	Namespace     string   `json:"namespace"`
+	Group         string   `json:"group"`
+	SubmitTime    float64  `json:"submitTime"`
+	FirstDispatch *float64 `json:"firstDispatch,omitempty"`
+	Completion    *float64 `json:"completion,omitempty"`
+	// Wait is the time the job spent queued, including after preemptions
+	Wait        float64 `json:"wait"`
+	Preemptions int     `json:"preemptions"`
+	Backoffs    int     `json:"backoffs"`
+}
+
+// UtilisationSample is the fraction of the cluster resources used from a point in time until the next sample
+type UtilisationSample struct {
+	Time   float64 `json:"time"`
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	GPU    float64 `json:"gpu"`
+}
+
+// sample records the utilisation of the cluster when it changed
+func (s *Simulator) sample() {
+	used := s.usedResources()
+	sample := UtilisationSample{
+		Time:   s.now.Seconds(),
+		CPU:    fraction(used.MilliCPU, s.capacity.MilliCPU),
+		Memory: fraction(used.Memory, s.capacity.Memory),
+		GPU:    fraction(float64(used.GPU), float64(s.capacity.GPU)),
+	}
+	if n := len(s.samples); n > 0 {
+		last := s.samples[n-1]
+		if last.CPU == sample.CPU && last.Memory == sample.Memory && last.GPU == sample.GPU {
+			return
+		}
+		if last.Time == sample.Time {
+			s.samples[n-1] = sample
+			return
+		}
+	}
+	s.samples = append(s.samples, sample)
+}
+
+func fraction(used, capacity float64) float64 {
+	if capacity <= 0 {
+		return 0
+	}
+	return used / capacity
+}
+
+func (s *Simulator) group(job *simJob) string {
+	if len(s.scenario.FairnessLabel) > 0 {
+		if value, found := job.aw.Labels[s.scenario.FairnessLabel]; found {
+			return value
+		}
+		return defaultQuotaNode
+	}
+	return job.spec.Namespace
+}
+
+func (s *Simulator) report() *Report {
+	end := s.now
+	report := &Report{Utilisation: s.samples}
+
+	keys := make([]string, 0, len(s.jobs))
+	for key := range s.jobs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ji, jj := s.jobs[keys[i]], s.jobs[keys[j]]
+		if ji.spec.SubmitTime != jj.spec.SubmitTime {
+			return ji.spec.SubmitTime < jj.spec.SubmitTime
+		}
+		return keys[i] < keys[j]
+	})
+
+	groups := make(map[string]*GroupStats)
+	slowdowns := make(map[string][]float64)
+	var waits []float64
+	for _, key := range keys {
+		job := s.jobs[key]
+		wait := job.wait
+		if !job.running && !job.completed && job.queuedAt <= end {
+			// still queued at the end of the simulation
+			wait += end - job.queuedAt
+		}
+		result := JobResult{
+			Name:        job.spec.Name,
+			Namespace:   job.spec.Namespace,
+			Group:       s.group(job),
+			SubmitTime:  job.spec.SubmitTime,
+			Wait:        wait.Seconds(),
+			Preemptions: job.preemptions,
+			Backoffs:    job.backoffs,
+		}
+		if job.run > 0 {
+			result.FirstDispatch = secondsPtr(job.firstDispatch)
+		}
+		group := groups[result.Group]
+		if group == nil {
+			group = &GroupStats{Group: result.Group}
+			groups[result.Group] = group
+		}
+		group.Jobs++
+		group.Preemptions += job.preemptions
+		report.Summary.Jobs++
+		report.Summary.Preemptions += job.preemptions
+		report.Summary.Backoffs += job.backoffs
+		if job.completed {
+			result.Completion = secondsPtr(job.completion)
+			report.Summary.Completed++
+			report.Summary.Makespan = math.Max(report.Summary.Makespan, job.completion.Seconds())
+			waits = append(waits, result.Wait)
+			group.Completed++
+			group.MeanWait += result.Wait
+			duration := job.spec.Duration
+			group.CPUSeconds += job.demand.MilliCPU / 1000 * duration
+			group.GPUSeconds += float64(job.demand.GPU) * duration
+			if duration > 0 {
+				slowdowns[result.Group] = append(slowdowns[result.Group], (result.Wait+duration)/duration)
+			}
+		} else {
+			report.Summary.Unfinished++
+		}
+		report.Jobs = append(report.Jobs, result)
+	}
+
+	report.Summary.MeanWait = mean(waits)
+	report.Summary.MedianWait = percentile(waits, 0.5)
+	report.Summary.P95Wait = percentile(waits, 0.95)
+	for _, w := range waits {
+		report.Summary.MaxWait = math.Max(report.Summary.MaxWait, w)
+	}
+	report.Summary.CPUUtilisation, report.Summary.MemoryUtilisation, report.Summary.GPUUtilisation = meanUtilisation(s.samples, end.Seconds())
+
+	var groupSlowdowns []float64
+	for _, group := range groups {
+		if group.Completed > 0 {
+			group.MeanWait /= float64(group.Completed)
+		}
+		if len(slowdowns[group.Group]) > 0 {
+			group.MeanSlowdown = mean(slowdowns[group.Group])
+			groupSlowdowns = append(groupSlowdowns, group.MeanSlowdown)
+		}
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].Group < report.Groups[j].Group })
+	report.Summary.WaitFairness = jainIndex(groupSlowdowns)
+	return report
+}
+
+func secondsPtr(d time.Duration) *float64 {
+	s := d.Seconds()
+	return &s
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile returns the nearest-rank percentile of the values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// jainIndex returns Jain's fairness index of the values: 1 when all the values are equal, 1/n when a single
+// value is not zero
+func jainIndex(values []float64) float64 {
+	sum, squares := 0.0, 0.0
+	for _, v := range values {
+		sum += v
+		squares += v * v
+	}
+	if squares == 0 {
+		return 1
+	}
+	return sum * sum / (float64(len(values)) * squares)
+}
+
+// meanUtilisation returns the time-weighted mean utilisation of each resource until the end time
+func meanUtilisation(samples []UtilisationSample, end float64) (cpu, memory, gpu float64) {
+	if end <= 0 {
+		return 0, 0, 0
+	}
+	for i, sample := range samples {
+		next := end
+		if i+1 < len(samples) {
+			next = samples[i+1].Time
+		}
+		if next > end {
+			next = end
+		}
+		span := next - sample.Time
+		if span <= 0 {
+			continue
+		}
+		cpu += sample.CPU * span
+		memory += sample.Memory * span
+		gpu += sample.GPU * span
+	}
+	return cpu / end, memory / end, gpu / end
+}
+
+// WriteText writes the summary and the group statistics of a report in a human readable form
+func (r *Report) WriteText(out io.Writer) error {
+	s := r.Summary
+	fmt.Fprintf(out, "Jobs:         %d (%d completed, %d unfinished)\n", s.Jobs, s.Completed, s.Unfinished)
+	fmt.Fprintf(out, "Makespan:     %.0fs\n", s.Makespan)
+	fmt.Fprintf(out, "Wait:         mean %.1fs, median %.1fs, p95 %.1fs, max %.1fs\n", s.MeanWait, s.MedianWait, s.P95Wait, s.MaxWait)
+	fmt.Fprintf(out, "Preemptions:  %d\n", s.Preemptions)
+	fmt.Fprintf(out, "Backoffs:     %d\n", s.Backoffs)
+	fmt.Fprintf(out, "Utilisation:  cpu %.1f%%, memory %.1f%%, %s %.1f%%\n", 100*s.CPUUtilisation, 100*s.MemoryUtilisation,
+		clusterstateapi.GPUResourceName, 100*s.GPUUtilisation)
+	fmt.Fprintf(out, "Fairness:     %.3f (Jain's index of the mean slowdown of the groups)\n\n", s.WaitFairness)
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tJOBS\tCOMPLETED\tMEAN WAIT\tMEAN SLOWDOWN\tPREEMPTIONS\tCPU SECONDS\tGPU SECONDS")
+	for _, g := range r.Groups {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1fs\t%.2f\t%d\t%.0f\t%.0f\n", g.Group, g.Jobs, g.Completed, g.MeanWait, g.MeanSlowdown,
+			g.Preemptions, g.CPUSeconds, g.GPUSeconds)
+	}
+	return w.Flush()
+}
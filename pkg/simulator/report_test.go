@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestJainIndex(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(jainIndex([]float64{2, 2, 2})).To(gomega.Equal(float64(1)))
+	g.Expect(jainIndex([]float64{1, 0, 0, 0})).To(gomega.Equal(0.25))
+	g.Expect(jainIndex(nil)).To(gomega.Equal(float64(1)))
+}
+
+func TestPercentile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	values := []float64{5, 1, 4, 2, 3}
+	g.Expect(percentile(values, 0.5)).To(gomega.Equal(float64(3)))
+	g.Expect(percentile(values, 0.95)).To(gomega.Equal(float64(5)))
+	g.Expect(percentile(values, 0)).To(gomega.Equal(float64(1)))
+	g.Expect(percentile(nil, 0.5)).To(gomega.Equal(float64(0)))
+	// the values are not reordered
+	g.Expect(values).To(gomega.Equal([]float64{5, 1, 4, 2, 3}))
+}
+
+func TestMeanUtilisation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	samples := []UtilisationSample{{Time: 0, CPU: 1, GPU: 0.5}, {Time: 30, CPU: 0.5, GPU: 1}, {Time: 60, CPU: 0}}
+	cpu, memory, gpu := meanUtilisation(samples, 100)
+	g.Expect(cpu).To(gomega.BeNumerically("~", 0.45, 1e-9))
+	g.Expect(memory).To(gomega.Equal(float64(0)))
+	g.Expect(gpu).To(gomega.BeNumerically("~", 0.45, 1e-9))
+
+	// samples after the end are ignored
+	cpu, _, _ = meanUtilisation(samples, 20)
+	g.Expect(cpu).To(gomega.Equal(float64(1)))
+}
+
+func TestWriteText(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	report := &Report{
+		Summary: Summary{Jobs: 2, Completed: 1, Unfinished: 1, Makespan: 120, MeanWait: 10, Preemptions: 1, GPUUtilisation: 0.5, WaitFairness: 1},
+		Groups:  []GroupStats{{Group: "team-a", Jobs: 2, Completed: 1, MeanWait: 10, MeanSlowdown: 1.1, Preemptions: 1, GPUSeconds: 480}},
+	}
+	out := &bytes.Buffer{}
+	g.Expect(report.WriteText(out)).To(gomega.Succeed())
+	g.Expect(out.String()).To(gomega.ContainSubstring("Jobs:         2 (1 completed, 1 unfinished)"))
+	g.Expect(out.String()).To(gomega.ContainSubstring("nvidia.com/gpu 50.0%"))
+	g.Expect(out.String()).To(gomega.MatchRegexp(`team-a\s+2\s+1\s+10.0s\s+1.10\s+1\s+0\s+480`))
+}
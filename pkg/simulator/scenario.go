@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulator replays a trace of AppWrapper arrivals against a synthetic cluster with a virtual clock.
+// It drives the queue, ordering and quota forest code of the controller to evaluate scheduling policies offline.
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	qstv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/quotaplugins/quotasubtree/v1alpha1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+)
+
+// Scenario describes the policy and the synthetic cluster of a simulation
+type Scenario struct {
+	// Policy is the configuration of the controller under evaluation
+	Policy config.MCADConfiguration `json:"policy"`
+
+	// Nodes are the pools of identical nodes of the cluster
+	Nodes []NodePool `json:"nodes"`
+
+	// QuotaSubtrees define the quota trees, when quota management is enabled
+	QuotaSubtrees []qstv1.QuotaSubtree `json:"quotaSubtrees,omitempty"`
+
+	// FairnessLabel is the label of the jobs used to group them for the fairness statistics,
+	// e.g., the label of a quota tree.  Jobs are grouped by namespace when not set.
+	FairnessLabel string `json:"fairnessLabel,omitempty"`
+
+	// MaxTime bounds the simulated time in seconds.  Zero means no bound.
+	MaxTime float64 `json:"maxTime,omitempty"`
+}
+
+// NodePool is a number of nodes with the same allocatable resources
+type NodePool struct {
+	Name        string          `json:"name"`
+	Count       int32           `json:"count"`
+	Allocatable v1.ResourceList `json:"allocatable"`
+}
+
+// Job is a record of the arrival trace
+type Job struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// SubmitTime is the arrival time of the job in seconds since the start of the simulation
+	SubmitTime float64 `json:"submitTime"`
+
+	// Duration is the run time of the job in seconds once dispatched
+	Duration float64 `json:"duration"`
+
+	Priority      int32             `json:"priority,omitempty"`
+	PrioritySlope float64           `json:"prioritySlope,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+
+	// Replicas is the number of pods of the job, each requesting Requests.  It defaults to 1.
+	Replicas int             `json:"replicas,omitempty"`
+	Requests v1.ResourceList `json:"requests"`
+}
+
+// capacity returns the aggregated allocatable resources of the node pools
+func (s *Scenario) capacity() *clusterstateapi.Resource {
+	capacity := clusterstateapi.EmptyResource()
+	for _, pool := range s.Nodes {
+		node := clusterstateapi.NewResource(pool.Allocatable)
+		for i := int32(0); i < pool.Count; i++ {
+			capacity.Add(node)
+		}
+	}
+	return capacity
+}
+
+// ReadScenario reads a scenario in YAML or JSON
+func ReadScenario(r io.Reader) (*Scenario, error) {
+	scenario := &Scenario{}
+	if err := yaml.NewYAMLOrJSONDecoder(r, 4096).Decode(scenario); err != nil {
+		return nil, fmt.Errorf("failed to decode the scenario: %v", err)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.capacity().IsEmpty() {
+		return fmt.Errorf("the scenario has no node resources")
+	}
+	for _, pool := range s.Nodes {
+		if pool.Count < 0 {
+			return fmt.Errorf("node pool %s has a negative count", pool.Name)
+		}
+	}
+	if s.Policy.IsQuotaEnabled() && len(s.QuotaSubtrees) == 0 {
+		return fmt.Errorf("quota management is enabled but no quota subtrees are defined")
+	}
+	return nil
+}
+
+// ReadTrace reads an arrival trace of jobs, one JSON record per line, and returns the jobs sorted by submit time
+func ReadTrace(r io.Reader) ([]Job, error) {
+	var jobs []Job
+	names := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 || record[0] == '#' {
+			continue
+		}
+		job := Job{}
+		if err := json.Unmarshal(record, &job); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := job.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if names[job.key()] {
+			return nil, fmt.Errorf("line %d: duplicate job %s", line, job.key())
+		}
+		names[job.key()] = true
+		jobs = append(jobs, job)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].SubmitTime < jobs[j].SubmitTime })
+	return jobs, nil
+}
+
+func (j *Job) validate() error {
+	if len(j.Name) == 0 {
+		return fmt.Errorf("job without name")
+	}
+	if len(j.Namespace) == 0 {
+		j.Namespace = "default"
+	}
+	if j.Replicas == 0 {
+		j.Replicas = 1
+	}
+	if j.Replicas < 0 || j.SubmitTime < 0 || j.Duration < 0 {
+		return fmt.Errorf("job %s has a negative replicas, submit time or duration", j.key())
+	}
+	return nil
+}
+
+func (j *Job) key() string {
+	return j.Namespace + "/" + j.Name
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestReadTrace(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	trace := `# arrival trace
+{"name":"late","submitTime":60,"duration":10,"requests":{"cpu":"1"}}
+
+{"name":"early","namespace":"team-a","submitTime":0,"duration":30,"priority":5,"replicas":4,"labels":{"quota_context":"team-a"},"requests":{"cpu":"2","nvidia.com/gpu":"1"}}
+`
+	jobs, err := ReadTrace(strings.NewReader(trace))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(jobs).To(gomega.HaveLen(2))
+	g.Expect(jobs[0].Name).To(gomega.Equal("early"))
+	g.Expect(jobs[0].Replicas).To(gomega.Equal(4))
+	g.Expect(jobs[0].Labels).To(gomega.HaveKeyWithValue("quota_context", "team-a"))
+	g.Expect(jobs[1].Namespace).To(gomega.Equal("default"))
+	g.Expect(jobs[1].Replicas).To(gomega.Equal(1))
+
+	_, err = ReadTrace(strings.NewReader(`{"name":"a","duration":10}` + "\n" + `{"name":"a","duration":20}`))
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("line 2: duplicate job default/a")))
+	_, err = ReadTrace(strings.NewReader(`{"name":"a","duration":-1}`))
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = ReadTrace(strings.NewReader(`{"name":`))
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("line 1")))
+}
+
+func TestReadScenario(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scenario, err := ReadScenario(strings.NewReader(`
+policy:
+  backoffTime: 10
+  preemption: true
+nodes:
+- name: gpu
+  count: 2
+  allocatable:
+    cpu: "16"
+    memory: 64Gi
+    nvidia.com/gpu: "4"
+`))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(scenario.Policy.HasPreemption()).To(gomega.BeTrue())
+	g.Expect(scenario.Policy.BackoffTimeOrDefault(defaultBackoffTime)).To(gomega.Equal(int32(10)))
+	capacity := scenario.capacity()
+	g.Expect(capacity.MilliCPU).To(gomega.Equal(float64(32000)))
+	g.Expect(capacity.GPU).To(gomega.Equal(int64(8)))
+
+	_, err = ReadScenario(strings.NewReader(`{"nodes":[]}`))
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("no node resources")))
+	_, err = ReadScenario(strings.NewReader(`{"policy":{"quotaEnabled":true},"nodes":[{"name":"n","count":1,"allocatable":{"cpu":"1"}}]}`))
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("no quota subtrees")))
+}
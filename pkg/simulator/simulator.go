@@ -0,0 +1,480 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	arbv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/controller/v1beta1"
+	qstv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/quotaplugins/quotasubtree/v1alpha1"
+	listersv1beta1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/client/listers/controller/v1beta1"
+	clusterstateapi "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/clusterstate/api"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejob"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/queuejobresources/genericresource"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/controller/quota/quotaforestmanager"
+)
+
+// defaultBackoffTime is the default backoff time in seconds of the controller
+const defaultBackoffTime = 20
+
+// defaultQuotaNode is the quota node of the jobs without a label for a quota tree
+const defaultQuotaNode = "default"
+
+// epoch is the wall clock time of the start of the simulation
+var epoch = time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type eventKind int
+
+const (
+	eventArrival eventKind = iota
+	eventCompletion
+	eventBackoffExpired
+)
+
+type event struct {
+	at   time.Duration
+	seq  int
+	kind eventKind
+	job  *simJob
+	// run is the run of the job a completion belongs to, completions of preempted runs are ignored
+	run int
+}
+
+// eventHeap orders the events by time, then by the order in which they were scheduled
+type eventHeap []*event
+
+func (h eventHeap) Len() int { return len(h) }
+func (h eventHeap) Less(i, j int) bool {
+	if h[i].at != h[j].at {
+		return h[i].at < h[j].at
+	}
+	return h[i].seq < h[j].seq
+}
+func (h eventHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *eventHeap) Push(x interface{}) { *h = append(*h, x.(*event)) }
+func (h *eventHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// simJob is the state of a job of the trace during the simulation
+type simJob struct {
+	spec   Job
+	aw     *arbv1.AppWrapper
+	demand *clusterstateapi.Resource
+
+	queuedAt      time.Duration
+	firstDispatch time.Duration
+	completion    time.Duration
+	wait          time.Duration
+	running       bool
+	completed     bool
+	run           int
+	preemptions   int
+	backoffs      int
+}
+
+// Simulator replays a trace with the queue, ordering and quota forest code of the controller.
+// As in the controller, a single AppWrapper is evaluated at a time from the head of the queue, and the
+// evaluation of the queue is blocked for the backoff time when the head of line cannot be dispatched.
+type Simulator struct {
+	scenario     *Scenario
+	capacity     *clusterstateapi.Resource
+	backoffTime  time.Duration
+	preemption   bool
+	dynamic      bool
+	queue        *queuejob.PriorityQueue
+	indexer      cache.Indexer
+	quotaManager *quotaforestmanager.QuotaManager
+	quotaTrees   []string
+
+	now          time.Duration
+	events       eventHeap
+	seq          int
+	blockedUntil time.Duration
+	jobs         map[string]*simJob
+	running      map[string]*simJob
+	samples      []UtilisationSample
+	failedCycles int
+}
+
+// New creates a simulator for the specified scenario
+func New(scenario *Scenario) (*Simulator, error) {
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	s := &Simulator{
+		scenario:    scenario,
+		capacity:    scenario.capacity(),
+		backoffTime: time.Duration(scenario.Policy.BackoffTimeOrDefault(defaultBackoffTime)) * time.Second,
+		preemption:  scenario.Policy.HasPreemption(),
+		dynamic:     scenario.Policy.HasDynamicPriority(),
+		queue:       queuejob.NewPriorityQueue(),
+		indexer:     cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+		jobs:        make(map[string]*simJob),
+		running:     make(map[string]*simJob),
+	}
+	if scenario.Policy.IsQuotaEnabled() {
+		quotaSubtrees := make([]*qstv1.QuotaSubtree, len(scenario.QuotaSubtrees))
+		for i := range scenario.QuotaSubtrees {
+			quotaSubtrees[i] = &scenario.QuotaSubtrees[i]
+		}
+		qm, err := quotaforestmanager.NewOfflineQuotaManager(quotaSubtrees, listersv1beta1.NewAppWrapperLister(s.indexer), &scenario.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the quota manager: %v", err)
+		}
+		s.quotaManager = qm
+		s.quotaTrees = qm.GetValidQuotaLabels()
+		sort.Strings(s.quotaTrees)
+	}
+	return s, nil
+}
+
+// Run replays the jobs of a trace and returns the report of the simulation
+func (s *Simulator) Run(jobs []Job) (*Report, error) {
+	for i := range jobs {
+		job := &simJob{spec: jobs[i]}
+		if err := job.spec.validate(); err != nil {
+			return nil, err
+		}
+		if _, found := s.jobs[job.spec.key()]; found {
+			return nil, fmt.Errorf("duplicate job %s", job.spec.key())
+		}
+		job.aw = newAppWrapper(&job.spec, s.quotaTrees)
+		job.demand = aggregatedResources(job.aw)
+		s.jobs[job.spec.key()] = job
+		s.schedule(seconds(job.spec.SubmitTime), eventArrival, job)
+	}
+
+	maxTime := seconds(s.scenario.MaxTime)
+	for len(s.events) > 0 {
+		at := s.events[0].at
+		if maxTime > 0 && at > maxTime {
+			s.now = maxTime
+			break
+		}
+		s.now = at
+		for len(s.events) > 0 && s.events[0].at == at {
+			s.handle(heap.Pop(&s.events).(*event))
+		}
+		s.dispatch()
+		s.sample()
+		if s.stalled() {
+			klog.V(2).Infof("[Run] No queued AppWrapper can be dispatched anymore at %v, stopping the simulation.", s.now)
+			break
+		}
+	}
+	return s.report(), nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// newAppWrapper returns the AppWrapper of a job, with the default quota node for the trees it has no label for
+func newAppWrapper(job *Job, quotaTrees []string) *arbv1.AppWrapper {
+	labels := make(map[string]string)
+	for k, v := range job.Labels {
+		labels[k] = v
+	}
+	for _, tree := range quotaTrees {
+		if _, found := labels[tree]; !found {
+			labels[tree] = defaultQuotaNode
+		}
+	}
+	return &arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{Name: job.Name, Namespace: job.Namespace, Labels: labels},
+		Spec: arbv1.AppWrapperSpec{
+			Priority:      job.Priority,
+			PrioritySlope: job.PrioritySlope,
+			AggrResources: arbv1.AppWrapperResourceList{
+				GenericItems: []arbv1.AppWrapperGenericResource{{
+					GenericTemplate:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Pod"}`)},
+					CustomPodResources: []arbv1.CustomPodResourceTemplate{{Replicas: job.Replicas, Requests: job.Requests}},
+				}},
+			},
+		},
+	}
+}
+
+// aggregatedResources returns the demand of the AppWrapper of a job. The requests of a job are explicit custom
+// pod resources, so no LimitRange lister is set: LimitRange defaults never apply to the jobs of a trace.
+func aggregatedResources(aw *arbv1.AppWrapper) *clusterstateapi.Resource {
+	total := clusterstateapi.EmptyResource()
+	for i := range aw.Spec.AggrResources.GenericItems {
+		res, err := genericresource.GetResources(&aw.Spec.AggrResources.GenericItems[i], aw.Namespace)
+		if err != nil {
+			klog.Errorf("[aggregatedResources] Failure aggregating resources for %s/%s, err=%v", aw.Namespace, aw.Name, err)
+			continue
+		}
+		total.Add(res)
+	}
+	return total
+}
+
+func (s *Simulator) schedule(at time.Duration, kind eventKind, job *simJob) {
+	s.seq++
+	heap.Push(&s.events, &event{at: at, seq: s.seq, kind: kind, job: job, run: job.run})
+}
+
+func (s *Simulator) handle(e *event) {
+	job := e.job
+	switch e.kind {
+	case eventArrival:
+		job.aw.Status.ControllerFirstTimestamp = metav1.NewMicroTime(epoch.Add(s.now))
+		job.aw.Status.SystemPriority = float64(job.spec.Priority)
+		job.aw.Status.State = arbv1.AppWrapperStateEnqueued
+		if err := s.indexer.Add(job.aw); err != nil {
+			klog.Errorf("[handle] Failed to add AppWrapper %s to the cache, err=%v", job.spec.key(), err)
+		}
+		job.queuedAt = s.now
+		s.queue.AddIfNotPresent(job.aw)
+		s.failedCycles = 0
+	case eventCompletion:
+		if !job.running || e.run != job.run {
+			return
+		}
+		s.stop(job)
+		job.completed = true
+		job.completion = s.now
+		job.aw.Status.State = arbv1.AppWrapperStateCompleted
+		s.failedCycles = 0
+	case eventBackoffExpired:
+		s.queue.MoveToActiveQueueIfExists(job.aw)
+	}
+}
+
+// stop releases the resources and the quota of a running job
+func (s *Simulator) stop(job *simJob) {
+	job.running = false
+	job.aw.Status.CanRun = false
+	delete(s.running, job.spec.key())
+	if s.quotaManager != nil {
+		s.quotaManager.Release(job.aw)
+	}
+}
+
+// dispatch evaluates the AppWrappers at the head of the queue until one must back off
+func (s *Simulator) dispatch() {
+	for s.now >= s.blockedUntil && s.queue.Length() > 0 {
+		if s.dynamic {
+			s.updateSystemPriorities()
+		}
+		aw, err := s.queue.Pop()
+		if err != nil {
+			klog.Errorf("[dispatch] Failed to pop the queue, err=%v", err)
+			return
+		}
+		job := s.jobs[aw.Namespace+"/"+aw.Name]
+		if s.tryDispatch(job) {
+			continue
+		}
+		job.backoffs++
+		s.failedCycles++
+		s.queue.AddUnschedulableIfNotPresent(aw)
+		s.blockedUntil = s.now + s.backoffTime
+		s.schedule(s.blockedUntil, eventBackoffExpired, job)
+		return
+	}
+}
+
+// updateSystemPriorities recomputes the system priorities of the queued AppWrappers for dynamic priority
+func (s *Simulator) updateSystemPriorities() {
+	var aws []*arbv1.AppWrapper
+	for s.queue.Length() > 0 {
+		aw, _ := s.queue.Pop()
+		aws = append(aws, aw)
+	}
+	for _, aw := range aws {
+		aw.Status.SystemPriority = float64(aw.Spec.Priority) + aw.Spec.PrioritySlope*epoch.Add(s.now).Sub(aw.Status.ControllerFirstTimestamp.Time).Seconds()
+		s.queue.AddIfNotPresent(aw)
+	}
+}
+
+// tryDispatch dispatches a job if it fits in the free resources and quota, preempting jobs as needed
+func (s *Simulator) tryDispatch(job *simJob) bool {
+	free := s.freeResources()
+
+	// Running jobs of lower priority are preemptable, as in the controller preemption is disabled
+	// under dynamic priority
+	targetPriority := -math.MaxFloat64
+	if s.preemption && !s.dynamic {
+		targetPriority = job.aw.Status.SystemPriority
+	}
+	available := free.Clone()
+	var preemptable []*simJob
+	for _, running := range s.sortedRunning() {
+		if running.aw.Status.SystemPriority < targetPriority {
+			available.Add(running.demand)
+			preemptable = append(preemptable, running)
+		}
+	}
+	proposed := proposedPreemptions(job.demand, free, preemptable)
+
+	var victims []*simJob
+	if s.quotaManager != nil {
+		var proposedAWs []*arbv1.AppWrapper
+		for _, p := range proposed {
+			proposedAWs = append(proposedAWs, p.aw)
+		}
+		fits, preemptAWs, msg := s.quotaManager.Fits(job.aw, job.demand, available.Clone(), proposedAWs)
+		if !fits {
+			klog.V(4).Infof("[tryDispatch] AppWrapper %s does not fit in quota at %v: %s", job.spec.key(), s.now, msg)
+			return false
+		}
+		for _, aw := range preemptAWs {
+			if victim := s.running[aw.Namespace+"/"+aw.Name]; victim != nil {
+				victims = append(victims, victim)
+			}
+		}
+	} else if !job.demand.LessEqual(available) {
+		return false
+	}
+
+	// Preempt the lower priority jobs, as the scheduler would, when the quota preemptions do not free enough resources
+	released := free.Clone()
+	for _, victim := range victims {
+		released.Add(victim.demand)
+	}
+	for _, p := range proposed {
+		if job.demand.LessEqual(released) {
+			break
+		}
+		if !containsJob(victims, p) {
+			victims = append(victims, p)
+			released.Add(p.demand)
+		}
+	}
+	if !job.demand.LessEqual(released) {
+		// the AppWrappers the quota allocation preempted keep running, they get their quota back
+		if s.quotaManager != nil {
+			s.quotaManager.UndoAllocate(job.aw)
+		}
+		return false
+	}
+
+	for _, victim := range victims {
+		s.preempt(victim)
+	}
+	s.start(job)
+	return true
+}
+
+func containsJob(jobs []*simJob, job *simJob) bool {
+	for _, j := range jobs {
+		if j == job {
+			return true
+		}
+	}
+	return false
+}
+
+// proposedPreemptions returns the preemptable jobs, lowest priority first, needed to dispatch a demand that
+// does not fit in the free resources, following the selection of the controller
+func proposedPreemptions(demand *clusterstateapi.Resource, free *clusterstateapi.Resource, preemptable []*simJob) []*simJob {
+	if demand.LessEqual(free) || len(preemptable) == 0 {
+		return nil
+	}
+	candidates := append([]*simJob(nil), preemptable...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].aw.Status.SystemPriority < candidates[j].aw.Status.SystemPriority
+	})
+	var proposed []*simJob
+	released := clusterstateapi.EmptyResource()
+	for _, candidate := range candidates {
+		released.Add(candidate.demand)
+		proposed = append(proposed, candidate)
+		if demand.LessEqual(released) {
+			break
+		}
+	}
+	return proposed
+}
+
+func (s *Simulator) preempt(job *simJob) {
+	klog.V(4).Infof("[preempt] Preempting AppWrapper %s at %v.", job.spec.key(), s.now)
+	s.stop(job)
+	job.preemptions++
+	job.queuedAt = s.now
+	job.aw.Status.State = arbv1.AppWrapperStateEnqueued
+	s.queue.AddIfNotPresent(job.aw)
+}
+
+func (s *Simulator) start(job *simJob) {
+	klog.V(4).Infof("[start] Dispatching AppWrapper %s at %v.", job.spec.key(), s.now)
+	if job.run == 0 {
+		job.firstDispatch = s.now
+	}
+	job.wait += s.now - job.queuedAt
+	job.run++
+	job.running = true
+	job.aw.Status.CanRun = true
+	job.aw.Status.State = arbv1.AppWrapperStateActive
+	s.running[job.spec.key()] = job
+	s.failedCycles = 0
+	s.schedule(s.now+seconds(job.spec.Duration), eventCompletion, job)
+}
+
+// freeResources returns the capacity of the cluster not used by the running jobs
+func (s *Simulator) freeResources() *clusterstateapi.Resource {
+	free := s.capacity.Clone()
+	free.NonNegSub(s.usedResources())
+	return free
+}
+
+func (s *Simulator) usedResources() *clusterstateapi.Resource {
+	used := clusterstateapi.EmptyResource()
+	for _, job := range s.running {
+		used.Add(job.demand)
+	}
+	return used
+}
+
+// sortedRunning returns the running jobs in a deterministic order
+func (s *Simulator) sortedRunning() []*simJob {
+	jobs := make([]*simJob, 0, len(s.running))
+	for _, job := range s.running {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].spec.key() < jobs[j].spec.key() })
+	return jobs
+}
+
+// stalled returns true when nothing can change the state of the simulation anymore: no job is running or
+// about to arrive, and every queued job failed to dispatch since the last change
+func (s *Simulator) stalled() bool {
+	if len(s.running) > 0 || s.queue.Length()+s.queue.UnschedulableLength() == 0 {
+		return false
+	}
+	for _, e := range s.events {
+		if e.kind == eventArrival {
+			return false
+		}
+	}
+	return s.failedCycles > s.queue.Length()+s.queue.UnschedulableLength()
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 The Multi-Cluster App Dispatcher Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	qstv1 "github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/apis/quotaplugins/quotasubtree/v1alpha1"
+	"github.com/project-codeflare/multi-cluster-app-dispatcher/pkg/config"
+)
+
+func gpuScenario(policy config.MCADConfiguration) *Scenario {
+	return &Scenario{
+		Policy: policy,
+		Nodes: []NodePool{{Name: "gpu", Count: 2, Allocatable: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("16"),
+			v1.ResourceMemory: resource.MustParse("64Gi"),
+			"nvidia.com/gpu":  resource.MustParse("4"),
+		}}},
+	}
+}
+
+func gpuJob(name string, submit, duration float64, gpus string, priority int32, labels map[string]string) Job {
+	return Job{Name: name, Namespace: "default", SubmitTime: submit, Duration: duration, Priority: priority, Labels: labels,
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), "nvidia.com/gpu": resource.MustParse(gpus)}}
+}
+
+func jobResult(report *Report, name string) JobResult {
+	for _, job := range report.Jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return JobResult{}
+}
+
+func runSimulation(g *gomega.WithT, scenario *Scenario, jobs ...Job) *Report {
+	sim, err := New(scenario)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	report, err := sim.Run(jobs)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return report
+}
+
+func TestRunBackoff(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	report := runSimulation(g, gpuScenario(config.MCADConfiguration{BackoffTime: pointer.Int32(20)}),
+		gpuJob("first", 0, 100, "8", 0, nil),
+		gpuJob("second", 10, 50, "8", 0, nil))
+
+	// the second job is retried every 20s after its arrival, it is dispatched at 110s
+	second := jobResult(report, "second")
+	g.Expect(second.Wait).To(gomega.Equal(float64(100)))
+	g.Expect(*second.FirstDispatch).To(gomega.Equal(float64(110)))
+	g.Expect(second.Backoffs).To(gomega.Equal(5))
+	g.Expect(report.Summary.Completed).To(gomega.Equal(2))
+	g.Expect(report.Summary.Makespan).To(gomega.Equal(float64(160)))
+	g.Expect(report.Summary.GPUUtilisation).To(gomega.BeNumerically("~", 150.0/160.0, 1e-9))
+	g.Expect(report.Utilisation).To(gomega.Equal([]UtilisationSample{
+		{Time: 0, CPU: 1.0 / 32, Memory: 0, GPU: 1},
+		{Time: 100, CPU: 0, Memory: 0, GPU: 0},
+		{Time: 110, CPU: 1.0 / 32, Memory: 0, GPU: 1},
+		{Time: 160, CPU: 0, Memory: 0, GPU: 0},
+	}))
+}
+
+func TestRunPriorityPreemption(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	jobs := []Job{gpuJob("low", 0, 100, "8", 1, nil), gpuJob("high", 10, 50, "4", 10, nil)}
+
+	report := runSimulation(g, gpuScenario(config.MCADConfiguration{Preemption: pointer.Bool(true)}), jobs...)
+	g.Expect(jobResult(report, "high").Wait).To(gomega.Equal(float64(0)))
+	low := jobResult(report, "low")
+	g.Expect(low.Preemptions).To(gomega.Equal(1))
+	// the low priority job is requeued at 10s, backs off and runs again from 70s
+	g.Expect(*low.Completion).To(gomega.Equal(float64(170)))
+	g.Expect(low.Wait).To(gomega.Equal(float64(60)))
+	g.Expect(report.Summary.Preemptions).To(gomega.Equal(1))
+
+	// without preemption, the high priority job waits
+	report = runSimulation(g, gpuScenario(config.MCADConfiguration{}), jobs...)
+	g.Expect(report.Summary.Preemptions).To(gomega.Equal(0))
+	g.Expect(jobResult(report, "high").Wait).To(gomega.BeNumerically(">=", 90))
+}
+
+func TestRunQuota(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	quota := func(gpus string, hard bool) qstv1.Quota {
+		return qstv1.Quota{HardLimit: hard, Requests: qstv1.ResourceList{
+			"cpu":            resource.MustParse("32"),
+			"memory":         resource.MustParse("128Gi"),
+			"nvidia.com/gpu": resource.MustParse(gpus),
+		}}
+	}
+	treeLabels := map[string]string{"tree": "quota_context"}
+	scenario := gpuScenario(config.MCADConfiguration{QuotaEnabled: pointer.Bool(true), Preemption: pointer.Bool(true)})
+	scenario.FairnessLabel = "quota_context"
+	scenario.QuotaSubtrees = []qstv1.QuotaSubtree{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root", Namespace: "kube-system", Labels: treeLabels},
+			Spec:       qstv1.QuotaSubtreeSpec{Children: []qstv1.Child{{Name: "context-root", Quotas: quota("8", false)}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "context-root-children", Namespace: "kube-system", Labels: treeLabels},
+			Spec: qstv1.QuotaSubtreeSpec{Parent: "context-root", Children: []qstv1.Child{
+				{Name: "team-a", Quotas: quota("4", false)},
+				{Name: "team-b", Quotas: quota("4", false)},
+				{Name: "default", Quotas: quota("0", false)},
+			}},
+		},
+	}
+	teamA := map[string]string{"quota_context": "team-a"}
+	teamB := map[string]string{"quota_context": "team-b"}
+
+	report := runSimulation(g, scenario,
+		gpuJob("a1", 0, 600, "4", 0, teamA),
+		gpuJob("a2", 10, 600, "4", 0, teamA),
+		gpuJob("b1", 30, 300, "4", 0, teamB))
+
+	// a2 borrows the quota of team-b and is preempted when b1 claims it
+	a2 := jobResult(report, "a2")
+	g.Expect(a2.Preemptions).To(gomega.Equal(1))
+	g.Expect(*a2.FirstDispatch).To(gomega.Equal(float64(10)))
+	g.Expect(jobResult(report, "b1").Wait).To(gomega.Equal(float64(0)))
+	g.Expect(report.Summary.Completed).To(gomega.Equal(3))
+	g.Expect(report.Groups).To(gomega.HaveLen(2))
+	g.Expect(report.Groups[0].Group).To(gomega.Equal("team-a"))
+	g.Expect(report.Groups[0].Preemptions).To(gomega.Equal(1))
+	g.Expect(report.Groups[1].GPUSeconds).To(gomega.Equal(float64(1200)))
+}
+
+func TestRunStalled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	report := runSimulation(g, gpuScenario(config.MCADConfiguration{}),
+		gpuJob("small", 0, 100, "4", 0, nil),
+		gpuJob("too-big", 5, 100, "16", 0, nil))
+
+	// the job larger than the cluster never runs, the simulation stops once nothing can change
+	g.Expect(report.Summary.Completed).To(gomega.Equal(1))
+	g.Expect(report.Summary.Unfinished).To(gomega.Equal(1))
+	tooBig := jobResult(report, "too-big")
+	g.Expect(tooBig.FirstDispatch).To(gomega.BeNil())
+	g.Expect(tooBig.Wait).To(gomega.BeNumerically(">", 95))
+}
+
+func TestRunMaxTime(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scenario := gpuScenario(config.MCADConfiguration{})
+	scenario.MaxTime = 50
+	report := runSimulation(g, scenario, gpuJob("long", 0, 100, "4", 0, nil), gpuJob("later", 60, 10, "4", 0, nil))
+	g.Expect(report.Summary.Completed).To(gomega.Equal(0))
+	g.Expect(report.Summary.Unfinished).To(gomega.Equal(2))
+	g.Expect(report.Summary.GPUUtilisation).To(gomega.BeNumerically("~", 0.5, 1e-9))
+}
@@ -572,6 +572,35 @@ var _ = Describe("AppWrapper E2E Test", func() {
 		fmt.Fprintf(os.Stdout, "[e2e] MCAD Job Completion Test - Completed.\n")
 	})
 
+	It("MCAD Job Failure Test", func() {
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Job Failure Test - Started.\n")
+		context := initTestContext()
+		var appwrappers []*arbv1.AppWrapper
+		appwrappersPtr := &appwrappers
+		defer cleanupTestObjectsPtr(context, appwrappersPtr)
+
+		aw := createGenericJobAWWithFailureStatus(context, "aw-test-job-with-failure-1")
+		appwrappers = append(appwrappers, aw)
+		Eventually(AppWrapper(context, aw.Namespace, aw.Name), 2*time.Minute).Should(WithTransform(AppWrapperState, Equal(arbv1.AppWrapperStateFailed)))
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Job Failure Test - Completed.\n")
+	})
+
+	It("MCAD Job Restart Test", func() {
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Job Restart Test - Started.\n")
+		context := initTestContext()
+		var appwrappers []*arbv1.AppWrapper
+		appwrappersPtr := &appwrappers
+		defer cleanupTestObjectsPtr(context, appwrappersPtr)
+
+		aw := createGenericJobAWWithRestartPolicy(context, "aw-test-job-with-restart-1", arbv1.RestartPolicy{MaxRestarts: 1, BackoffInSeconds: 5})
+		appwrappers = append(appwrappers, aw)
+		Eventually(AppWrapper(context, aw.Namespace, aw.Name), 4*time.Minute).Should(WithTransform(AppWrapperState, Equal(arbv1.AppWrapperStateFailed)))
+		aw, err := context.karclient.WorkloadV1beta1().AppWrappers(aw.Namespace).Get(context.ctx, aw.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aw.Status.NumberOfRestarts).To(Equal(1))
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Job Restart Test - Completed.\n")
+	})
+
 	It("MCAD Multi-Item Job Completion Test", func() {
 		fmt.Fprintf(os.Stdout, "[e2e] MCAD Multi-Item Job Completion Test - Started.\n")
 		context := initTestContext()
@@ -678,6 +707,26 @@ var _ = Describe("AppWrapper E2E Test", func() {
 		fmt.Fprintf(os.Stdout, "[e2e] MCAD Deployment RuningHoldCompletion Test - Completed. Awaiting app wrapper cleanup.\n")
 	})
 
+	It("MCAD Staged Deployment Test", func() {
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Staged Deployment Test - Started.\n")
+		context := initTestContext()
+		var appwrappers []*arbv1.AppWrapper
+		appwrappersPtr := &appwrappers
+		defer cleanupTestObjectsPtr(context, appwrappersPtr)
+
+		aw := createGenericDeploymentAWWithStages(context, appendRandomString("aw-deployment-stages"))
+		appwrappers = append(appwrappers, aw)
+		err1 := waitAWPodsReady(context, aw)
+		Expect(err1).NotTo(HaveOccurred(), "Expecting pods to be ready for app wrapper: %s", aw.Name)
+		Eventually(AppWrapper(context, aw.Namespace, aw.Name), 2*time.Minute).Should(WithTransform(func(aw *arbv1.AppWrapper) int {
+			return aw.Status.CurrentStage
+		}, Equal(1)))
+		aw, err := context.karclient.WorkloadV1beta1().AppWrappers(aw.Namespace).Get(context.ctx, aw.Name, metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aw.Status.Items).To(HaveLen(2))
+		fmt.Fprintf(os.Stdout, "[e2e] MCAD Staged Deployment Test - Completed.\n")
+	})
+
 	It("MCAD Service no RunningHoldCompletion or Complete Test", func() {
 		fmt.Fprintf(os.Stdout, "[e2e] MCAD Service no RunningHoldCompletion or Complete Test - Started.\n")
 		context := initTestContext()
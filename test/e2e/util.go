@@ -1172,6 +1172,88 @@ func createGenericJobAWWithStatus(context *context, name string) *arbv1.AppWrapp
 	return appwrapper
 }
 
+func createGenericJobAWWithFailureStatus(context *context, name string) *arbv1.AppWrapper {
+	return createGenericJobAWWithRestartPolicy(context, name, arbv1.RestartPolicy{})
+}
+
+func createGenericJobAWWithRestartPolicy(context *context, name string, restartPolicy arbv1.RestartPolicy) *arbv1.AppWrapper {
+	rb := []byte(fmt.Sprintf(`{
+		"apiVersion": "batch/v1",
+		"kind": "Job",
+		"metadata": {
+			"name": "%[1]s",
+			"namespace": "test"
+		},
+		"spec": {
+			"backoffLimit": 0,
+			"completions": 1,
+			"parallelism": 1,
+			"template": {
+				"metadata": {
+					"labels": {
+						"appwrapper.mcad.ibm.com": "%[1]s"
+					}
+				},
+				"spec": {
+					"containers": [
+						{
+							"args": [
+								"sleep 5; exit 1"
+							],
+							"command": [
+								"/bin/bash",
+								"-c",
+								"--"
+							],
+							"image": "ubuntu:latest",
+							"imagePullPolicy": "IfNotPresent",
+							"name": "%[1]s",
+							"resources": {
+								"limits": {
+									"cpu": "100m",
+									"memory": "256M"
+								},
+								"requests": {
+									"cpu": "100m",
+									"memory": "256M"
+								}
+							}
+						}
+					],
+					"restartPolicy": "Never"
+				}
+			}
+		}
+	}`, name))
+
+	aw := &arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: arbv1.AppWrapperSpec{
+			AggrResources: arbv1.AppWrapperResourceList{
+				GenericItems: []arbv1.AppWrapperGenericResource{
+					{
+						DesiredAvailable: 1,
+						GenericTemplate: runtime.RawExtension{
+							Raw: rb,
+						},
+						CompletionStatus: "Complete",
+						FailureStatus:    "Failed",
+					},
+				},
+			},
+			RestartPolicy: restartPolicy,
+		},
+	}
+
+	appwrapper, err := context.karclient.WorkloadV1beta1().AppWrappers(context.namespace).Create(context.ctx, aw, metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return appwrapper
+}
+
 func createGenericJobAWWithMultipleStatus(context *context, name string) *arbv1.AppWrapper {
 	rb := []byte(`{
 		"apiVersion": "batch/v1",
@@ -1708,6 +1790,87 @@ func createGenericDeploymentAWWithMultipleItems(context *context, name string) *
 	return appwrapper
 }
 
+func createGenericDeploymentAWWithStages(context *context, name string) *arbv1.AppWrapper {
+	deployment := func(deploymentName string) []byte {
+		return []byte(`{"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {
+			"name": "` + deploymentName + `",
+			"namespace": "test",
+			"labels": {
+				"app": "` + deploymentName + `"
+			}
+		},
+		"spec": {
+			"replicas": 1,
+			"selector": {
+				"matchLabels": {
+					"app": "` + deploymentName + `"
+				}
+			},
+			"template": {
+				"metadata": {
+					"labels": {
+						"app": "` + deploymentName + `"
+					},
+					"annotations": {
+						"appwrapper.mcad.ibm.com/appwrapper-name": "` + name + `"
+					}
+				},
+				"spec": {
+					"containers": [
+						{
+							"name": "` + deploymentName + `",
+							"image": "kicbase/echo-server:1.0",
+							"ports": [
+								{
+									"containerPort": 80
+								}
+							]
+						}
+					]
+				}
+			}
+		}} `)
+	}
+
+	var readyReplicas int32 = 1
+
+	aw := &arbv1.AppWrapper{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: arbv1.AppWrapperSpec{
+			AggrResources: arbv1.AppWrapperResourceList{
+				GenericItems: []arbv1.AppWrapperGenericResource{
+					{
+						DesiredAvailable: 1,
+						GenericTemplate: runtime.RawExtension{
+							Raw: deployment(name + "-head"),
+						},
+						Readiness: &arbv1.ItemReadiness{
+							ReadyReplicas: &readyReplicas,
+						},
+					},
+					{
+						DesiredAvailable: 1,
+						GenericTemplate: runtime.RawExtension{
+							Raw: deployment(name + "-worker"),
+						},
+						Stage: 1,
+					},
+				},
+			},
+		},
+	}
+
+	appwrapper, err := context.karclient.WorkloadV1beta1().AppWrappers(context.namespace).Create(context.ctx, aw, metav1.CreateOptions{})
+	Expect(err).NotTo(HaveOccurred())
+
+	return appwrapper
+}
+
 func createGenericDeploymentWithCPUAW(context *context, name string, cpuDemand string, replicas int) *arbv1.AppWrapper {
 	rb := []byte(fmt.Sprintf(`{
 	"apiVersion": "apps/v1",